@@ -2,15 +2,23 @@ package main
 
 import (
 	"log"
+	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 
 	_ "github.com/gocart-v2/cart-service/docs"
+	"github.com/gocart-v2/cart-service/internal/client"
 	"github.com/gocart-v2/cart-service/internal/handler"
 	"github.com/gocart-v2/cart-service/internal/repository"
 	"github.com/gocart-v2/cart-service/internal/router"
 	"github.com/gocart-v2/cart-service/internal/service"
+	"github.com/gocart-v2/cart-service/internal/shipping"
+	"github.com/gocart-v2/cart-service/internal/tax"
+	"github.com/gocart-v2/shared/currency"
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/shared/webhook"
 )
 
 // @title E-commerce API
@@ -27,18 +35,43 @@ import (
 // @securityDefinitions.bearer BearerAuth
 // @tag.name Shopping Cart
 // @tag.description Shopping cart operations
+// @tag.name Order
+// @tag.description Order operations
+// @tag.name Webhook
+// @tag.description Signed outbound event notifications
 func main() {
 
 	rh := handler.NewRootHandler()
 
+	pc := client.NewProductClient(getEnv("PRODUCT_SERVICE_URL", "http://product-service:8080"))
+	wc := client.NewWarehouseClient(getEnv("WAREHOUSE_SERVICE_URL", "http://warehouse-service:8082"))
+	pyc := client.NewPaymentClient(getEnv("PAYMENT_SERVICE_URL", "http://payment-service:8083"))
+	tc := tax.NewTableCalculator(tax.DefaultRules)
+	rt := shipping.NewRateTable(shipping.DefaultZones, shipping.DefaultServices)
+	pk := shipping.NewPacker(shipping.DefaultBoxes)
+
+	xr, err := currency.NewRateTable(getEnv("EXCHANGE_RATES_FILE", "config/exchange_rates.json"))
+	if err != nil {
+		log.Fatal("Failed to load exchange rates:", err)
+	}
+	go xr.Run(15 * time.Minute)
+
+	wd := webhook.NewDispatcher([]string{model.EventOrderPlaced}, webhook.DefaultRetryPolicy)
+	wh := handler.NewWebhookHandler(wd)
+	go wd.Run(10 * time.Second)
+
 	cr := repository.NewCartRepository()
-	cs := service.NewCartService(cr)
+	or := repository.NewOrderRepository()
+	cs := service.NewCartService(cr, or, pc, wc, pyc, tc, rt, pk, xr, wd)
 	ch := handler.NewCartHandler(cs)
+	oh := handler.NewOrderHandler(cs)
 
 	e := gin.Default()
 	router.SetupRoutes(e, &router.AllHandlers{
 		RootHandler:    rh,
 		CartHandler:    ch,
+		OrderHandler:   oh,
+		WebhookHandler: wh,
 		SwaggerHandler: swaggerFiles.Handler,
 	})
 
@@ -47,3 +80,11 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// getEnv returns the value of an environment variable, or fallback if it is unset
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrPaymentRejected = errors.New("payment request was rejected")
+)
+
+// PaymentClient talks to payment-service over HTTP
+type PaymentClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewPaymentClient(baseURL string) *PaymentClient {
+	return &PaymentClient{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Authorize places a hold on a card or gift card. A card the gateway or
+// fraud screening refuses, or a gift card with too low a balance, comes back
+// as a payment with the failed status rather than an error, and one held for
+// review comes back as pending_review.
+func (c *PaymentClient) Authorize(req *model.AuthorizePaymentRequest) (*model.Payment, error) {
+	var payment model.Payment
+	if err := c.post("/v1/payment", req, &payment); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// Get retrieves a payment
+func (c *PaymentClient) Get(paymentID int) (*model.Payment, error) {
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s/v1/payment/%d", c.baseURL, paymentID))
+	if err != nil {
+		return nil, fmt.Errorf("payment service unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("payment service returned status %d", resp.StatusCode)
+	}
+
+	var payment model.Payment
+	if err := json.NewDecoder(resp.Body).Decode(&payment); err != nil {
+		return nil, fmt.Errorf("failed to decode payment: %w", err)
+	}
+	return &payment, nil
+}
+
+// Void releases the hold of an authorized payment
+func (c *PaymentClient) Void(paymentID int) error {
+	return c.post(fmt.Sprintf("/v1/payment/%d/void", paymentID), nil, nil)
+}
+
+// CancelReview fails a payment held for fraud review
+func (c *PaymentClient) CancelReview(paymentID int) error {
+	return c.post(fmt.Sprintf("/v1/fraud/reviews/%d/cancel", paymentID), nil, nil)
+}
+
+// post sends body as JSON and decodes a successful response into out
+func (c *PaymentClient) post(path string, body any, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Post(c.baseURL+path, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("payment service unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr model.Error
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if resp.StatusCode < 500 {
+			return fmt.Errorf("%w: %s", ErrPaymentRejected, apiErr.Message)
+		}
+		return fmt.Errorf("payment service returned status %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode payment response: %w", err)
+	}
+	return nil
+}
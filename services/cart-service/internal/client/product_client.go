@@ -0,0 +1,51 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrProductNotFound = errors.New("product not found")
+)
+
+// ProductClient talks to product-service over HTTP
+type ProductClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewProductClient(baseURL string) *ProductClient {
+	return &ProductClient{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// GetProduct retrieves a product by its ID
+func (c *ProductClient) GetProduct(productID int) (*model.Product, error) {
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s/v1/product/%d", c.baseURL, productID))
+	if err != nil {
+		return nil, fmt.Errorf("product service unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrProductNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("product service returned status %d", resp.StatusCode)
+	}
+
+	var product model.Product
+	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
+		return nil, fmt.Errorf("failed to decode product: %w", err)
+	}
+
+	return &product, nil
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrReservationFailed = errors.New("reservation could not be completed")
+)
+
+// WarehouseClient talks to warehouse-service over HTTP
+type WarehouseClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewWarehouseClient(baseURL string) *WarehouseClient {
+	return &WarehouseClient{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Reserve holds stock for every line of an order, or none of them
+func (c *WarehouseClient) Reserve(req *model.ReserveRequest) (*model.Reservation, error) {
+	var reservation model.Reservation
+	if err := c.post("/v1/warehouse/reservations", req, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ConfirmReservation commits held stock to an order
+func (c *WarehouseClient) ConfirmReservation(reservationID int) (*model.Reservation, error) {
+	var reservation model.Reservation
+	if err := c.post(fmt.Sprintf("/v1/warehouse/reservations/%d/confirm", reservationID), nil, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ReleaseReservation returns held stock to the available pool
+func (c *WarehouseClient) ReleaseReservation(reservationID int) error {
+	return c.post(fmt.Sprintf("/v1/warehouse/reservations/%d/release", reservationID), nil, nil)
+}
+
+// ListBackorders retrieves the backorders opened for an order
+func (c *WarehouseClient) ListBackorders(orderRef string) ([]*model.Backorder, error) {
+	resp, err := c.httpClient.Get(c.baseURL + "/v1/warehouse/backorders?order_ref=" + url.QueryEscape(orderRef))
+	if err != nil {
+		return nil, fmt.Errorf("warehouse service unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("warehouse service returned status %d", resp.StatusCode)
+	}
+
+	var backorders []*model.Backorder
+	if err := json.NewDecoder(resp.Body).Decode(&backorders); err != nil {
+		return nil, fmt.Errorf("failed to decode backorders: %w", err)
+	}
+	return backorders, nil
+}
+
+// ListShipments retrieves the shipments sent for an order
+func (c *WarehouseClient) ListShipments(orderRef string) ([]*model.Shipment, error) {
+	resp, err := c.httpClient.Get(c.baseURL + "/v1/warehouse/shipments?order_ref=" + url.QueryEscape(orderRef))
+	if err != nil {
+		return nil, fmt.Errorf("warehouse service unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("warehouse service returned status %d", resp.StatusCode)
+	}
+
+	var shipments []*model.Shipment
+	if err := json.NewDecoder(resp.Body).Decode(&shipments); err != nil {
+		return nil, fmt.Errorf("failed to decode shipments: %w", err)
+	}
+	return shipments, nil
+}
+
+// post sends body as JSON and decodes a successful response into out
+func (c *WarehouseClient) post(path string, body any, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Post(c.baseURL+path, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("warehouse service unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr model.Error
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Error == "INSUFFICIENT_STOCK" {
+			return ErrInsufficientStock
+		}
+		if resp.StatusCode < 500 {
+			return fmt.Errorf("%w: %s", ErrReservationFailed, apiErr.Message)
+		}
+		return fmt.Errorf("warehouse service returned status %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode warehouse response: %w", err)
+	}
+	return nil
+}
@@ -66,7 +66,7 @@ func (h *CartHandler) CreateCart(c *gin.Context) {
 
 // GetCart handles GET /shopping-cart/{shoppingCartId}
 // @Summary Get shopping cart by ID
-// @Description Retrieve a shopping cart's details using its unique identifier
+// @Description Retrieve a shopping cart's details using its unique identifier, priced in the cart's currency with its totals in the settlement currency alongside
 // @ID getCart
 // @Tags Shopping Cart
 // @Accept json
@@ -75,6 +75,7 @@ func (h *CartHandler) CreateCart(c *gin.Context) {
 // @Success 200 {object} model.Cart
 // @Failure 400 {object} model.Error
 // @Failure 404 {object} model.Error
+// @Failure 422 {object} model.Error
 // @Failure 500 {object} model.Error
 // @Router /shopping-cart/{shoppingCartId} [get]
 // @Security ApiKeyAuth
@@ -94,13 +95,27 @@ func (h *CartHandler) GetCart(c *gin.Context) {
 
 	// Get cart from service
 	cart, err := h.service.GetCart(cartID)
-	if err == service.ErrCartNotFound {
+	if err == service.ErrCurrency {
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Currency not supported",
+			Details: "A product in the cart is priced in a currency with no exchange rate",
+		})
+		return
+	} else if err == service.ErrCartNotFound {
 		c.JSON(http.StatusNotFound, model.Error{
 			Error:   "NOT_FOUND",
 			Message: "Cart not found",
 			Details: "No cart exists with the specified ID",
 		})
 		return
+	} else if err == service.ErrProductNotFound {
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Product not found",
+			Details: "A product in the cart no longer exists",
+		})
+		return
 	} else if err == service.ErrInvalidCart {
 		c.JSON(http.StatusBadRequest, model.Error{
 			Error:   "INVALID_INPUT",
@@ -123,7 +138,7 @@ func (h *CartHandler) GetCart(c *gin.Context) {
 
 // AddItemsToCart handles POST /shopping-cart/{shoppingCartId}/items
 // @Summary Add items to shopping cart
-// @Description Add products with specified quantities to a shopping cart
+// @Description Add products with specified quantities to a shopping cart. A cart holds at most 999 units of each product.
 // @ID addItemsToCart
 // @Tags Shopping Cart
 // @Accept json
@@ -177,6 +192,73 @@ func (h *CartHandler) AddItemsToCart(c *gin.Context) {
 			Details: "No product exists with the specified ID",
 		})
 		return
+	} else if err == service.ErrInvalidCart || err == service.ErrQuantityLimit {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetShippingAddress handles PUT /shopping-cart/{shoppingCartId}/shipping-address
+// @Summary Set shopping cart shipping address
+// @Description Set the destination used for tax and shipping calculation
+// @ID setShippingAddress
+// @Tags Shopping Cart
+// @Accept json
+// @Produce json
+// @Param shoppingCartId path int true "Unique identifier for the shopping cart" minimum(1)
+// @Param request body model.Address true "Shipping address"
+// @Success 204 "Shipping address set successfully"
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /shopping-cart/{shoppingCartId}/shipping-address [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *CartHandler) SetShippingAddress(c *gin.Context) {
+	// Parse shoppingCartId from URL
+	cartIDStr := c.Param("shoppingCartId")
+	cartID, err := strconv.Atoi(cartIDStr)
+	if err != nil || cartID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid cart ID",
+			Details: "Cart ID must be a positive integer",
+		})
+		return
+	}
+
+	// Parse request body
+	var req model.Address
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	err = h.service.SetShippingAddress(cartID, req)
+	if err == service.ErrCartNotFound {
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Cart not found",
+			Details: "No cart exists with the specified ID",
+		})
+		return
 	} else if err == service.ErrInvalidCart {
 		c.JSON(http.StatusBadRequest, model.Error{
 			Error:   "INVALID_INPUT",
@@ -196,17 +278,244 @@ func (h *CartHandler) AddItemsToCart(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// GetShippingOptions handles GET /shopping-cart/{shoppingCartId}/shipping-options
+// @Summary List shipping options
+// @Description Quote carrier services for the cart's contents and shipping address. Prices are in the cart's currency.
+// @ID getShippingOptions
+// @Tags Shopping Cart
+// @Accept json
+// @Produce json
+// @Param shoppingCartId path int true "Unique identifier for the shopping cart" minimum(1)
+// @Success 200 {array} model.ShippingOption
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /shopping-cart/{shoppingCartId}/shipping-options [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *CartHandler) GetShippingOptions(c *gin.Context) {
+	// Parse shoppingCartId from URL
+	cartIDStr := c.Param("shoppingCartId")
+	cartID, err := strconv.Atoi(cartIDStr)
+	if err != nil || cartID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid cart ID",
+			Details: "Cart ID must be a positive integer",
+		})
+		return
+	}
+
+	options, err := h.service.GetShippingOptions(cartID)
+	if err != nil {
+		h.writeShippingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// SelectShippingOption handles PUT /shopping-cart/{shoppingCartId}/shipping-option
+// @Summary Select shipping option
+// @Description Choose one of the quoted shipping options for a cart
+// @ID selectShippingOption
+// @Tags Shopping Cart
+// @Accept json
+// @Produce json
+// @Param shoppingCartId path int true "Unique identifier for the shopping cart" minimum(1)
+// @Param request body model.SelectShippingOptionRequest true "Shipping option"
+// @Success 204 "Shipping option selected successfully"
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /shopping-cart/{shoppingCartId}/shipping-option [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *CartHandler) SelectShippingOption(c *gin.Context) {
+	// Parse shoppingCartId from URL
+	cartIDStr := c.Param("shoppingCartId")
+	cartID, err := strconv.Atoi(cartIDStr)
+	if err != nil || cartID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid cart ID",
+			Details: "Cart ID must be a positive integer",
+		})
+		return
+	}
+
+	// Parse request body
+	var req model.SelectShippingOptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.SelectShippingOption(cartID, req.OptionID); err != nil {
+		h.writeShippingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeShippingError maps shipping quote errors to responses
+func (h *CartHandler) writeShippingError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrCartNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Cart not found",
+			Details: "No cart exists with the specified ID",
+		})
+	case service.ErrProductNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Product not found",
+			Details: "A product in the cart no longer exists",
+		})
+	case service.ErrInvalidCart:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	case service.ErrEmptyCart:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Cart is empty",
+			Details: "Add items before requesting shipping options",
+		})
+	case service.ErrMissingAddress:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Shipping address missing",
+			Details: "Set a shipping address before requesting shipping options",
+		})
+	case service.ErrCurrency:
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Currency not supported",
+			Details: "A product in the cart is priced in a currency with no exchange rate",
+		})
+	case service.ErrUndeliverable, service.ErrShippingOption:
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "UNAVAILABLE",
+			Message: "Shipping not available",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
+
+// SetCurrency handles PUT /shopping-cart/{shoppingCartId}/currency
+// @Summary Set cart currency
+// @Description Price a cart in a currency at the current exchange rates, which are locked for 30 minutes so the amounts do not move while the customer checks out. Each line's unit price, net amount and tax, and the shipping price, are converted and rounded on their own by the chosen rounding mode, and the totals are summed from them. Choosing the settlement currency removes the lock.
+// @ID setCartCurrency
+// @Tags Shopping Cart
+// @Accept json
+// @Produce json
+// @Param shoppingCartId path int true "Unique identifier for the shopping cart" minimum(1)
+// @Param request body model.SetCurrencyRequest true "Currency and rounding mode"
+// @Success 200 {object} model.Cart
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /shopping-cart/{shoppingCartId}/currency [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *CartHandler) SetCurrency(c *gin.Context) {
+	// Parse shoppingCartId from URL
+	cartIDStr := c.Param("shoppingCartId")
+	cartID, err := strconv.Atoi(cartIDStr)
+	if err != nil || cartID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid cart ID",
+			Details: "Cart ID must be a positive integer",
+		})
+		return
+	}
+
+	// Parse request body
+	var req model.SetCurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	cart, err := h.service.SetCurrency(cartID, &req)
+	if err == service.ErrCurrency {
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Currency not supported",
+			Details: "There is no exchange rate for the currency or a product in the cart",
+		})
+		return
+	} else if err == service.ErrCartNotFound {
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Cart not found",
+			Details: "No cart exists with the specified ID",
+		})
+		return
+	} else if err == service.ErrProductNotFound {
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Product not found",
+			Details: "A product in the cart no longer exists",
+		})
+		return
+	} else if err == service.ErrInvalidCart {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
 // CheckoutCart handles POST /shopping-cart/{shoppingCartId}/checkout
 // @Summary Checkout shopping cart
-// @Description Process checkout for a shopping cart
+// @Description Process checkout for a shopping cart, holding stock and authorizing payment. The cart is paid by one card, or split across several payments, such as gift cards and a card, whose amounts add up to the total. Either every payment is authorized or none is: if one fails, those already authorized are voided. A payment held for fraud review still places the order, with payment status pending_review. Payments are taken in the cart's currency at its locked exchange rates; once the lock has expired the currency must be chosen again. The order records its amounts in both the cart's currency and the settlement currency.
 // @ID checkoutCart
 // @Tags Shopping Cart
 // @Accept json
 // @Produce json
 // @Param shoppingCartId path int true "Unique identifier for the shopping cart" minimum(1)
+// @Param request body model.CheckoutRequest true "Card token, saved payment method or split payments to pay with"
 // @Success 200 {object} model.CheckoutResponse
 // @Failure 400 {object} model.Error
+// @Failure 402 {object} model.Error
 // @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 422 {object} model.Error
 // @Failure 500 {object} model.Error
 // @Router /shopping-cart/{shoppingCartId}/checkout [post]
 // @Security ApiKeyAuth
@@ -224,8 +533,18 @@ func (h *CartHandler) CheckoutCart(c *gin.Context) {
 		return
 	}
 
+	var req model.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
 	// Process checkout
-	orderID, err := h.service.CheckoutCart(cartID)
+	order, err := h.service.CheckoutCart(cartID, &req)
 	if err == service.ErrCartNotFound {
 		c.JSON(http.StatusNotFound, model.Error{
 			Error:   "NOT_FOUND",
@@ -240,6 +559,97 @@ func (h *CartHandler) CheckoutCart(c *gin.Context) {
 			Details: "Cannot checkout an empty cart",
 		})
 		return
+	} else if err == service.ErrMissingAddress {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Shipping address missing",
+			Details: "Set a shipping address before checking out",
+		})
+		return
+	} else if err == service.ErrNoShipping {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Shipping option missing",
+			Details: "Select a shipping option before checking out",
+		})
+		return
+	} else if err == service.ErrOrderExists {
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "CONFLICT",
+			Message: "Order already placed",
+			Details: "An order has already been placed from this cart",
+		})
+		return
+	} else if err == service.ErrCheckoutBusy {
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Checkout in progress",
+			Details: "Another checkout of this cart has not finished",
+		})
+		return
+	} else if err == service.ErrRateLockExpired {
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Exchange rate expired",
+			Details: "The cart's exchange rate lock has expired; set its currency again to lock current rates",
+		})
+		return
+	} else if err == service.ErrCurrency {
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Currency not supported",
+			Details: "A product in the cart is priced in a currency with no exchange rate",
+		})
+		return
+	} else if err == service.ErrShippingOption {
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Shipping option unavailable",
+			Details: "The selected shipping option no longer applies to this cart",
+		})
+		return
+	} else if err == service.ErrOutOfStock {
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INSUFFICIENT_STOCK",
+			Message: "Insufficient stock",
+			Details: "One or more items in the cart are out of stock",
+		})
+		return
+	} else if err == service.ErrPaymentDeclined {
+		c.JSON(http.StatusPaymentRequired, model.Error{
+			Error:   "PAYMENT_DECLINED",
+			Message: "Payment declined",
+			Details: "A card or gift card could not be authorized for its amount; nothing was charged",
+		})
+		return
+	} else if err == service.ErrPaymentRejected {
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "PAYMENT_REJECTED",
+			Message: "Card rejected",
+			Details: "A card token, saved payment method or gift card is unknown, has expired or cannot pay in this currency",
+		})
+		return
+	} else if err == service.ErrPaymentTotal {
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Payments do not cover the order total",
+			Details: "The payment amounts must add up to the cart total, including tax and shipping",
+		})
+		return
+	} else if err == service.ErrNoPaymentMethod || err == service.ErrInvalidPayment {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	} else if err == service.ErrProductNotFound {
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Product not found",
+			Details: "A product in the cart no longer exists",
+		})
+		return
 	} else if err == service.ErrInvalidCart {
 		c.JSON(http.StatusBadRequest, model.Error{
 			Error:   "INVALID_INPUT",
@@ -257,6 +667,14 @@ func (h *CartHandler) CheckoutCart(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, model.CheckoutResponse{
-		OrderID: orderID,
+		OrderID:    order.OrderID,
+		PaymentID:  order.PaymentID,
+		Payments:   order.Payments,
+		Currency:   order.Currency,
+		Subtotal:   order.Subtotal,
+		Tax:        order.Tax,
+		Shipping:   order.Shipping,
+		Total:      order.Total,
+		Settlement: order.Settlement,
 	})
 }
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gocart-v2/cart-service/internal/service"
+	"github.com/gocart-v2/shared/model"
+)
+
+type OrderHandler struct {
+	service *service.CartService
+}
+
+func NewOrderHandler(service *service.CartService) *OrderHandler {
+	return &OrderHandler{service: service}
+}
+
+// GetOrder handles GET /order/{orderId}
+// @Summary Get order by ID
+// @Description Retrieve an order created by checking out a shopping cart
+// @ID getOrder
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param orderId path int true "Unique identifier for the order" minimum(1)
+// @Success 200 {object} model.Order
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /order/{orderId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	// Parse orderId from URL
+	orderIDStr := c.Param("orderId")
+	orderID, err := strconv.Atoi(orderIDStr)
+	if err != nil || orderID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid order ID",
+			Details: "Order ID must be a positive integer",
+		})
+		return
+	}
+
+	order, err := h.service.GetOrder(orderID)
+	if err == service.ErrOrderNotFound {
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Order not found",
+			Details: "No order exists with the specified ID",
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
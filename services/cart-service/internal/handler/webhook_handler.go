@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/webhook"
+)
+
+// WebhookHandler documents the shared webhook endpoints
+type WebhookHandler struct {
+	handler *webhook.Handler
+}
+
+func NewWebhookHandler(dispatcher *webhook.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{handler: webhook.NewHandler(dispatcher)}
+}
+
+// @Summary Subscribe to webhook events
+// @Description Register an endpoint for order.placed events. Every delivery carries an X-Webhook-Signature header of the form t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>"> signed with the returned secret, which is not shown again.
+// @ID createOrderWebhookSubscription
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param request body model.CreateWebhookSubscriptionRequest true "Endpoint and event types"
+// @Success 201 {object} model.WebhookSubscription
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /webhook/subscriptions [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	h.handler.CreateSubscription(c)
+}
+
+// @Summary List webhook subscriptions
+// @ID listOrderWebhookSubscriptions
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.WebhookSubscription
+// @Failure 500 {object} model.Error
+// @Router /webhook/subscriptions [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	h.handler.ListSubscriptions(c)
+}
+
+// @Summary Delete webhook subscription
+// @ID deleteOrderWebhookSubscription
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param subscriptionId path int true "Unique identifier for the subscription" minimum(1)
+// @Success 204
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /webhook/subscriptions/{subscriptionId} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	h.handler.DeleteSubscription(c)
+}
+
+// @Summary List webhook deliveries
+// @ID listOrderWebhookDeliveries
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param status query string false "Only include deliveries in this status" Enums(pending, delivered, dead_lettered)
+// @Success 200 {array} model.WebhookDelivery
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /webhook/deliveries [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	h.handler.ListDeliveries(c)
+}
+
+// @Summary List dead-lettered webhook deliveries
+// @ID listOrderWebhookDeadLetters
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.WebhookDelivery
+// @Failure 500 {object} model.Error
+// @Router /webhook/dead-letters [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	h.handler.ListDeadLetters(c)
+}
+
+// @Summary Redeliver webhook
+// @ID redeliverOrderWebhook
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param deliveryId path int true "Unique identifier for the delivery" minimum(1)
+// @Success 200 {object} model.WebhookDelivery
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /webhook/deliveries/{deliveryId}/redeliver [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *WebhookHandler) RedeliverDelivery(c *gin.Context) {
+	h.handler.RedeliverDelivery(c)
+}
@@ -8,19 +8,23 @@ import (
 )
 
 var (
-	ErrCartNotFound = errors.New("cart not found")
+	ErrCartNotFound       = errors.New("cart not found")
+	ErrCheckoutInProgress = errors.New("cart is already being checked out")
+	ErrQuantityLimit      = errors.New("item quantity exceeds the limit")
 )
 
 type CartRepository struct {
-	carts      map[int]*model.Cart
-	mu         sync.RWMutex
-	nextCartID int
+	carts       map[int]*model.Cart
+	checkingOut map[int]bool
+	mu          sync.RWMutex
+	nextCartID  int
 }
 
 func NewCartRepository() *CartRepository {
 	return &CartRepository{
-		carts:      make(map[int]*model.Cart),
-		nextCartID: 1,
+		carts:       make(map[int]*model.Cart),
+		checkingOut: make(map[int]bool),
+		nextCartID:  1,
 	}
 }
 
@@ -55,12 +59,25 @@ func (r *CartRepository) GetByID(cartID int) (*model.Cart, error) {
 	cartCopy := *cart
 	cartCopy.Items = make([]model.CartItem, len(cart.Items))
 	copy(cartCopy.Items, cart.Items)
+	if cart.ShippingAddress != nil {
+		addressCopy := *cart.ShippingAddress
+		cartCopy.ShippingAddress = &addressCopy
+	}
+	if cart.RateLock != nil {
+		lockCopy := *cart.RateLock
+		lockCopy.Rates.Rates = make(map[string]string, len(cart.RateLock.Rates.Rates))
+		for code, rate := range cart.RateLock.Rates.Rates {
+			lockCopy.Rates.Rates[code] = rate
+		}
+		cartCopy.RateLock = &lockCopy
+	}
 
 	return &cartCopy, nil
 }
 
-// AddItem adds an item to a cart
-func (r *CartRepository) AddItem(cartID int, item model.CartItem) error {
+// AddItem adds an item to a cart, failing without a change if the cart
+// would then hold more than maxQuantity units of the product
+func (r *CartRepository) AddItem(cartID int, item model.CartItem, maxQuantity int) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -73,6 +90,9 @@ func (r *CartRepository) AddItem(cartID int, item model.CartItem) error {
 	found := false
 	for i, existingItem := range cart.Items {
 		if existingItem.ProductID == item.ProductID {
+			if existingItem.Quantity+item.Quantity > maxQuantity {
+				return ErrQuantityLimit
+			}
 			cart.Items[i].Quantity += item.Quantity
 			found = true
 			break
@@ -80,12 +100,84 @@ func (r *CartRepository) AddItem(cartID int, item model.CartItem) error {
 	}
 
 	if !found {
+		if item.Quantity > maxQuantity {
+			return ErrQuantityLimit
+		}
 		cart.Items = append(cart.Items, item)
 	}
 
 	return nil
 }
 
+// SetShippingAddress sets the shipping destination of a cart
+func (r *CartRepository) SetShippingAddress(cartID int, address model.Address) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cart, exists := r.carts[cartID]
+	if !exists {
+		return ErrCartNotFound
+	}
+
+	cart.ShippingAddress = &address
+	return nil
+}
+
+// SetShippingOption records the shipping option chosen for a cart
+func (r *CartRepository) SetShippingOption(cartID int, optionID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cart, exists := r.carts[cartID]
+	if !exists {
+		return ErrCartNotFound
+	}
+
+	cart.ShippingOptionID = optionID
+	return nil
+}
+
+// SetCurrency records the currency a cart is priced in and the rates it is
+// locked at. A nil lock prices the cart in the settlement currency.
+func (r *CartRepository) SetCurrency(cartID int, currency string, lock *model.RateLock) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cart, exists := r.carts[cartID]
+	if !exists {
+		return ErrCartNotFound
+	}
+
+	cart.Currency = currency
+	cart.RateLock = lock
+	return nil
+}
+
+// BeginCheckout marks a cart as being checked out, failing if another
+// checkout of it has not finished
+func (r *CartRepository) BeginCheckout(cartID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.carts[cartID]; !exists {
+		return ErrCartNotFound
+	}
+	if r.checkingOut[cartID] {
+		return ErrCheckoutInProgress
+	}
+
+	r.checkingOut[cartID] = true
+	return nil
+}
+
+// EndCheckout clears a cart's checkout mark so it can be checked out again
+func (r *CartRepository) EndCheckout(cartID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.checkingOut, cartID)
+}
+
 // Delete removes a cart (used after checkout)
 func (r *CartRepository) Delete(cartID int) error {
 	r.mu.Lock()
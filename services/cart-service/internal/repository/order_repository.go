@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrOrderNotFound = errors.New("order not found")
+	ErrOrderExists   = errors.New("order already exists")
+)
+
+type OrderRepository struct {
+	orders  map[int]*model.Order
+	claimed map[int]bool
+	mu      sync.RWMutex
+}
+
+func NewOrderRepository() *OrderRepository {
+	return &OrderRepository{
+		orders:  make(map[int]*model.Order),
+		claimed: make(map[int]bool),
+	}
+}
+
+// Claim sets an order ID aside for a checkout in progress, so that the
+// order can be stored once stock and payment are secured
+func (r *OrderRepository) Claim(orderID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.orders[orderID]; exists || r.claimed[orderID] {
+		return ErrOrderExists
+	}
+
+	r.claimed[orderID] = true
+	return nil
+}
+
+// Unclaim gives back an order ID claimed by a checkout that failed
+func (r *OrderRepository) Unclaim(orderID int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.claimed, orderID)
+}
+
+// Create stores a new order, taking over its claimed ID if there is one
+func (r *OrderRepository) Create(order *model.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.orders[order.OrderID]; exists {
+		return ErrOrderExists
+	}
+
+	delete(r.claimed, order.OrderID)
+	r.orders[order.OrderID] = copyOrder(order)
+	return nil
+}
+
+// GetByID retrieves an order by its ID
+func (r *OrderRepository) GetByID(orderID int) (*model.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, exists := r.orders[orderID]
+	if !exists {
+		return nil, ErrOrderNotFound
+	}
+
+	return copyOrder(order), nil
+}
+
+// SetShipments records the shipments of an order and its overall shipment status
+func (r *OrderRepository) SetShipments(orderID int, status model.ShipmentProgress, shipments []model.OrderShipment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, exists := r.orders[orderID]
+	if !exists {
+		return ErrOrderNotFound
+	}
+
+	order.ShipmentStatus = status
+	order.Shipments = append([]model.OrderShipment(nil), shipments...)
+	return nil
+}
+
+// SetPaymentStatus records the status of one of an order's payments
+func (r *OrderRepository) SetPaymentStatus(orderID, paymentID int, status model.PaymentStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, exists := r.orders[orderID]
+	if !exists {
+		return ErrOrderNotFound
+	}
+
+	for i := range order.Payments {
+		if order.Payments[i].PaymentID == paymentID {
+			order.Payments[i].Status = status
+		}
+	}
+	if order.PaymentID == paymentID {
+		order.PaymentStatus = status
+	}
+	return nil
+}
+
+// copyOrder returns a copy of an order that shares no slices with the original
+func copyOrder(order *model.Order) *model.Order {
+	orderCopy := *order
+	orderCopy.Lines = make([]model.OrderLine, len(order.Lines))
+	copy(orderCopy.Lines, order.Lines)
+	orderCopy.Fulfillments = make([]model.Fulfillment, len(order.Fulfillments))
+	for i, fulfillment := range order.Fulfillments {
+		orderCopy.Fulfillments[i] = fulfillment
+		orderCopy.Fulfillments[i].Items = make([]model.CartItem, len(fulfillment.Items))
+		copy(orderCopy.Fulfillments[i].Items, fulfillment.Items)
+		orderCopy.Fulfillments[i].SerialNumbers = append([]string(nil), fulfillment.SerialNumbers...)
+	}
+	orderCopy.Shipments = append([]model.OrderShipment(nil), order.Shipments...)
+	orderCopy.Payments = append([]model.OrderPayment(nil), order.Payments...)
+	return &orderCopy
+}
@@ -11,6 +11,8 @@ import (
 type AllHandlers struct {
 	RootHandler    *handler.RootHandler
 	CartHandler    *handler.CartHandler
+	OrderHandler   *handler.OrderHandler
+	WebhookHandler *handler.WebhookHandler
 	SwaggerHandler *webdav.Handler
 }
 
@@ -28,8 +30,28 @@ func SetupRoutes(e *gin.Engine, h *AllHandlers) {
 			carts.POST("", h.CartHandler.CreateCart)
 			carts.GET("/:shoppingCartId", h.CartHandler.GetCart)
 			carts.POST("/:shoppingCartId/items", h.CartHandler.AddItemsToCart)
+			carts.PUT("/:shoppingCartId/shipping-address", h.CartHandler.SetShippingAddress)
+			carts.GET("/:shoppingCartId/shipping-options", h.CartHandler.GetShippingOptions)
+			carts.PUT("/:shoppingCartId/shipping-option", h.CartHandler.SelectShippingOption)
+			carts.PUT("/:shoppingCartId/currency", h.CartHandler.SetCurrency)
 			carts.POST("/:shoppingCartId/checkout", h.CartHandler.CheckoutCart)
 		}
+
+		// Order routes
+		orders := v1.Group("/order")
+		{
+			orders.GET("/:orderId", h.OrderHandler.GetOrder)
+		}
+		// Webhook routes
+		webhooks := v1.Group("/webhook")
+		{
+			webhooks.POST("/subscriptions", h.WebhookHandler.CreateSubscription)
+			webhooks.GET("/subscriptions", h.WebhookHandler.ListSubscriptions)
+			webhooks.DELETE("/subscriptions/:subscriptionId", h.WebhookHandler.DeleteSubscription)
+			webhooks.GET("/deliveries", h.WebhookHandler.ListDeliveries)
+			webhooks.POST("/deliveries/:deliveryId/redeliver", h.WebhookHandler.RedeliverDelivery)
+			webhooks.GET("/dead-letters", h.WebhookHandler.ListDeadLetters)
+		}
 	}
 
 	swagger := e.Group("/swagger")
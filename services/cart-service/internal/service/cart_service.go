@@ -2,9 +2,20 @@ package service
 
 import (
 	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/gocart-v2/cart-service/internal/client"
 	"github.com/gocart-v2/cart-service/internal/repository"
+	"github.com/gocart-v2/cart-service/internal/shipping"
+	"github.com/gocart-v2/cart-service/internal/tax"
+	"github.com/gocart-v2/shared/currency"
 	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/shared/units"
+	"github.com/gocart-v2/shared/webhook"
 )
 
 var (
@@ -12,15 +23,68 @@ var (
 	ErrCartNotFound    = errors.New("cart not found")
 	ErrInvalidCart     = errors.New("invalid cart data")
 	ErrEmptyCart       = errors.New("cart is empty")
+	ErrMissingAddress  = errors.New("shipping address is required")
+	ErrOrderNotFound   = errors.New("order not found")
+	ErrUndeliverable   = errors.New("destination is not served by any carrier")
+	ErrNoShipping      = errors.New("shipping option has not been selected")
+	ErrShippingOption  = errors.New("shipping option is not available for this cart")
+	ErrOutOfStock      = errors.New("not enough stock to fulfill the cart")
+	ErrPaymentDeclined = errors.New("payment was declined")
+	ErrPaymentRejected = errors.New("payment service rejected the card")
+	ErrNoPaymentMethod = errors.New("exactly one of a card token, a saved payment method or a list of payments is required")
+	ErrInvalidPayment  = errors.New("each payment needs an amount and exactly one of a card token, a saved payment method or a gift card code")
+	ErrPaymentTotal    = errors.New("payments do not add up to the order total")
+	ErrCurrency        = errors.New("currency is not supported")
+	ErrRateLockExpired = errors.New("exchange rate lock has expired")
+	ErrCheckoutBusy    = errors.New("cart is already being checked out")
+	ErrOrderExists     = errors.New("an order has already been placed from this cart")
+	ErrQuantityLimit   = errors.New("a cart may hold at most 999 units of a product")
 )
 
+// MaxItemQuantity is the most units of one product a cart may hold
+const MaxItemQuantity = 999
+
+// DefaultRateLockTTL is how long a cart keeps the exchange rates locked when
+// its currency was chosen. Checkout is refused after that until the
+// currency is chosen again at the rates of the time.
+const DefaultRateLockTTL = 30 * time.Minute
+
 type CartService struct {
-	cartRepo *repository.CartRepository
+	cartRepo        *repository.CartRepository
+	orderRepo       *repository.OrderRepository
+	productClient   *client.ProductClient
+	warehouseClient *client.WarehouseClient
+	paymentClient   *client.PaymentClient
+	taxCalculator   tax.Calculator
+	rateTable       *shipping.RateTable
+	packer          *shipping.Packer
+	exchangeRates   *currency.RateTable
+	webhooks        *webhook.Dispatcher
 }
 
-func NewCartService(cartRepo *repository.CartRepository) *CartService {
+func NewCartService(
+	cartRepo *repository.CartRepository,
+	orderRepo *repository.OrderRepository,
+	productClient *client.ProductClient,
+	warehouseClient *client.WarehouseClient,
+	paymentClient *client.PaymentClient,
+	taxCalculator tax.Calculator,
+	rateTable *shipping.RateTable,
+	packer *shipping.Packer,
+	exchangeRates *currency.RateTable,
+	webhooks *webhook.Dispatcher,
+) *CartService {
 	return &CartService{
-		cartRepo: cartRepo,
+		cartRepo:        cartRepo,
+		orderRepo:       orderRepo,
+		productClient:   productClient,
+		warehouseClient: warehouseClient,
+		paymentClient:   paymentClient,
+		taxCalculator:   taxCalculator,
+		rateTable:       rateTable,
+		packer:          packer,
+		exchangeRates:   exchangeRates,
+		webhooks:        webhooks,
 	}
 }
 
@@ -33,7 +97,8 @@ func (s *CartService) CreateCart(customerID int) (*model.Cart, error) {
 	return s.cartRepo.Create(customerID)
 }
 
-// AddItemToCart adds an item to a cart
+// AddItemToCart adds an item to a cart, up to MaxItemQuantity units of
+// each product
 func (s *CartService) AddItemToCart(cartID int, productID int, quantity int) error {
 	if cartID < 1 || productID < 1 || quantity < 1 {
 		return ErrInvalidCart
@@ -48,50 +113,612 @@ func (s *CartService) AddItemToCart(cartID int, productID int, quantity int) err
 		return err
 	}
 
+	// Verify product exists
+	_, err = s.productClient.GetProduct(productID)
+	if err == client.ErrProductNotFound {
+		return ErrProductNotFound
+	}
+	if err != nil {
+		return err
+	}
+
 	// Add item to cart
 	item := model.CartItem{
 		ProductID: productID,
 		Quantity:  quantity,
 	}
 
-	return s.cartRepo.AddItem(cartID, item)
+	err = s.cartRepo.AddItem(cartID, item, MaxItemQuantity)
+	if err == repository.ErrQuantityLimit {
+		return ErrQuantityLimit
+	}
+	return err
+}
+
+// SetShippingAddress sets where a cart will be shipped
+func (s *CartService) SetShippingAddress(cartID int, address model.Address) error {
+	if cartID < 1 || address.Country == "" {
+		return ErrInvalidCart
+	}
+
+	err := s.cartRepo.SetShippingAddress(cartID, address)
+	if err == repository.ErrCartNotFound {
+		return ErrCartNotFound
+	}
+	return err
 }
 
-// CheckoutCart processes checkout for a cart
-func (s *CartService) CheckoutCart(cartID int) (int, error) {
+// SetCurrency prices a cart in a currency, locking the current exchange
+// rates for DefaultRateLockTTL. Choosing the settlement currency removes
+// the lock, pricing the cart at the store's own prices again.
+func (s *CartService) SetCurrency(cartID int, req *model.SetCurrencyRequest) (*model.Cart, error) {
+	rounding := req.Rounding
+	if rounding == "" {
+		rounding = model.RoundHalfUp
+	}
+	if cartID < 1 || !rounding.Valid() {
+		return nil, ErrInvalidCart
+	}
+	if !currency.Valid(req.Currency) {
+		return nil, ErrCurrency
+	}
+
+	rates := s.exchangeRates.Rates()
+	var err error
+	if req.Currency == rates.Base {
+		err = s.cartRepo.SetCurrency(cartID, "", nil)
+	} else {
+		rate, ok := rates.Rates[req.Currency]
+		if !ok {
+			return nil, ErrCurrency
+		}
+		now := time.Now().UTC()
+		err = s.cartRepo.SetCurrency(cartID, req.Currency, &model.RateLock{
+			Currency:           req.Currency,
+			SettlementCurrency: rates.Base,
+			Rate:               rate,
+			Rounding:           rounding,
+			Rates:              rates,
+			LockedAt:           now,
+			ExpiresAt:          now.Add(DefaultRateLockTTL),
+		})
+	}
+	if err == repository.ErrCartNotFound {
+		return nil, ErrCartNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCart(cartID)
+}
+
+// CheckoutCart processes checkout for a cart, authorizing payment while the
+// stock is held. The cart is paid either by one card, given as a new card
+// token or one of the customer's saved payment methods, or by several
+// payments, including gift cards, that add up to the total. Payments are
+// taken in the cart's currency at its locked exchange rates, which must
+// not have expired.
+func (s *CartService) CheckoutCart(cartID int, req *model.CheckoutRequest) (*model.Order, error) {
+	if err := validatePayments(req); err != nil {
+		return nil, err
+	}
+
 	if cartID < 1 {
-		return 0, ErrInvalidCart
+		return nil, ErrInvalidCart
 	}
 
+	// Only one checkout of a cart may run at a time, since both would
+	// reserve stock and take payment for the same order
+	err := s.cartRepo.BeginCheckout(cartID)
+	if err == repository.ErrCartNotFound {
+		return nil, ErrCartNotFound
+	}
+	if err == repository.ErrCheckoutInProgress {
+		return nil, ErrCheckoutBusy
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer s.cartRepo.EndCheckout(cartID)
+
 	// Get cart
 	cart, err := s.cartRepo.GetByID(cartID)
 	if err == repository.ErrCartNotFound {
-		return 0, ErrCartNotFound
+		return nil, ErrCartNotFound
 	}
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	// Validate cart has items
+	// Validate cart has items and a destination
 	if len(cart.Items) == 0 {
-		return 0, ErrEmptyCart
+		return nil, ErrEmptyCart
+	}
+	if cart.ShippingAddress == nil {
+		return nil, ErrMissingAddress
+	}
+	now := time.Now().UTC()
+	if cart.RateLock != nil && !now.Before(cart.RateLock.ExpiresAt) {
+		return nil, ErrRateLockExpired
 	}
 
-	// In a real system, this would:
-	// 1. Reserve inventory
-	// 2. Process payment
-	// 3. Create order
-	// 4. Delete cart
+	// Price the cart, including tax and shipping for the destination
+	products, err := s.loadProducts(cart)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.priceCart(cart, products)
+	if err != nil {
+		return nil, err
+	}
+	if cart.ShippingOptionID == "" {
+		return nil, ErrNoShipping
+	}
+	if totals.ShippingOption == nil {
+		return nil, ErrShippingOption
+	}
+	payments, err := paymentsFor(req, totals.Total)
+	if err != nil {
+		return nil, err
+	}
 
-	// For now, just generate an order ID and delete the cart
 	orderID := cartID * 1000 // Simple order ID generation
 
+	// Claim the order ID before touching stock or payment, so that the
+	// order can always be recorded once the reservation is confirmed
+	if err := s.orderRepo.Claim(orderID); err == repository.ErrOrderExists {
+		return nil, ErrOrderExists
+	} else if err != nil {
+		return nil, err
+	}
+	placed := false
+	defer func() {
+		if !placed {
+			s.orderRepo.Unclaim(orderID)
+		}
+	}()
+
+	// Hold stock for every line before taking payment. Lines for products
+	// that allow backorders or pre-orders wait for stock instead of failing.
+	reserveReq := &model.ReserveRequest{
+		OrderRef:    strconv.Itoa(orderID),
+		CustomerID:  cart.CustomerID,
+		Destination: cart.ShippingAddress,
+		Lines:       make([]model.ReserveLineRequest, 0, len(cart.Items)),
+	}
+	for _, item := range cart.Items {
+		reserveReq.Lines = append(reserveReq.Lines, model.ReserveLineRequest{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Backorder: products[item.ProductID].BackorderTerms(now),
+		})
+	}
+	reservation, err := s.warehouseClient.Reserve(reserveReq)
+	if err == client.ErrInsufficientStock {
+		return nil, ErrOutOfStock
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Authorize payment while the stock is held. The holds are captured
+	// through payment-service once the order is fulfilled. A payment held
+	// for fraud review still places the order; staff release or cancel it.
+	authorized, err := s.authorizePayments(cart, reserveReq.OrderRef, req.BillingCountry, totals.Currency, payments)
+	if err != nil {
+		s.releaseReservation(reservation.ReservationID)
+		return nil, err
+	}
+
+	confirmed, err := s.warehouseClient.ConfirmReservation(reservation.ReservationID)
+	if err != nil {
+		s.voidPayments(authorized)
+		s.releaseReservation(reservation.ReservationID)
+		return nil, err
+	}
+	applyBackorders(totals.Lines, confirmed.Backorders)
+
+	// Record how the customer's amounts were converted from settlement ones
+	exchangeRate, rounding := "1", model.RoundHalfUp
+	if cart.RateLock != nil {
+		exchangeRate, rounding = cart.RateLock.Rate, cart.RateLock.Rounding
+	}
+
+	order := &model.Order{
+		OrderID:         orderID,
+		CartID:          cart.CartID,
+		CustomerID:      cart.CustomerID,
+		ReservationID:   reservation.ReservationID,
+		Lines:           totals.Lines,
+		Fulfillments:    fulfillmentsFor(confirmed),
+		ShippingAddress: *cart.ShippingAddress,
+		ShippingOption:  *totals.ShippingOption,
+		Currency:        totals.Currency,
+		Subtotal:        totals.Subtotal,
+		Tax:             totals.Tax,
+		Shipping:        totals.Shipping,
+		Total:           totals.Total,
+		Settlement:      *totals.Settlement,
+		ExchangeRate:    exchangeRate,
+		Rounding:        rounding,
+		PaymentID:       authorized[0].PaymentID,
+		PaymentStatus:   authorized[0].Status,
+		Payments:        authorized,
+		ShipmentStatus:  model.OrderUnshipped,
+		Shipments:       []model.OrderShipment{},
+		CreatedAt:       now,
+	}
+	// The ID is claimed, so only the store itself can fail here
+	if err := s.orderRepo.Create(order); err != nil {
+		s.voidPayments(authorized)
+		return nil, err
+	}
+	placed = true
+
+	if err := s.webhooks.Publish(model.EventOrderPlaced, order); err != nil {
+		log.Printf("Failed to publish order %d: %v", order.OrderID, err)
+	}
+
 	err = s.cartRepo.Delete(cartID)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// releaseReservation gives held stock back after a failed checkout
+func (s *CartService) releaseReservation(reservationID int) {
+	if err := s.warehouseClient.ReleaseReservation(reservationID); err != nil {
+		log.Printf("Failed to release reservation %d: %v", reservationID, err)
+	}
+}
+
+// validatePayments checks that a checkout names exactly one way to pay and
+// that each of several payments names exactly one instrument
+func validatePayments(req *model.CheckoutRequest) error {
+	if countGiven(req.CardToken != "", req.PaymentMethodID != 0, len(req.Payments) > 0) != 1 {
+		return ErrNoPaymentMethod
+	}
+	for _, payment := range req.Payments {
+		if payment.Amount < 1 || countGiven(payment.CardToken != "", payment.PaymentMethodID != 0, payment.GiftCardCode != "") != 1 {
+			return ErrInvalidPayment
+		}
+	}
+	return nil
+}
+
+// paymentsFor returns the payments a checkout splits the total across. A
+// single card pays the whole total.
+func paymentsFor(req *model.CheckoutRequest, total int) ([]model.CheckoutPayment, error) {
+	if len(req.Payments) == 0 {
+		return []model.CheckoutPayment{{
+			Amount:          total,
+			CardToken:       req.CardToken,
+			PaymentMethodID: req.PaymentMethodID,
+		}}, nil
+	}
+
+	sum := 0
+	for _, payment := range req.Payments {
+		sum += payment.Amount
+	}
+	if sum != total {
+		return nil, ErrPaymentTotal
+	}
+	return append([]model.CheckoutPayment(nil), req.Payments...), nil
+}
+
+// authorizePayments authorizes every payment for an order in the currency
+// the customer pays in, gift cards first since a short balance is the
+// likeliest failure. Either all of them are
+// authorized or held for review, or the ones already authorized are voided
+// and the error for the payment that failed is returned.
+func (s *CartService) authorizePayments(cart *model.Cart, orderRef, billingCountry, presentment string, payments []model.CheckoutPayment) ([]model.OrderPayment, error) {
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].GiftCardCode != "" && payments[j].GiftCardCode == ""
+	})
+
+	authorized := make([]model.OrderPayment, 0, len(payments))
+	for _, p := range payments {
+		payment, err := s.paymentClient.Authorize(&model.AuthorizePaymentRequest{
+			OrderRef:        orderRef,
+			CustomerID:      cart.CustomerID,
+			Amount:          p.Amount,
+			Currency:        presentment,
+			CardToken:       p.CardToken,
+			PaymentMethodID: p.PaymentMethodID,
+			GiftCardCode:    p.GiftCardCode,
+			BillingCountry:  billingCountry,
+			ShippingCountry: strings.ToUpper(cart.ShippingAddress.Country),
+		})
+		if errors.Is(err, client.ErrPaymentRejected) {
+			err = ErrPaymentRejected
+		} else if err == nil && payment.Status != model.PaymentAuthorized && payment.Status != model.PaymentPendingReview {
+			log.Printf("Payment %d for order %s failed: %s", payment.PaymentID, orderRef, payment.FailureReason)
+			err = ErrPaymentDeclined
+		}
+		if err != nil {
+			s.voidPayments(authorized)
+			return nil, err
+		}
+
+		authorized = append(authorized, model.OrderPayment{
+			PaymentID:  payment.PaymentID,
+			Instrument: payment.Instrument,
+			Amount:     payment.Amount,
+			Status:     payment.Status,
+		})
+	}
+	return authorized, nil
+}
+
+// voidPayments releases the holds of payments after a failed checkout.
+// Payments held for review have no hold yet, so their review is canceled.
+func (s *CartService) voidPayments(payments []model.OrderPayment) {
+	for _, payment := range payments {
+		var err error
+		if payment.Status == model.PaymentPendingReview {
+			err = s.paymentClient.CancelReview(payment.PaymentID)
+		} else {
+			err = s.paymentClient.Void(payment.PaymentID)
+		}
+		if err != nil {
+			log.Printf("Failed to void payment %d: %v", payment.PaymentID, err)
+		}
+	}
+}
+
+// countGiven returns how many of the conditions hold
+func countGiven(given ...bool) int {
+	count := 0
+	for _, g := range given {
+		if g {
+			count++
+		}
+	}
+	return count
+}
+
+// applyBackorders marks order lines with the units still waiting for stock
+// and the latest date they are expected to ship
+func applyBackorders(lines []model.OrderLine, backorders []model.BackorderLine) {
+	for i := range lines {
+		lines[i].Backordered = 0
+		lines[i].ExpectedShipDate = nil
+		for _, backorder := range backorders {
+			if backorder.ProductID != lines[i].ProductID {
+				continue
+			}
+			lines[i].Backordered += backorder.Quantity
+			if lines[i].ExpectedShipDate == nil || backorder.ExpectedShipDate.After(*lines[i].ExpectedShipDate) {
+				shipDate := backorder.ExpectedShipDate
+				lines[i].ExpectedShipDate = &shipDate
+			}
+		}
+	}
+}
+
+// fulfillmentsFor groups confirmed lines, and the serial numbers picked for
+// them, by the warehouse that will ship them
+func fulfillmentsFor(reservation *model.Reservation) []model.Fulfillment {
+	fulfillments := []model.Fulfillment{}
+	index := make(map[int]int)
+	for _, line := range reservation.Lines {
+		i, exists := index[line.WarehouseID]
+		if !exists {
+			i = len(fulfillments)
+			index[line.WarehouseID] = i
+			fulfillments = append(fulfillments, model.Fulfillment{WarehouseID: line.WarehouseID})
+		}
+		fulfillments[i].Items = append(fulfillments[i].Items, model.CartItem{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+		})
+		fulfillments[i].SerialNumbers = append(fulfillments[i].SerialNumbers, line.SerialNumbers...)
+	}
+	return fulfillments
+}
+
+// GetShippingOptions quotes the shipping options available for a cart
+func (s *CartService) GetShippingOptions(cartID int) ([]model.ShippingOption, error) {
+	if cartID < 1 {
+		return nil, ErrInvalidCart
+	}
+
+	cart, err := s.cartRepo.GetByID(cartID)
+	if err == repository.ErrCartNotFound {
+		return nil, ErrCartNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cart.Items) == 0 {
+		return nil, ErrEmptyCart
+	}
+	if cart.ShippingAddress == nil {
+		return nil, ErrMissingAddress
 	}
 
-	return orderID, nil
+	products, err := s.loadProducts(cart)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := s.quoteShipping(cart, products)
+	if err != nil {
+		return nil, err
+	}
+
+	// Quote prices in the currency the customer sees
+	conv := s.converterFor(cart)
+	for i := range options {
+		if options[i].Price, err = conv.convert(options[i].Price, conv.settlement(), conv.presentment(cart)); err != nil {
+			return nil, err
+		}
+	}
+	return options, nil
+}
+
+// SelectShippingOption chooses one of the quoted shipping options for a cart
+func (s *CartService) SelectShippingOption(cartID int, optionID string) error {
+	options, err := s.GetShippingOptions(cartID)
+	if err != nil {
+		return err
+	}
+
+	for _, option := range options {
+		if option.OptionID == optionID {
+			return s.cartRepo.SetShippingOption(cartID, optionID)
+		}
+	}
+
+	return ErrShippingOption
+}
+
+// GetOrder retrieves an order
+func (s *CartService) GetOrder(orderID int) (*model.Order, error) {
+	if orderID < 1 {
+		return nil, ErrInvalidCart
+	}
+
+	order, err := s.orderRepo.GetByID(orderID)
+	if err == repository.ErrOrderNotFound {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Refresh backordered lines with what the warehouse has allocated since
+	if hasBackorders(order) {
+		if err := s.refreshBackorders(order); err != nil {
+			log.Printf("Failed to refresh backorders for order %d: %v", order.OrderID, err)
+		}
+	}
+
+	// Follow payments held for fraud review until they are released or canceled
+	if err := s.refreshPayments(order); err != nil {
+		log.Printf("Failed to refresh payments for order %d: %v", order.OrderID, err)
+	}
+
+	// Pick up shipments and carrier tracking until everything is delivered
+	if order.ShipmentStatus != model.OrderDelivered {
+		if err := s.refreshShipments(order); err != nil {
+			log.Printf("Failed to refresh shipments for order %d: %v", order.OrderID, err)
+		}
+	}
+
+	return order, nil
+}
+
+// refreshBackorders updates the backordered quantities on an order's lines
+func (s *CartService) refreshBackorders(order *model.Order) error {
+	backorders, err := s.warehouseClient.ListBackorders(strconv.Itoa(order.OrderID))
+	if err != nil {
+		return err
+	}
+
+	lines := []model.BackorderLine{}
+	for _, backorder := range backorders {
+		if backorder.Status == model.BackorderCancelled {
+			continue
+		}
+		if remaining := backorder.Quantity - backorder.Allocated; remaining > 0 {
+			lines = append(lines, model.BackorderLine{
+				BackorderID:      backorder.BackorderID,
+				ProductID:        backorder.ProductID,
+				Quantity:         remaining,
+				ExpectedShipDate: backorder.ExpectedShipDate,
+				ReleaseDate:      backorder.ReleaseDate,
+			})
+		}
+	}
+	applyBackorders(order.Lines, lines)
+	return nil
+}
+
+// refreshPayments records the current status of an order's payments that
+// are held for fraud review
+func (s *CartService) refreshPayments(order *model.Order) error {
+	for i := range order.Payments {
+		if order.Payments[i].Status != model.PaymentPendingReview {
+			continue
+		}
+
+		payment, err := s.paymentClient.Get(order.Payments[i].PaymentID)
+		if err != nil {
+			return err
+		}
+		order.Payments[i].Status = payment.Status
+		if payment.PaymentID == order.PaymentID {
+			order.PaymentStatus = payment.Status
+		}
+		if err := s.orderRepo.SetPaymentStatus(order.OrderID, payment.PaymentID, payment.Status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshShipments records the warehouse's shipments for an order and how
+// far they have got
+func (s *CartService) refreshShipments(order *model.Order) error {
+	shipments, err := s.warehouseClient.ListShipments(strconv.Itoa(order.OrderID))
+	if err != nil {
+		return err
+	}
+
+	ordered, shipped := 0, 0
+	delivered := true
+	for _, line := range order.Lines {
+		ordered += line.Quantity
+	}
+	order.Shipments = make([]model.OrderShipment, 0, len(shipments))
+	for _, shipment := range shipments {
+		order.Shipments = append(order.Shipments, model.OrderShipment{
+			ShipmentID:     shipment.ShipmentID,
+			WarehouseID:    shipment.WarehouseID,
+			Carrier:        shipment.Carrier,
+			TrackingNumber: shipment.TrackingNumber,
+			Status:         shipment.Status,
+			ShippedAt:      shipment.CreatedAt,
+			DeliveredAt:    shipment.DeliveredAt,
+		})
+		for _, carton := range shipment.Cartons {
+			for _, item := range carton.Items {
+				shipped += item.Quantity
+			}
+		}
+		if shipment.Status != model.ShipmentDelivered {
+			delivered = false
+		}
+	}
+
+	switch {
+	case shipped == 0:
+		order.ShipmentStatus = model.OrderUnshipped
+	case shipped < ordered:
+		order.ShipmentStatus = model.OrderPartiallyShipped
+	case delivered:
+		order.ShipmentStatus = model.OrderDelivered
+	default:
+		order.ShipmentStatus = model.OrderShipped
+	}
+
+	return s.orderRepo.SetShipments(order.OrderID, order.ShipmentStatus, order.Shipments)
+}
+
+// hasBackorders reports whether any line of an order was waiting for stock
+func hasBackorders(order *model.Order) bool {
+	for _, line := range order.Lines {
+		if line.Backordered > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // GetCart retrieves a cart
@@ -104,5 +731,251 @@ func (s *CartService) GetCart(cartID int) (*model.Cart, error) {
 	if err == repository.ErrCartNotFound {
 		return nil, ErrCartNotFound
 	}
-	return cart, err
+	if err != nil {
+		return nil, err
+	}
+
+	// Attach current prices and tax
+	products, err := s.loadProducts(cart)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.priceCart(cart, products)
+	if err != nil {
+		return nil, err
+	}
+	cart.Totals = totals
+
+	return cart, nil
+}
+
+// loadProducts fetches the current product data for every item in a cart
+func (s *CartService) loadProducts(cart *model.Cart) (map[int]*model.Product, error) {
+	products := make(map[int]*model.Product, len(cart.Items))
+	for _, item := range cart.Items {
+		product, err := s.productClient.GetProduct(item.ProductID)
+		if err == client.ErrProductNotFound {
+			return nil, ErrProductNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		products[item.ProductID] = product
+	}
+
+	return products, nil
+}
+
+// quoteShipping packs the cart into cartons and quotes shipping for them
+func (s *CartService) quoteShipping(cart *model.Cart, products map[int]*model.Product) ([]model.ShippingOption, error) {
+	items := make([]shipping.Item, 0, len(cart.Items))
+	for _, cartItem := range cart.Items {
+		product := products[cartItem.ProductID]
+		weight, err := product.WeightIn(units.Gram)
+		if err != nil {
+			return nil, err
+		}
+		length, width, height, err := product.DimensionsIn(units.Millimeter)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, shipping.Item{
+			ProductID: product.ProductID,
+			Quantity:  cartItem.Quantity,
+			Length:    length,
+			Width:     width,
+			Height:    height,
+			Weight:    weight,
+		})
+	}
+
+	cartons, err := s.packer.Pack(items)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := s.rateTable.Quote(cartons, cart.ShippingAddress)
+	if err == shipping.ErrMissingDestination {
+		return nil, ErrMissingAddress
+	}
+	if err == shipping.ErrUndeliverable {
+		return nil, ErrUndeliverable
+	}
+	return options, err
+}
+
+// priceCart looks up current product prices and applies tax and the
+// selected shipping option for the cart's destination. The cart is priced
+// in the settlement currency, converting products priced in other
+// currencies, and then converted to the currency the customer sees.
+func (s *CartService) priceCart(cart *model.Cart, products map[int]*model.Product) (*model.CartTotals, error) {
+	conv := s.converterFor(cart)
+	settlement := conv.settlement()
+
+	req := &tax.Request{
+		Address: cart.ShippingAddress,
+		Lines:   make([]tax.Line, 0, len(cart.Items)),
+	}
+	for _, item := range cart.Items {
+		product := products[item.ProductID]
+		priceCurrency := product.Currency
+		if priceCurrency == "" {
+			priceCurrency = settlement
+		}
+		price, err := conv.convert(product.Price, priceCurrency, settlement)
+		if err != nil {
+			return nil, err
+		}
+		req.Lines = append(req.Lines, tax.Line{
+			ProductID:  item.ProductID,
+			CategoryID: product.CategoryID,
+			Quantity:   item.Quantity,
+			UnitPrice:  price,
+		})
+	}
+
+	result, err := s.taxCalculator.Calculate(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Lines) != len(req.Lines) {
+		return nil, errors.New("tax calculator returned mismatched lines")
+	}
+
+	totals := &model.CartTotals{
+		Lines:    make([]model.OrderLine, 0, len(result.Lines)),
+		Currency: settlement,
+	}
+	for i, line := range result.Lines {
+		totals.Lines = append(totals.Lines, model.OrderLine{
+			ProductID:   line.ProductID,
+			Quantity:    req.Lines[i].Quantity,
+			UnitPrice:   req.Lines[i].UnitPrice,
+			NetAmount:   line.Net,
+			TaxRate:     line.Rate,
+			TaxAmount:   line.Tax,
+			GrossAmount: line.Gross,
+		})
+		totals.Subtotal += line.Net
+		totals.Tax += line.Tax
+		totals.Total += line.Gross
+	}
+
+	// Price the selected shipping option if it is still on offer
+	if cart.ShippingOptionID != "" && cart.ShippingAddress != nil && len(cart.Items) > 0 {
+		options, err := s.quoteShipping(cart, products)
+		if err != nil && err != ErrUndeliverable {
+			return nil, err
+		}
+		for _, option := range options {
+			if option.OptionID == cart.ShippingOptionID {
+				selected := option
+				totals.ShippingOption = &selected
+				totals.Shipping = option.Price
+				totals.Total += option.Price
+				break
+			}
+		}
+	}
+
+	totals.Settlement = &model.SettlementTotals{
+		Currency: settlement,
+		Subtotal: totals.Subtotal,
+		Tax:      totals.Tax,
+		Shipping: totals.Shipping,
+		Total:    totals.Total,
+	}
+	if err := conv.present(totals, conv.presentment(cart)); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// converterFor returns a converter at the rates a cart is priced at: the
+// ones locked when its currency was chosen, or else the current ones
+func (s *CartService) converterFor(cart *model.Cart) *converter {
+	if cart.RateLock != nil {
+		return &converter{rates: cart.RateLock.Rates, rounding: cart.RateLock.Rounding}
+	}
+	return &converter{rates: s.exchangeRates.Rates(), rounding: model.RoundHalfUp}
+}
+
+// converter converts amounts between currencies at one set of rates
+type converter struct {
+	rates    model.ExchangeRates
+	rounding model.RoundingMode
+}
+
+// settlement returns the currency the store settles in
+func (c *converter) settlement() string {
+	return c.rates.Base
+}
+
+// presentment returns the currency a cart is shown and paid in
+func (c *converter) presentment(cart *model.Cart) string {
+	if cart.Currency == "" {
+		return c.rates.Base
+	}
+	return cart.Currency
+}
+
+// convert converts an amount from one currency to another
+func (c *converter) convert(amount int, from, to string) (int, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	rate, err := currency.Rate(c.rates, from, to)
+	if errors.Is(err, currency.ErrNoRate) || errors.Is(err, currency.ErrUnknownCurrency) {
+		return 0, ErrCurrency
+	}
+	if err != nil {
+		return 0, err
+	}
+	return currency.Convert(amount, from, to, rate, c.rounding)
+}
+
+// present converts priced totals to the currency the customer sees. Each
+// line's unit price, net amount and tax, and the shipping price, are
+// converted and rounded on their own; gross amounts and totals are then
+// summed from the converted amounts so that they always add up.
+func (c *converter) present(totals *model.CartTotals, to string) error {
+	from := totals.Currency
+	if from == to {
+		return nil
+	}
+
+	totals.Subtotal, totals.Tax, totals.Shipping, totals.Total = 0, 0, 0, 0
+	var err error
+	for i := range totals.Lines {
+		line := &totals.Lines[i]
+		if line.UnitPrice, err = c.convert(line.UnitPrice, from, to); err != nil {
+			return err
+		}
+		if line.NetAmount, err = c.convert(line.NetAmount, from, to); err != nil {
+			return err
+		}
+		if line.TaxAmount, err = c.convert(line.TaxAmount, from, to); err != nil {
+			return err
+		}
+		line.GrossAmount = line.NetAmount + line.TaxAmount
+		totals.Subtotal += line.NetAmount
+		totals.Tax += line.TaxAmount
+		totals.Total += line.GrossAmount
+	}
+
+	if totals.ShippingOption != nil {
+		option := *totals.ShippingOption
+		if option.Price, err = c.convert(option.Price, from, to); err != nil {
+			return err
+		}
+		totals.ShippingOption = &option
+		totals.Shipping = option.Price
+		totals.Total += option.Price
+	}
+
+	totals.Currency = to
+	return nil
 }
@@ -0,0 +1,234 @@
+package shipping
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var (
+	ErrEmptyShipment   = errors.New("nothing to pack")
+	ErrInvalidQuantity = errors.New("item quantity must be positive")
+)
+
+// OwnPackaging names cartons for items too large for any catalog box
+const OwnPackaging = "own-packaging"
+
+// Box is a carton size from the box catalog. Dimensions are inner
+// measurements in millimeters and weights are in grams.
+type Box struct {
+	Name       string
+	Length     float64
+	Width      float64
+	Height     float64
+	MaxWeight  float64
+	TareWeight float64
+}
+
+// Item is a product to be packed, Quantity units of it. Dimensions and
+// weight are per unit, in millimeters and grams; items without dimensions
+// take up no space.
+type Item struct {
+	ProductID int
+	Quantity  int
+	Length    float64
+	Width     float64
+	Height    float64
+	Weight    float64
+}
+
+// Carton is a packed box ready to be quoted. Items lists the products in
+// it with how many units of each.
+type Carton struct {
+	Box    string
+	Length float64
+	Width  float64
+	Height float64
+	Weight float64
+	Items  []Item
+}
+
+// DefaultBoxes is the built-in box catalog
+var DefaultBoxes = []Box{
+	{Name: "small", Length: 220, Width: 160, Height: 100, MaxWeight: 5000, TareWeight: 150},
+	{Name: "medium", Length: 350, Width: 250, Height: 200, MaxWeight: 15000, TareWeight: 350},
+	{Name: "large", Length: 500, Width: 400, Height: 350, MaxWeight: 25000, TareWeight: 700},
+	{Name: "xlarge", Length: 700, Width: 500, Height: 500, MaxWeight: 30000, TareWeight: 1100},
+}
+
+// Packer assigns items to cartons from a box catalog
+type Packer struct {
+	boxes []Box
+}
+
+func NewPacker(boxes []Box) *Packer {
+	sorted := make([]Box, len(boxes))
+	copy(sorted, boxes)
+	sort.Slice(sorted, func(i, j int) bool { return boxVolume(sorted[i]) < boxVolume(sorted[j]) })
+	return &Packer{boxes: sorted}
+}
+
+// openCarton tracks the remaining capacity of a carton while packing
+type openCarton struct {
+	box    Box
+	volume float64
+	weight float64
+	items  []Item
+}
+
+// Pack places items into as few cartons as it can using first-fit
+// decreasing by volume, then shrinks each carton to the smallest box
+// that still holds its contents. Each carton takes as many units of an
+// item as it has room for at once, so the work done grows with the number
+// of cartons rather than the number of units. Item dimensions are checked
+// in every orientation against the box, while the contents as a whole are
+// only checked by volume.
+func (p *Packer) Pack(items []Item) ([]Carton, error) {
+	if len(items) == 0 {
+		return nil, ErrEmptyShipment
+	}
+	for _, item := range items {
+		if item.Quantity < 1 {
+			return nil, ErrInvalidQuantity
+		}
+	}
+
+	sorted := make([]Item, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool { return itemVolume(sorted[i]) > itemVolume(sorted[j]) })
+
+	var open []*openCarton
+	var cartons []Carton
+	for _, item := range sorted {
+		remaining := item.Quantity
+		for _, carton := range open {
+			if remaining == 0 {
+				break
+			}
+			if n := carton.room(item, remaining); n > 0 {
+				carton.add(item, n)
+				remaining -= n
+			}
+		}
+
+		unit := item
+		unit.Quantity = 1
+		for remaining > 0 {
+			box, ok := p.smallestBoxFor([]Item{unit})
+			if !ok {
+				// Nothing in the catalog holds it, so each unit ships as is
+				for ; remaining > 0; remaining-- {
+					cartons = append(cartons, Carton{
+						Box:    OwnPackaging,
+						Length: item.Length,
+						Width:  item.Width,
+						Height: item.Height,
+						Weight: item.Weight,
+						Items:  []Item{unit},
+					})
+				}
+				break
+			}
+
+			carton := &openCarton{box: box}
+			n := carton.room(item, remaining)
+			carton.add(item, n)
+			remaining -= n
+			open = append(open, carton)
+		}
+	}
+
+	for _, carton := range open {
+		box := carton.box
+		if smaller, ok := p.smallestBoxFor(carton.items); ok {
+			box = smaller
+		}
+
+		cartons = append(cartons, Carton{
+			Box:    box.Name,
+			Length: box.Length,
+			Width:  box.Width,
+			Height: box.Height,
+			Weight: carton.weight + box.TareWeight,
+			Items:  carton.items,
+		})
+	}
+
+	return cartons, nil
+}
+
+// smallestBoxFor finds the smallest catalog box that holds every item
+func (p *Packer) smallestBoxFor(items []Item) (Box, bool) {
+	for _, box := range p.boxes {
+		carton := &openCarton{box: box}
+		fits := true
+		for _, item := range items {
+			if carton.room(item, item.Quantity) < item.Quantity {
+				fits = false
+				break
+			}
+			carton.add(item, item.Quantity)
+		}
+		if fits {
+			return box, true
+		}
+	}
+	return Box{}, false
+}
+
+// room returns how many units of an item, up to limit, still fit in the carton
+func (c *openCarton) room(item Item, limit int) int {
+	if !fitsWithin(item, c.box) {
+		return 0
+	}
+
+	n := limit
+	if c.box.MaxWeight > 0 {
+		spare := c.box.MaxWeight - c.box.TareWeight - c.weight
+		if spare < 0 {
+			return 0
+		}
+		if item.Weight > 0 {
+			n = min(n, int(math.Floor(spare/item.Weight)))
+		}
+	}
+	if unitVolume := itemVolume(item); unitVolume > 0 {
+		n = min(n, int(math.Floor((boxVolume(c.box)-c.volume)/unitVolume)))
+	}
+	return max(n, 0)
+}
+
+// add puts n units of an item in the carton
+func (c *openCarton) add(item Item, n int) {
+	item.Quantity = n
+	c.items = append(c.items, item)
+	c.volume += itemVolume(item) * float64(n)
+	c.weight += item.Weight * float64(n)
+}
+
+// fitsWithin reports whether an item fits inside a box in some orientation
+func fitsWithin(item Item, box Box) bool {
+	itemDims := []float64{item.Length, item.Width, item.Height}
+	boxDims := []float64{box.Length, box.Width, box.Height}
+	sort.Float64s(itemDims)
+	sort.Float64s(boxDims)
+	for i := range itemDims {
+		if itemDims[i] > boxDims[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// itemVolume returns the volume of one unit of an item
+func itemVolume(item Item) float64 {
+	return volume(item.Length, item.Width, item.Height)
+}
+
+func boxVolume(box Box) float64 {
+	return volume(box.Length, box.Width, box.Height)
+}
+
+func volume(length, width, height float64) float64 {
+	return length * width * height
+}
@@ -0,0 +1,215 @@
+package shipping
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrMissingDestination = errors.New("shipping destination is required")
+	ErrUndeliverable      = errors.New("destination is not served by any carrier")
+)
+
+// AnyCountry matches every destination in a ZoneRule
+const AnyCountry = "*"
+
+// ZoneRule maps a destination to a shipping zone. An empty Regions list
+// matches the whole country. Rules are evaluated in order.
+type ZoneRule struct {
+	Zone    string
+	Country string
+	Regions []string
+}
+
+// DefaultDimDivisor is the dimensional weight divisor in cubic centimeters per kilogram
+const DefaultDimDivisor = 5000
+
+// WeightBand prices a package up to and including MaxWeight grams
+type WeightBand struct {
+	MaxWeight int
+	Price     int
+}
+
+// Service is a carrier service level with weight bands per zone.
+// Zones missing from Rates are not served. Each package is billed at
+// the greater of its actual and dimensional weight, using DimDivisor
+// or DefaultDimDivisor when it is zero.
+type Service struct {
+	OptionID     string
+	Carrier      string
+	ServiceLevel string
+	TransitDays  int
+	DimDivisor   float64
+	Rates        map[string][]WeightBand
+}
+
+// DefaultZones is the built-in destination zone table
+var DefaultZones = []ZoneRule{
+	{Zone: "US-WEST", Country: "US", Regions: []string{"CA", "OR", "WA", "NV", "AZ"}},
+	{Zone: "US", Country: "US"},
+	{Zone: "EU", Country: "DE"},
+	{Zone: "EU", Country: "FR"},
+	{Zone: "EU", Country: "GB"},
+	{Zone: "INTL", Country: AnyCountry},
+}
+
+// DefaultServices is the built-in carrier rate table
+var DefaultServices = []Service{
+	{
+		OptionID:     "ups-ground",
+		Carrier:      "UPS",
+		ServiceLevel: "Ground",
+		TransitDays:  5,
+		Rates: map[string][]WeightBand{
+			"US-WEST": {{MaxWeight: 1000, Price: 599}, {MaxWeight: 5000, Price: 899}, {MaxWeight: 20000, Price: 1899}},
+			"US":      {{MaxWeight: 1000, Price: 799}, {MaxWeight: 5000, Price: 1199}, {MaxWeight: 20000, Price: 2499}},
+		},
+	},
+	{
+		OptionID:     "ups-2day",
+		Carrier:      "UPS",
+		ServiceLevel: "2nd Day Air",
+		TransitDays:  2,
+		Rates: map[string][]WeightBand{
+			"US-WEST": {{MaxWeight: 1000, Price: 1499}, {MaxWeight: 5000, Price: 2499}, {MaxWeight: 20000, Price: 4999}},
+			"US":      {{MaxWeight: 1000, Price: 1799}, {MaxWeight: 5000, Price: 2999}, {MaxWeight: 20000, Price: 5999}},
+		},
+	},
+	{
+		OptionID:     "dhl-express",
+		Carrier:      "DHL",
+		ServiceLevel: "Express Worldwide",
+		TransitDays:  3,
+		Rates: map[string][]WeightBand{
+			"US-WEST": {{MaxWeight: 2000, Price: 2999}, {MaxWeight: 10000, Price: 5999}, {MaxWeight: 30000, Price: 11999}},
+			"US":      {{MaxWeight: 2000, Price: 2999}, {MaxWeight: 10000, Price: 5999}, {MaxWeight: 30000, Price: 11999}},
+			"EU":      {{MaxWeight: 2000, Price: 4499}, {MaxWeight: 10000, Price: 8999}, {MaxWeight: 30000, Price: 17999}},
+			"INTL":    {{MaxWeight: 2000, Price: 5999}, {MaxWeight: 10000, Price: 11999}, {MaxWeight: 30000, Price: 23999}},
+		},
+	},
+}
+
+// RateTable quotes shipping options from configured zones and weight bands
+type RateTable struct {
+	zones    []ZoneRule
+	services []Service
+}
+
+func NewRateTable(zones []ZoneRule, services []Service) *RateTable {
+	t := &RateTable{
+		zones:    zones,
+		services: make([]Service, 0, len(services)),
+	}
+	for _, service := range services {
+		// Keep bands sorted so the first band that fits is the cheapest
+		rates := make(map[string][]WeightBand, len(service.Rates))
+		for zone, bands := range service.Rates {
+			sorted := make([]WeightBand, len(bands))
+			copy(sorted, bands)
+			sort.Slice(sorted, func(i, j int) bool { return sorted[i].MaxWeight < sorted[j].MaxWeight })
+			rates[zone] = sorted
+		}
+		service.Rates = rates
+		t.services = append(t.services, service)
+	}
+	return t
+}
+
+// Zone resolves the shipping zone for an address
+func (t *RateTable) Zone(address *model.Address) (string, error) {
+	if address == nil {
+		return "", ErrMissingDestination
+	}
+
+	country := strings.ToUpper(address.Country)
+	region := strings.ToUpper(address.Region)
+	for _, rule := range t.zones {
+		if rule.Country != AnyCountry && !strings.EqualFold(rule.Country, country) {
+			continue
+		}
+		if len(rule.Regions) == 0 {
+			return rule.Zone, nil
+		}
+		for _, r := range rule.Regions {
+			if strings.EqualFold(r, region) {
+				return rule.Zone, nil
+			}
+		}
+	}
+
+	return "", ErrUndeliverable
+}
+
+// Quote returns every service able to carry all cartons to address, cheapest first.
+// Each carton is priced as a separate package.
+func (t *RateTable) Quote(cartons []Carton, address *model.Address) ([]model.ShippingOption, error) {
+	zone, err := t.Zone(address)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []model.ShippingOption{}
+	for _, service := range t.services {
+		bands, served := service.Rates[zone]
+		if !served {
+			continue
+		}
+
+		option := model.ShippingOption{
+			OptionID:     service.OptionID,
+			Carrier:      service.Carrier,
+			ServiceLevel: service.ServiceLevel,
+			Zone:         zone,
+			Packages:     len(cartons),
+			TransitDays:  service.TransitDays,
+		}
+		available := true
+		for _, carton := range cartons {
+			weight := service.billableWeight(carton)
+			price, ok := priceForWeight(bands, weight)
+			if !ok {
+				available = false
+				break
+			}
+			option.Weight += weight
+			option.Price += price
+		}
+
+		if available {
+			options = append(options, option)
+		}
+	}
+
+	if len(options) == 0 {
+		return nil, ErrUndeliverable
+	}
+
+	sort.SliceStable(options, func(i, j int) bool { return options[i].Price < options[j].Price })
+	return options, nil
+}
+
+// billableWeight is the greater of a carton's actual and dimensional weight, in whole grams
+func (s Service) billableWeight(carton Carton) int {
+	divisor := s.DimDivisor
+	if divisor <= 0 {
+		divisor = DefaultDimDivisor
+	}
+
+	// Cubic millimeters over cubic centimeters per kilogram gives grams
+	dimWeight := volume(carton.Length, carton.Width, carton.Height) / divisor
+	return int(math.Ceil(math.Max(carton.Weight, dimWeight)))
+}
+
+// priceForWeight finds the first band that covers weight
+func priceForWeight(bands []WeightBand, weight int) (int, bool) {
+	for _, band := range bands {
+		if weight <= band.MaxWeight {
+			return band.Price, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,54 @@
+package tax
+
+import (
+	"errors"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrInvalidRequest = errors.New("invalid tax request")
+)
+
+// Mode describes whether line prices already include tax
+type Mode string
+
+const (
+	ModeExclusive Mode = "exclusive"
+	ModeInclusive Mode = "inclusive"
+)
+
+// Line is a single priced line submitted for tax calculation
+type Line struct {
+	ProductID  int
+	CategoryID int
+	Quantity   int
+	UnitPrice  int
+}
+
+// Request is the input to a tax calculation
+type Request struct {
+	Address *model.Address
+	Lines   []Line
+}
+
+// LineResult is the tax outcome for a single line
+type LineResult struct {
+	ProductID int
+	Rate      float64
+	Net       int
+	Tax       int
+	Gross     int
+}
+
+// Result is the outcome of a tax calculation
+type Result struct {
+	Mode  Mode
+	Lines []LineResult
+}
+
+// Calculator computes tax for a set of lines shipped to an address.
+// Implementations may be table driven or backed by an external tax engine.
+type Calculator interface {
+	Calculate(req *Request) (*Result, error)
+}
@@ -0,0 +1,116 @@
+package tax
+
+import (
+	"math"
+	"strings"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+// JurisdictionRule holds the tax rates for a single jurisdiction.
+// Code is either an ISO country code ("DE") or a country and region
+// pair ("US-CA"); the more specific code wins when both are present.
+type JurisdictionRule struct {
+	Code          string
+	Mode          Mode
+	StandardRate  float64
+	CategoryRates map[int]float64
+}
+
+// DefaultRules is the built-in rate table
+var DefaultRules = []JurisdictionRule{
+	{Code: "US-CA", Mode: ModeExclusive, StandardRate: 0.0725},
+	{Code: "US-NY", Mode: ModeExclusive, StandardRate: 0.04},
+	{Code: "US-WA", Mode: ModeExclusive, StandardRate: 0.065},
+	{Code: "US", Mode: ModeExclusive, StandardRate: 0},
+	{Code: "DE", Mode: ModeInclusive, StandardRate: 0.19, CategoryRates: map[int]float64{1: 0.07}},
+	{Code: "GB", Mode: ModeInclusive, StandardRate: 0.20, CategoryRates: map[int]float64{1: 0}},
+}
+
+// TableCalculator calculates tax from a static table of jurisdiction rules
+type TableCalculator struct {
+	rules map[string]JurisdictionRule
+}
+
+func NewTableCalculator(rules []JurisdictionRule) *TableCalculator {
+	c := &TableCalculator{
+		rules: make(map[string]JurisdictionRule, len(rules)),
+	}
+	for _, rule := range rules {
+		c.rules[strings.ToUpper(rule.Code)] = rule
+	}
+	return c
+}
+
+// Calculate computes tax for each line, rounding per line.
+// Lines shipped to an unknown jurisdiction are not taxed.
+func (c *TableCalculator) Calculate(req *Request) (*Result, error) {
+	if req == nil {
+		return nil, ErrInvalidRequest
+	}
+
+	rule, found := c.resolve(req.Address)
+	result := &Result{
+		Mode:  ModeExclusive,
+		Lines: make([]LineResult, 0, len(req.Lines)),
+	}
+	if found {
+		result.Mode = rule.Mode
+	}
+
+	for _, line := range req.Lines {
+		if line.Quantity < 1 || line.UnitPrice < 0 {
+			return nil, ErrInvalidRequest
+		}
+
+		rate := 0.0
+		if found {
+			rate = rule.StandardRate
+			if categoryRate, ok := rule.CategoryRates[line.CategoryID]; ok {
+				rate = categoryRate
+			}
+		}
+
+		amount := line.UnitPrice * line.Quantity
+		lineResult := LineResult{
+			ProductID: line.ProductID,
+			Rate:      rate,
+		}
+		if result.Mode == ModeInclusive {
+			// Price already contains tax, so extract it from the gross amount
+			lineResult.Gross = amount
+			lineResult.Tax = roundHalfUp(float64(amount) * rate / (1 + rate))
+			lineResult.Net = amount - lineResult.Tax
+		} else {
+			lineResult.Net = amount
+			lineResult.Tax = roundHalfUp(float64(amount) * rate)
+			lineResult.Gross = amount + lineResult.Tax
+		}
+
+		result.Lines = append(result.Lines, lineResult)
+	}
+
+	return result, nil
+}
+
+// resolve finds the most specific rule for an address
+func (c *TableCalculator) resolve(address *model.Address) (JurisdictionRule, bool) {
+	if address == nil {
+		return JurisdictionRule{}, false
+	}
+
+	country := strings.ToUpper(address.Country)
+	if address.Region != "" {
+		if rule, ok := c.rules[country+"-"+strings.ToUpper(address.Region)]; ok {
+			return rule, true
+		}
+	}
+
+	rule, ok := c.rules[country]
+	return rule, ok
+}
+
+// roundHalfUp rounds a minor-unit amount to the nearest integer, with halves rounded away from zero
+func roundHalfUp(amount float64) int {
+	return int(math.Round(amount))
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+
+	_ "github.com/gocart-v2/payment-service/docs"
+	"github.com/gocart-v2/payment-service/internal/fraud"
+	"github.com/gocart-v2/payment-service/internal/gateway"
+	"github.com/gocart-v2/payment-service/internal/handler"
+	"github.com/gocart-v2/payment-service/internal/repository"
+	"github.com/gocart-v2/payment-service/internal/router"
+	"github.com/gocart-v2/payment-service/internal/service"
+	"github.com/gocart-v2/payment-service/internal/vault"
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/shared/webhook"
+)
+
+// @title E-commerce API
+// @version 1.0.0
+// @description API for managing products, shopping carts, warehouse operations, and credit card processing
+// @contact.name API Support
+// @contact.email support@example.com
+// @license.name MIT
+// @license.url https://opensource.org/licenses/MIT
+// @BasePath /v1
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @securityDefinitions.bearer BearerAuth
+// @tag.name Payment
+// @tag.description Card and gift card payment operations
+// @tag.name Ledger
+// @tag.description Double-entry payment ledger
+// @tag.name Fraud
+// @tag.description Fraud screening, manual review and deny lists
+// @tag.name Settlement
+// @tag.description Processor settlement imports and reconciliation
+// @tag.name Dispute
+// @tag.description Chargebacks, evidence and rulings
+// @tag.name Webhook
+// @tag.description Signed outbound event notifications
+func main() {
+
+	rh := handler.NewRootHandler()
+
+	gw := gateway.NewFakeGateway()
+	vc, err := vault.NewCipher(loadVaultKey())
+	if err != nil {
+		log.Fatal("Failed to set up card vault:", err)
+	}
+
+	tr := repository.NewTokenRepository()
+	ts := service.NewTokenService(tr, vc)
+	th := handler.NewTokenHandler(ts)
+
+	mr := repository.NewPaymentMethodRepository()
+	ms := service.NewPaymentMethodService(mr, ts)
+	mh := handler.NewPaymentMethodHandler(ms)
+
+	gr := repository.NewGiftCardRepository()
+	gs := service.NewGiftCardService(gr)
+	gh := handler.NewGiftCardHandler(gs)
+
+	lr := repository.NewLedgerRepository()
+	ls := service.NewLedgerService(lr, service.DefaultFeeSchedule)
+	lh := handler.NewLedgerHandler(ls)
+	go ls.RunRetry(time.Minute)
+
+	dr := repository.NewDenyListRepository()
+	fs := service.NewFraudService(dr, fraud.NewEngine(fraud.DefaultRules(dr), fraud.DefaultThresholds))
+
+	wd := webhook.NewDispatcher([]string{
+		model.EventPaymentCaptured,
+		model.EventPaymentRefunded,
+		model.EventDisputeOpened,
+		model.EventDisputeDue,
+		model.EventDisputeClosed,
+	}, webhook.DefaultRetryPolicy)
+	wh := handler.NewWebhookHandler(wd)
+	go wd.Run(10 * time.Second)
+
+	pr := repository.NewPaymentRepository()
+	ps := service.NewPaymentService(pr, ts, ms, gs, ls, fs, gw, wd)
+	ph := handler.NewPaymentHandler(ps)
+	fh := handler.NewFraudHandler(ps, fs)
+
+	sr := repository.NewSettlementRepository()
+	ss := service.NewSettlementService(sr, ps)
+	sh := handler.NewSettlementHandler(ss)
+
+	dpr := repository.NewDisputeRepository()
+	dps := service.NewDisputeService(dpr, ps, ls, wd, service.DefaultReminders)
+	dph := handler.NewDisputeHandler(dps)
+	go dps.RunReminders(15 * time.Minute)
+
+	e := gin.Default()
+	router.SetupRoutes(e, &router.AllHandlers{
+		RootHandler:          rh,
+		PaymentHandler:       ph,
+		TokenHandler:         th,
+		PaymentMethodHandler: mh,
+		GiftCardHandler:      gh,
+		LedgerHandler:        lh,
+		FraudHandler:         fh,
+		SettlementHandler:    sh,
+		DisputeHandler:       dph,
+		WebhookHandler:       wh,
+		SwaggerHandler:       swaggerFiles.Handler,
+	})
+
+	log.Println("Starting server on :8083")
+	if err := e.Run(":8083"); err != nil {
+		log.Fatal("Failed to start server:", err)
+	}
+}
+
+// loadVaultKey reads the hex encoded card vault key from CARD_VAULT_KEY. A
+// random key is used when it is unset, so cards tokenized by one run of the
+// service cannot be read by the next.
+func loadVaultKey() []byte {
+	if encoded := os.Getenv("CARD_VAULT_KEY"); encoded != "" {
+		key, err := vault.ParseKey(encoded)
+		if err != nil {
+			log.Fatal("Invalid CARD_VAULT_KEY:", err)
+		}
+		return key
+	}
+
+	log.Println("CARD_VAULT_KEY is not set; using a random key for this run")
+	key, err := vault.GenerateKey()
+	if err != nil {
+		log.Fatal("Failed to generate card vault key:", err)
+	}
+	return key
+}
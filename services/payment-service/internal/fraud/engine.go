@@ -0,0 +1,108 @@
+package fraud
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+// retention is how long attempts are remembered for velocity rules
+const retention = 24 * time.Hour
+
+// Input is what is known about a payment when it is screened
+type Input struct {
+	CustomerID      int
+	CardToken       string
+	Amount          int
+	Currency        string
+	BillingCountry  string
+	ShippingCountry string
+	IPCountry       string
+	At              time.Time
+
+	// Attempts are the earlier screenings still remembered, oldest first
+	Attempts []Attempt
+}
+
+// Attempt is a payment that was screened, kept for velocity rules
+type Attempt struct {
+	CustomerID int
+	CardToken  string
+	At         time.Time
+}
+
+// Rule is one fraud check. It returns the signal it raises, or nil when
+// the payment does not match.
+type Rule interface {
+	Evaluate(in *Input) *model.FraudSignal
+}
+
+// Thresholds turn a total score into a decision. Scores at or above
+// Decline are declined, at or above Review are held for review, and
+// anything lower is approved.
+type Thresholds struct {
+	Review  int
+	Decline int
+}
+
+// DefaultThresholds holds a payment once it looks suspicious and declines it
+// once it matches a deny list or several rules at once
+var DefaultThresholds = Thresholds{Review: 50, Decline: 100}
+
+// Engine scores payments against a set of rules and remembers recent
+// attempts so that rules can spot bursts of them
+type Engine struct {
+	rules      []Rule
+	thresholds Thresholds
+	attempts   []Attempt
+	mu         sync.Mutex
+}
+
+func NewEngine(rules []Rule, thresholds Thresholds) *Engine {
+	return &Engine{rules: rules, thresholds: thresholds, attempts: []Attempt{}}
+}
+
+// Screen scores a payment and records it as an attempt. The attempt counts
+// towards velocity whatever the decision, so that declined card testing
+// keeps being declined.
+func (e *Engine) Screen(in Input) *model.FraudScreening {
+	if in.At.IsZero() {
+		in.At = time.Now().UTC()
+	}
+
+	e.mu.Lock()
+	cutoff := in.At.Add(-retention)
+	kept := e.attempts[:0]
+	for _, attempt := range e.attempts {
+		if attempt.At.After(cutoff) {
+			kept = append(kept, attempt)
+		}
+	}
+	e.attempts = kept
+	in.Attempts = append([]Attempt(nil), e.attempts...)
+	e.attempts = append(e.attempts, Attempt{CustomerID: in.CustomerID, CardToken: in.CardToken, At: in.At})
+	e.mu.Unlock()
+
+	screening := &model.FraudScreening{Signals: []model.FraudSignal{}, ScreenedAt: in.At}
+	for _, rule := range e.rules {
+		if signal := rule.Evaluate(&in); signal != nil {
+			screening.Signals = append(screening.Signals, *signal)
+			screening.Score += signal.Score
+		}
+	}
+	screening.Decision = e.thresholds.decide(screening.Score)
+	return screening
+}
+
+// decide maps a score to a decision
+func (t Thresholds) decide(score int) model.FraudDecision {
+	switch {
+	case score >= t.Decline:
+		return model.FraudDecline
+	case score >= t.Review:
+		return model.FraudReview
+	default:
+		return model.FraudApprove
+	}
+}
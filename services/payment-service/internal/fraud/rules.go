@@ -0,0 +1,152 @@
+package fraud
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gocart-v2/shared/currency"
+	"github.com/gocart-v2/shared/model"
+)
+
+// VelocityKey is what a velocity rule counts attempts by
+type VelocityKey string
+
+const (
+	ByCustomer  VelocityKey = "customer"
+	ByCardToken VelocityKey = "card_token"
+)
+
+// VelocityRule matches when more than Limit attempts, including this one,
+// were made by the same customer or card within Window
+type VelocityRule struct {
+	By     VelocityKey
+	Window time.Duration
+	Limit  int
+	Score  int
+}
+
+func (r VelocityRule) Evaluate(in *Input) *model.FraudSignal {
+	key := in.CardToken
+	if r.By == ByCustomer {
+		if in.CustomerID == 0 {
+			return nil
+		}
+		key = strconv.Itoa(in.CustomerID)
+	}
+
+	count := 1
+	since := in.At.Add(-r.Window)
+	for _, attempt := range in.Attempts {
+		if !attempt.At.After(since) {
+			continue
+		}
+		if (r.By == ByCustomer && attempt.CustomerID == in.CustomerID) || (r.By == ByCardToken && attempt.CardToken == in.CardToken) {
+			count++
+		}
+	}
+	if count <= r.Limit {
+		return nil
+	}
+
+	return &model.FraudSignal{
+		Rule:   string(r.By) + "_velocity",
+		Score:  r.Score,
+		Reason: fmt.Sprintf("%d attempts by %s %s in %s", count, r.By, key, r.Window),
+	}
+}
+
+// AmountRule matches payments of at least Over, given in hundredths of the
+// major unit and scaled to the payment currency's minor units
+type AmountRule struct {
+	Over  int
+	Score int
+}
+
+func (r AmountRule) Evaluate(in *Input) *model.FraudSignal {
+	over, err := currency.FromHundredths(r.Over, in.Currency)
+	if err != nil || in.Amount < over {
+		return nil
+	}
+
+	return &model.FraudSignal{
+		Rule:   "amount",
+		Score:  r.Score,
+		Reason: fmt.Sprintf("amount %d is at least %d", in.Amount, over),
+	}
+}
+
+// CountryMismatchRule matches when the billing, shipping and IP countries
+// that were given do not all agree, scoring once for each extra country
+type CountryMismatchRule struct {
+	Score int
+}
+
+func (r CountryMismatchRule) Evaluate(in *Input) *model.FraudSignal {
+	countries := []string{}
+	seen := make(map[string]bool)
+	for _, country := range []string{in.BillingCountry, in.ShippingCountry, in.IPCountry} {
+		if country != "" && !seen[country] {
+			seen[country] = true
+			countries = append(countries, country)
+		}
+	}
+	if len(countries) < 2 {
+		return nil
+	}
+
+	return &model.FraudSignal{
+		Rule:   "country_mismatch",
+		Score:  r.Score * (len(countries) - 1),
+		Reason: fmt.Sprintf("billing %q, shipping %q and IP %q countries differ", in.BillingCountry, in.ShippingCountry, in.IPCountry),
+	}
+}
+
+// DenyList reports whether a value has been denied
+type DenyList interface {
+	Denied(kind model.DenyListKind, value string) bool
+}
+
+// DenyListRule matches payments from a denied customer, card token or country
+type DenyListRule struct {
+	List  DenyList
+	Score int
+}
+
+func (r DenyListRule) Evaluate(in *Input) *model.FraudSignal {
+	values := map[model.DenyListKind][]string{
+		model.DenyCardToken: {in.CardToken},
+		model.DenyCountry:   {in.BillingCountry, in.ShippingCountry, in.IPCountry},
+	}
+	if in.CustomerID != 0 {
+		values[model.DenyCustomer] = []string{strconv.Itoa(in.CustomerID)}
+	}
+
+	for _, kind := range []model.DenyListKind{model.DenyCustomer, model.DenyCardToken, model.DenyCountry} {
+		for _, value := range values[kind] {
+			if value != "" && r.List.Denied(kind, value) {
+				return &model.FraudSignal{
+					Rule:   "deny_list",
+					Score:  r.Score,
+					Reason: fmt.Sprintf("%s %s is on the deny list", kind, value),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// DefaultRules is the built-in rule set. Long bursts of attempts on one card,
+// which is what card testing looks like, are declined; other signals hold
+// the payment for review unless several match together.
+func DefaultRules(denyList DenyList) []Rule {
+	return []Rule{
+		DenyListRule{List: denyList, Score: 100},
+		VelocityRule{By: ByCardToken, Window: 10 * time.Minute, Limit: 3, Score: 50},
+		VelocityRule{By: ByCardToken, Window: time.Hour, Limit: 10, Score: 50},
+		VelocityRule{By: ByCustomer, Window: 10 * time.Minute, Limit: 5, Score: 50},
+		AmountRule{Over: 100000, Score: 30},
+		AmountRule{Over: 500000, Score: 30},
+		CountryMismatchRule{Score: 25},
+	}
+}
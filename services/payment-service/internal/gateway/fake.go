@@ -0,0 +1,181 @@
+package gateway
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/currency"
+	"github.com/gocart-v2/shared/model"
+)
+
+// Magic card numbers make the fake gateway fail authorizations in a
+// known way. Any other card number with a future expiry is approved.
+const (
+	DeclineCard           = "4000000000000002"
+	InsufficientFundsCard = "4000000000009995"
+	TimeoutCard           = "4000000000000408"
+	NetworkErrorCard      = "4000000000000507"
+)
+
+// Magic amounts make any fake gateway operation for that amount fail in
+// the same way as the matching card number. They are in hundredths of the
+// major unit, so 6602 is 66.02 in any currency: 6602 US cents, 66 yen.
+const (
+	DeclineAmount           = 6602
+	InsufficientFundsAmount = 6651
+	TimeoutAmount           = 6608
+	NetworkErrorAmount      = 6603
+)
+
+// FakeGateway is an in-memory gateway for development and tests. It never
+// sleeps: a timeout completes the operation and then reports ErrTimeout,
+// as a processor that answers too late would, while a network error leaves
+// the transaction untouched.
+type FakeGateway struct {
+	mu           sync.Mutex
+	transactions map[string]*Transaction
+}
+
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{
+		transactions: make(map[string]*Transaction),
+	}
+}
+
+// Name identifies the fake gateway
+func (g *FakeGateway) Name() string {
+	return "FAKE"
+}
+
+// Authorize approves the hold unless the card or amount is magic or the card has expired
+func (g *FakeGateway) Authorize(req *AuthorizeRequest) (*Transaction, error) {
+	outcome := magicOutcome(req.Card.Number, req.Amount, req.Currency)
+	if outcome == ErrNetwork {
+		return nil, ErrNetwork
+	}
+	if outcome == nil && CardExpired(req.Card.ExpMonth, req.Card.ExpYear, time.Now().UTC()) {
+		outcome = ErrExpiredCard
+	}
+	if outcome != nil && outcome != ErrTimeout {
+		return nil, outcome
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	transaction := &Transaction{
+		Reference: req.Reference,
+		Status:    model.PaymentAuthorized,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+	}
+	g.transactions[req.Reference] = transaction
+	return g.result(transaction, outcome)
+}
+
+// Capture takes amount from an authorized transaction
+func (g *FakeGateway) Capture(reference string, amount int) (*Transaction, error) {
+	return g.apply(reference, amount, func(transaction *Transaction) error {
+		if transaction.Status != model.PaymentAuthorized || amount > transaction.Amount {
+			return ErrDeclined
+		}
+		transaction.Status = model.PaymentCaptured
+		transaction.Captured = amount
+		return nil
+	})
+}
+
+// Void releases the hold of an authorized transaction
+func (g *FakeGateway) Void(reference string) (*Transaction, error) {
+	return g.apply(reference, 0, func(transaction *Transaction) error {
+		if transaction.Status != model.PaymentAuthorized {
+			return ErrDeclined
+		}
+		transaction.Status = model.PaymentVoided
+		return nil
+	})
+}
+
+// Refund returns amount of a captured transaction to the card
+func (g *FakeGateway) Refund(reference string, amount int) (*Transaction, error) {
+	return g.apply(reference, amount, func(transaction *Transaction) error {
+		if transaction.Status != model.PaymentCaptured && transaction.Status != model.PaymentPartiallyRefunded {
+			return ErrDeclined
+		}
+		if transaction.Refunded+amount > transaction.Captured {
+			return ErrDeclined
+		}
+		transaction.Refunded += amount
+		transaction.Status = model.PaymentPartiallyRefunded
+		if transaction.Refunded == transaction.Captured {
+			transaction.Status = model.PaymentRefunded
+		}
+		return nil
+	})
+}
+
+// Status looks up a transaction
+func (g *FakeGateway) Status(reference string) (*Transaction, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	transaction, exists := g.transactions[reference]
+	if !exists {
+		return nil, ErrUnknownTransaction
+	}
+	return g.result(transaction, nil)
+}
+
+// apply changes a transaction unless the amount is magic
+func (g *FakeGateway) apply(reference string, amount int, change func(*Transaction) error) (*Transaction, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	transaction, exists := g.transactions[reference]
+	if !exists {
+		return nil, ErrUnknownTransaction
+	}
+	outcome := magicOutcome("", amount, transaction.Currency)
+	if outcome != nil && outcome != ErrTimeout {
+		return nil, outcome
+	}
+	if err := change(transaction); err != nil {
+		return nil, err
+	}
+	return g.result(transaction, outcome)
+}
+
+// result returns a copy of a transaction, or only err when err is set
+func (g *FakeGateway) result(transaction *Transaction, err error) (*Transaction, error) {
+	if err != nil {
+		return nil, err
+	}
+	transactionCopy := *transaction
+	return &transactionCopy, nil
+}
+
+// magicOutcome returns the failure a magic card number or amount asks for
+func magicOutcome(cardNumber string, amount int, currencyCode string) error {
+	magic := func(hundredths int) bool {
+		scaled, err := currency.FromHundredths(hundredths, currencyCode)
+		return err == nil && amount == scaled
+	}
+
+	switch {
+	case cardNumber == DeclineCard || magic(DeclineAmount):
+		return ErrDeclined
+	case cardNumber == InsufficientFundsCard || magic(InsufficientFundsAmount):
+		return ErrInsufficientFunds
+	case cardNumber == TimeoutCard || magic(TimeoutAmount):
+		return ErrTimeout
+	case cardNumber == NetworkErrorCard || magic(NetworkErrorAmount):
+		return ErrNetwork
+	}
+	return nil
+}
+
+// CardExpired reports whether a card stopped working before now. Cards are
+// valid until the end of their expiry month.
+func CardExpired(month, year int, now time.Time) bool {
+	return !now.Before(time.Date(year, time.Month(month)+1, 1, 0, 0, 0, 0, time.UTC))
+}
@@ -0,0 +1,63 @@
+package gateway
+
+import (
+	"errors"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrDeclined           = errors.New("card was declined")
+	ErrInsufficientFunds  = errors.New("card has insufficient funds")
+	ErrExpiredCard        = errors.New("card has expired")
+	ErrTimeout            = errors.New("gateway did not respond in time")
+	ErrNetwork            = errors.New("gateway could not be reached")
+	ErrUnknownTransaction = errors.New("gateway has no transaction with this reference")
+)
+
+// Card is the card data a gateway needs to place a hold
+type Card struct {
+	Number     string
+	ExpMonth   int
+	ExpYear    int
+	HolderName string
+}
+
+// AuthorizeRequest describes a hold to place on a card. Reference is chosen
+// by the caller so that a transaction can be looked up even when the
+// gateway times out before answering.
+type AuthorizeRequest struct {
+	Reference string
+	Amount    int
+	Currency  string
+	Card      Card
+}
+
+// Transaction is the gateway's view of a payment
+type Transaction struct {
+	Reference string
+	Status    model.PaymentStatus
+	Amount    int
+	Currency  string
+	Captured  int
+	Refunded  int
+}
+
+// Gateway moves money with a card processor. ErrTimeout means the outcome
+// is unknown and should be resolved with Status; ErrNetwork means the
+// request never reached the processor. Implementations must be safe for
+// concurrent use.
+type Gateway interface {
+	// Name identifies the gateway on payment records
+	Name() string
+	// Authorize places a hold on a card
+	Authorize(req *AuthorizeRequest) (*Transaction, error)
+	// Capture takes amount from an authorized transaction
+	Capture(reference string, amount int) (*Transaction, error)
+	// Void releases the hold of an authorized transaction
+	Void(reference string) (*Transaction, error)
+	// Refund returns amount of a captured transaction to the card
+	Refund(reference string, amount int) (*Transaction, error)
+	// Status looks up a transaction by its reference
+	Status(reference string) (*Transaction, error)
+}
@@ -0,0 +1,349 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/payment-service/internal/service"
+	"github.com/gocart-v2/shared/model"
+)
+
+type DisputeHandler struct {
+	service *service.DisputeService
+}
+
+func NewDisputeHandler(service *service.DisputeService) *DisputeHandler {
+	return &DisputeHandler{service: service}
+}
+
+// OpenDispute handles POST /dispute
+// @Summary Open dispute
+// @Description Record a dispute the processor reported against a captured payment. The disputed amount is withheld in the ledger, and cannot be refunded or disputed again, until the dispute is decided; a won dispute makes it refundable again and a lost one keeps it withheld for good. Reminders are sent as the response deadline approaches, and a dispute not responded to by then is lost.
+// @ID openDispute
+// @Tags Dispute
+// @Accept json
+// @Produce json
+// @Param request body model.OpenDisputeRequest true "Payment, reason and deadline"
+// @Success 201 {object} model.Dispute
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /dispute [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *DisputeHandler) OpenDispute(c *gin.Context) {
+	var req model.OpenDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	dispute, err := h.service.OpenDispute(&req)
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dispute)
+}
+
+// ListDisputes handles GET /dispute
+// @Summary List disputes
+// @Description Retrieve disputes, optionally filtered by payment or status
+// @ID listDisputes
+// @Tags Dispute
+// @Accept json
+// @Produce json
+// @Param payment_id query int false "Only include disputes of this payment" minimum(1)
+// @Param status query string false "Only include disputes in this status" Enums(needs_response, under_review, won, lost)
+// @Success 200 {array} model.Dispute
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /dispute [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *DisputeHandler) ListDisputes(c *gin.Context) {
+	paymentID := 0
+	if paymentIDStr := c.Query("payment_id"); paymentIDStr != "" {
+		var err error
+		paymentID, err = strconv.Atoi(paymentIDStr)
+		if err != nil || paymentID < 1 {
+			c.JSON(http.StatusBadRequest, model.Error{
+				Error:   "INVALID_INPUT",
+				Message: "Invalid payment ID",
+				Details: "payment_id must be a positive integer",
+			})
+			return
+		}
+	}
+
+	disputes, err := h.service.ListDisputes(paymentID, model.DisputeStatus(c.Query("status")))
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, disputes)
+}
+
+// GetDispute handles GET /dispute/{disputeId}
+// @Summary Get dispute by ID
+// @Description Retrieve a dispute with its evidence
+// @ID getDispute
+// @Tags Dispute
+// @Accept json
+// @Produce json
+// @Param disputeId path int true "Unique identifier for the dispute" minimum(1)
+// @Success 200 {object} model.Dispute
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /dispute/{disputeId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *DisputeHandler) GetDispute(c *gin.Context) {
+	disputeID, ok := parseDisputeID(c)
+	if !ok {
+		return
+	}
+
+	dispute, err := h.service.GetDispute(disputeID)
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// AddEvidence handles POST /dispute/{disputeId}/evidence
+// @Summary Upload dispute evidence
+// @Description Attach a file, sent as the request body with its own content type, to a dispute that still needs a response. Files may be at most 5 MiB.
+// @ID addDisputeEvidence
+// @Tags Dispute
+// @Accept application/octet-stream
+// @Produce json
+// @Param disputeId path int true "Unique identifier for the dispute" minimum(1)
+// @Param kind query string true "What the file shows" Enums(receipt, shipping_documentation, customer_communication, refund_policy, other)
+// @Param file_name query string true "Name of the file"
+// @Param file body string true "File content"
+// @Success 201 {object} model.Dispute
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /dispute/{disputeId}/evidence [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *DisputeHandler) AddEvidence(c *gin.Context) {
+	disputeID, ok := parseDisputeID(c)
+	if !ok {
+		return
+	}
+
+	content, err := io.ReadAll(io.LimitReader(c.Request.Body, service.MaxEvidenceSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	dispute, err := h.service.AddEvidence(disputeID, model.EvidenceKind(c.Query("kind")), c.Query("file_name"), c.ContentType(), content)
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dispute)
+}
+
+// GetEvidence handles GET /dispute/{disputeId}/evidence/{evidenceId}
+// @Summary Download dispute evidence
+// @Description Download a file attached to a dispute, with the content type it was uploaded with
+// @ID getDisputeEvidence
+// @Tags Dispute
+// @Produce application/octet-stream
+// @Param disputeId path int true "Unique identifier for the dispute" minimum(1)
+// @Param evidenceId path int true "Unique identifier for the evidence" minimum(1)
+// @Success 200 {file} file
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /dispute/{disputeId}/evidence/{evidenceId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *DisputeHandler) GetEvidence(c *gin.Context) {
+	disputeID, ok := parseDisputeID(c)
+	if !ok {
+		return
+	}
+	evidenceID, err := strconv.Atoi(c.Param("evidenceId"))
+	if err != nil || evidenceID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid evidence ID",
+			Details: "Evidence ID must be a positive integer",
+		})
+		return
+	}
+
+	evidence, content, err := h.service.GetEvidence(disputeID, evidenceID)
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", evidence.FileName))
+	c.Data(http.StatusOK, evidence.ContentType, content)
+}
+
+// SubmitDispute handles POST /dispute/{disputeId}/submit
+// @Summary Submit dispute response
+// @Description Send a dispute's evidence to the card network before the response deadline, moving the dispute under review
+// @ID submitDispute
+// @Tags Dispute
+// @Accept json
+// @Produce json
+// @Param disputeId path int true "Unique identifier for the dispute" minimum(1)
+// @Success 200 {object} model.Dispute
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /dispute/{disputeId}/submit [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *DisputeHandler) SubmitDispute(c *gin.Context) {
+	disputeID, ok := parseDisputeID(c)
+	if !ok {
+		return
+	}
+
+	dispute, err := h.service.SubmitDispute(disputeID)
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// ResolveDispute handles POST /dispute/{disputeId}/resolve
+// @Summary Resolve dispute
+// @Description Record the card network's ruling. A dispute under review may be won or lost; one still needing a response can only be lost, which accepts it. Winning restores the withheld amount and losing reverses it out of the merchant's balance.
+// @ID resolveDispute
+// @Tags Dispute
+// @Accept json
+// @Produce json
+// @Param disputeId path int true "Unique identifier for the dispute" minimum(1)
+// @Param request body model.ResolveDisputeRequest true "Outcome"
+// @Success 200 {object} model.Dispute
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /dispute/{disputeId}/resolve [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *DisputeHandler) ResolveDispute(c *gin.Context) {
+	disputeID, ok := parseDisputeID(c)
+	if !ok {
+		return
+	}
+
+	var req model.ResolveDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	dispute, err := h.service.ResolveDispute(disputeID, req.Outcome)
+	if err != nil {
+		writeDisputeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// parseDisputeID reads the disputeId path parameter, writing a 400 if it is invalid
+func parseDisputeID(c *gin.Context) (int, bool) {
+	disputeID, err := strconv.Atoi(c.Param("disputeId"))
+	if err != nil || disputeID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid dispute ID",
+			Details: "Dispute ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return disputeID, true
+}
+
+// writeDisputeError maps dispute errors to responses
+func writeDisputeError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrDisputeNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Dispute not found",
+			Details: "No dispute exists with the specified ID",
+		})
+	case service.ErrEvidenceNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Evidence not found",
+			Details: "The dispute has no evidence with the specified ID",
+		})
+	case service.ErrDisputeExists:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "CONFLICT",
+			Message: "Payment is already disputed",
+			Details: err.Error(),
+		})
+	case service.ErrPaymentNotDisputable:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Payment cannot be disputed",
+			Details: err.Error(),
+		})
+	case service.ErrInvalidDisputeTransition, service.ErrDisputeChanged:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Dispute cannot be changed in its current status",
+			Details: err.Error(),
+		})
+	case service.ErrEvidenceRequired, service.ErrResponseOverdue:
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Dispute cannot be submitted",
+			Details: err.Error(),
+		})
+	case service.ErrInvalidDispute, service.ErrEvidenceTooLarge:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		writePaymentError(c, err)
+	}
+}
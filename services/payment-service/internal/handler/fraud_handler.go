@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/payment-service/internal/service"
+	"github.com/gocart-v2/shared/model"
+)
+
+type FraudHandler struct {
+	payments *service.PaymentService
+	fraud    *service.FraudService
+}
+
+func NewFraudHandler(payments *service.PaymentService, fraud *service.FraudService) *FraudHandler {
+	return &FraudHandler{payments: payments, fraud: fraud}
+}
+
+// ListReviews handles GET /fraud/reviews
+// @Summary List payments awaiting review
+// @Description Retrieve the payments fraud screening held for manual review, oldest first, with the signals that held them
+// @ID listFraudReviews
+// @Tags Fraud
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.Payment
+// @Failure 500 {object} model.Error
+// @Router /fraud/reviews [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *FraudHandler) ListReviews(c *gin.Context) {
+	payments, err := h.payments.ListReviews()
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payments)
+}
+
+// ReleasePayment handles POST /fraud/reviews/{paymentId}/release
+// @Summary Release held payment
+// @Description Send a payment held for review to the gateway. It becomes authorized, or failed with the gateway's reason.
+// @ID releaseFraudReview
+// @Tags Fraud
+// @Accept json
+// @Produce json
+// @Param paymentId path int true "Unique identifier for the payment" minimum(1)
+// @Success 200 {object} model.Payment
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /fraud/reviews/{paymentId}/release [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *FraudHandler) ReleasePayment(c *gin.Context) {
+	paymentID, ok := parsePaymentID(c)
+	if !ok {
+		return
+	}
+
+	payment, err := h.payments.ReleasePayment(paymentID)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// CancelPayment handles POST /fraud/reviews/{paymentId}/cancel
+// @Summary Cancel held payment
+// @Description Fail a payment held for review without contacting the gateway
+// @ID cancelFraudReview
+// @Tags Fraud
+// @Accept json
+// @Produce json
+// @Param paymentId path int true "Unique identifier for the payment" minimum(1)
+// @Success 200 {object} model.Payment
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /fraud/reviews/{paymentId}/cancel [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *FraudHandler) CancelPayment(c *gin.Context) {
+	paymentID, ok := parsePaymentID(c)
+	if !ok {
+		return
+	}
+
+	payment, err := h.payments.CancelPayment(paymentID)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// CreateDenyListEntry handles POST /fraud/deny-list
+// @Summary Add deny list entry
+// @Description Decline every future payment from a customer ID, card token or ISO country code
+// @ID createDenyListEntry
+// @Tags Fraud
+// @Accept json
+// @Produce json
+// @Param request body model.CreateDenyListEntryRequest true "Value to deny"
+// @Success 201 {object} model.DenyListEntry
+// @Failure 400 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /fraud/deny-list [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *FraudHandler) CreateDenyListEntry(c *gin.Context) {
+	var req model.CreateDenyListEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	entry, err := h.fraud.AddDenyListEntry(&req)
+	if err != nil {
+		writeFraudError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// ListDenyList handles GET /fraud/deny-list
+// @Summary List deny list
+// @Description Retrieve deny list entries, optionally only those of one kind
+// @ID listDenyList
+// @Tags Fraud
+// @Accept json
+// @Produce json
+// @Param kind query string false "Only include entries of this kind" Enums(customer, card_token, country)
+// @Success 200 {array} model.DenyListEntry
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /fraud/deny-list [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *FraudHandler) ListDenyList(c *gin.Context) {
+	entries, err := h.fraud.ListDenyList(model.DenyListKind(c.Query("kind")))
+	if err != nil {
+		writeFraudError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// DeleteDenyListEntry handles DELETE /fraud/deny-list/{entryId}
+// @Summary Remove deny list entry
+// @Description Take a value off the deny list
+// @ID deleteDenyListEntry
+// @Tags Fraud
+// @Accept json
+// @Produce json
+// @Param entryId path int true "Unique identifier for the deny list entry" minimum(1)
+// @Success 204
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /fraud/deny-list/{entryId} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *FraudHandler) DeleteDenyListEntry(c *gin.Context) {
+	entryID, err := strconv.Atoi(c.Param("entryId"))
+	if err != nil || entryID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid deny list entry ID",
+			Details: "Entry ID must be a positive integer",
+		})
+		return
+	}
+
+	if err := h.fraud.RemoveDenyListEntry(entryID); err != nil {
+		writeFraudError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeFraudError maps deny list errors to responses
+func writeFraudError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrDenyListEntryNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Deny list entry not found",
+			Details: "No deny list entry exists with the specified ID",
+		})
+	case service.ErrDenyListEntryExists:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "CONFLICT",
+			Message: "Value is already denied",
+			Details: err.Error(),
+		})
+	case service.ErrInvalidDenyListEntry:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
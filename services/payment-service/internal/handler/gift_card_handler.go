@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/payment-service/internal/service"
+	"github.com/gocart-v2/shared/model"
+)
+
+type GiftCardHandler struct {
+	service *service.GiftCardService
+}
+
+func NewGiftCardHandler(service *service.GiftCardService) *GiftCardHandler {
+	return &GiftCardHandler{service: service}
+}
+
+// IssueGiftCard handles POST /gift-card
+// @Summary Issue gift card
+// @Description Create a gift card with a new random code holding the requested balance. The code is what customers give at checkout to pay with the card.
+// @ID issueGiftCard
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param request body model.IssueGiftCardRequest true "Balance, currency and expiry"
+// @Success 201 {object} model.GiftCard
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /gift-card [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *GiftCardHandler) IssueGiftCard(c *gin.Context) {
+	var req model.IssueGiftCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	card, err := h.service.IssueGiftCard(&req)
+	if err != nil {
+		writeGiftCardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, card)
+}
+
+// GetGiftCard handles GET /gift-card/{code}
+// @Summary Get gift card balance
+// @Description Retrieve a gift card's balance and every change made to it. Codes match regardless of case, spaces and dashes.
+// @ID getGiftCard
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param code path string true "Gift card code"
+// @Success 200 {object} model.GiftCard
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /gift-card/{code} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *GiftCardHandler) GetGiftCard(c *gin.Context) {
+	card, err := h.service.GetGiftCard(c.Param("code"))
+	if err != nil {
+		writeGiftCardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, card)
+}
+
+// RedeemGiftCard handles POST /gift-card/{code}/redeem
+// @Summary Redeem gift card
+// @Description Spend part of a gift card's balance outside of a payment, such as at a till. Online orders pay with gift cards through payment authorization instead.
+// @ID redeemGiftCard
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param code path string true "Gift card code"
+// @Param request body model.RedeemGiftCardRequest true "Amount and sale reference"
+// @Success 200 {object} model.GiftCard
+// @Failure 400 {object} model.Error
+// @Failure 402 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /gift-card/{code}/redeem [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *GiftCardHandler) RedeemGiftCard(c *gin.Context) {
+	var req model.RedeemGiftCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	card, err := h.service.RedeemGiftCard(c.Param("code"), &req)
+	if err != nil {
+		writeGiftCardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, card)
+}
+
+// writeGiftCardError maps gift card errors to responses
+func writeGiftCardError(c *gin.Context, err error) {
+	if writeCardError(c, err) {
+		return
+	}
+
+	switch err {
+	case service.ErrInsufficientBalance:
+		c.JSON(http.StatusPaymentRequired, model.Error{
+			Error:   "PAYMENT_DECLINED",
+			Message: "Gift card balance is too low",
+			Details: err.Error(),
+		})
+	case service.ErrInvalidGiftCard:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/payment-service/internal/service"
+	"github.com/gocart-v2/shared/model"
+)
+
+type LedgerHandler struct {
+	service *service.LedgerService
+}
+
+func NewLedgerHandler(service *service.LedgerService) *LedgerHandler {
+	return &LedgerHandler{service: service}
+}
+
+// ListJournals handles GET /ledger/journals
+// @Summary List journals
+// @Description Retrieve ledger journals in the order they were posted, optionally only those for one payment
+// @ID listJournals
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Param payment_id query int false "Only include journals for this payment" minimum(1)
+// @Success 200 {array} model.Journal
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /ledger/journals [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *LedgerHandler) ListJournals(c *gin.Context) {
+	paymentID := 0
+	if paymentIDStr := c.Query("payment_id"); paymentIDStr != "" {
+		var err error
+		paymentID, err = strconv.Atoi(paymentIDStr)
+		if err != nil || paymentID < 1 {
+			c.JSON(http.StatusBadRequest, model.Error{
+				Error:   "INVALID_INPUT",
+				Message: "Invalid payment ID",
+				Details: "payment_id must be a positive integer",
+			})
+			return
+		}
+	}
+
+	journals, err := h.service.ListJournals(paymentID)
+	if err != nil {
+		writeLedgerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, journals)
+}
+
+// GetJournal handles GET /ledger/journals/{journalId}
+// @Summary Get journal by ID
+// @Description Retrieve a ledger journal with its lines
+// @ID getJournal
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Param journalId path int true "Unique identifier for the journal" minimum(1)
+// @Success 200 {object} model.Journal
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /ledger/journals/{journalId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *LedgerHandler) GetJournal(c *gin.Context) {
+	journalID, err := strconv.Atoi(c.Param("journalId"))
+	if err != nil || journalID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid journal ID",
+			Details: "Journal ID must be a positive integer",
+		})
+		return
+	}
+
+	journal, err := h.service.GetJournal(journalID)
+	if err != nil {
+		writeLedgerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, journal)
+}
+
+// GetTrialBalance handles GET /ledger/trial-balance
+// @Summary Get trial balance
+// @Description Total the debits and credits posted to every ledger account, separately for each currency. Amounts in different currencies are never added together; the ledger is balanced when debits equal credits in every currency.
+// @ID getTrialBalance
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.TrialBalance
+// @Failure 500 {object} model.Error
+// @Router /ledger/trial-balance [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *LedgerHandler) GetTrialBalance(c *gin.Context) {
+	trialBalance, err := h.service.TrialBalance()
+	if err != nil {
+		writeLedgerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trialBalance)
+}
+
+// CheckJournals handles GET /ledger/check
+// @Summary Check journals
+// @Description Verify that every posted journal sums to zero, listing any that do not, and list journals that failed to post and are waiting to be retried. The ledger is balanced only when there are neither.
+// @ID checkJournals
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.LedgerCheck
+// @Failure 500 {object} model.Error
+// @Router /ledger/check [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *LedgerHandler) CheckJournals(c *gin.Context) {
+	check, err := h.service.CheckJournals()
+	if err != nil {
+		writeLedgerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
+
+// ListFailedPostings handles GET /ledger/failed-postings
+// @Summary List failed postings
+// @Description Retrieve journals the ledger failed to store, oldest first. Pending ones are retried every minute and removed once posted; after 10 failed attempts they are dead-lettered and only retried by hand.
+// @ID listFailedPostings
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.FailedPosting
+// @Failure 500 {object} model.Error
+// @Router /ledger/failed-postings [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *LedgerHandler) ListFailedPostings(c *gin.Context) {
+	failed, err := h.service.ListFailedPostings()
+	if err != nil {
+		writeLedgerError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, failed)
+}
+
+// RetryFailedPostings handles POST /ledger/failed-postings/retry
+// @Summary Retry failed postings
+// @Description Try again now to post every journal the ledger failed to store, dead-lettered ones included, returning those that still could not be
+// @ID retryFailedPostings
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.FailedPosting
+// @Failure 500 {object} model.Error
+// @Router /ledger/failed-postings/retry [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *LedgerHandler) RetryFailedPostings(c *gin.Context) {
+	if _, err := h.service.RetryFailedPostings(true); err != nil {
+		writeLedgerError(c, err)
+		return
+	}
+
+	h.ListFailedPostings(c)
+}
+
+// writeLedgerError maps ledger errors to responses
+func writeLedgerError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrJournalNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Journal not found",
+			Details: "No journal exists with the specified ID",
+		})
+	case service.ErrInvalidJournal:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/payment-service/internal/service"
+	"github.com/gocart-v2/shared/model"
+)
+
+type PaymentHandler struct {
+	service *service.PaymentService
+}
+
+func NewPaymentHandler(service *service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{service: service}
+}
+
+// AuthorizePayment handles POST /payment
+// @Summary Authorize payment
+// @Description Place a hold on a card for an order through the payment gateway. The card is given either as a card token or as one of the customer's saved payment methods. The payment is screened for fraud first: a declined payment is recorded as failed with reason fraud_declined, and a suspicious one is held as pending_review until it is released or canceled. A hold the gateway refuses, or that cannot be confirmed, is recorded as a failed payment with the reason. A gift card code instead holds the amount against the gift card's balance without screening, failing with reason insufficient_balance if the balance is too low.
+// @ID authorizePayment
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param request body model.AuthorizePaymentRequest true "Order, amount and card"
+// @Success 201 {object} model.Payment
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /payment [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PaymentHandler) AuthorizePayment(c *gin.Context) {
+	var req model.AuthorizePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	payment, err := h.service.AuthorizePayment(&req)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, payment)
+}
+
+// ListPayments handles GET /payment
+// @Summary List payments
+// @Description Retrieve payments, optionally filtered by order or status
+// @ID listPayments
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param order_ref query string false "Only include payments for this order"
+// @Param status query string false "Only include payments in this status" Enums(pending_review, authorized, captured, partially_refunded, refunded, voided, failed)
+// @Success 200 {array} model.Payment
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /payment [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PaymentHandler) ListPayments(c *gin.Context) {
+	payments, err := h.service.ListPayments(c.Query("order_ref"), model.PaymentStatus(c.Query("status")))
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payments)
+}
+
+// GetPayment handles GET /payment/{paymentId}
+// @Summary Get payment by ID
+// @Description Retrieve a payment with its refunds and status history
+// @ID getPayment
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param paymentId path int true "Unique identifier for the payment" minimum(1)
+// @Success 200 {object} model.Payment
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /payment/{paymentId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PaymentHandler) GetPayment(c *gin.Context) {
+	paymentID, ok := parsePaymentID(c)
+	if !ok {
+		return
+	}
+
+	payment, err := h.service.GetPayment(paymentID)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// CapturePayment handles POST /payment/{paymentId}/capture
+// @Summary Capture payment
+// @Description Capture an authorized payment. Leaving out the amount captures everything authorized; a smaller amount releases the rest of the hold.
+// @ID capturePayment
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param paymentId path int true "Unique identifier for the payment" minimum(1)
+// @Param request body model.CapturePaymentRequest false "Amount to capture"
+// @Success 200 {object} model.Payment
+// @Failure 400 {object} model.Error
+// @Failure 402 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 502 {object} model.Error
+// @Router /payment/{paymentId}/capture [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PaymentHandler) CapturePayment(c *gin.Context) {
+	paymentID, ok := parsePaymentID(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional
+	var req model.CapturePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	payment, err := h.service.CapturePayment(paymentID, req.Amount)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// VoidPayment handles POST /payment/{paymentId}/void
+// @Summary Void payment
+// @Description Release the hold of an authorized payment that has not been captured
+// @ID voidPayment
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param paymentId path int true "Unique identifier for the payment" minimum(1)
+// @Success 200 {object} model.Payment
+// @Failure 400 {object} model.Error
+// @Failure 402 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 502 {object} model.Error
+// @Router /payment/{paymentId}/void [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PaymentHandler) VoidPayment(c *gin.Context) {
+	paymentID, ok := parsePaymentID(c)
+	if !ok {
+		return
+	}
+
+	payment, err := h.service.VoidPayment(paymentID)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// RefundPayment handles POST /payment/{paymentId}/refunds
+// @Summary Refund payment
+// @Description Return part or all of a captured payment to the card. Money withheld by an open dispute or charged back by a lost one cannot be refunded. The payment is refunded once nothing captured remains.
+// @ID refundPayment
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param paymentId path int true "Unique identifier for the payment" minimum(1)
+// @Param request body model.RefundPaymentRequest true "Amount to refund"
+// @Success 200 {object} model.Payment
+// @Failure 400 {object} model.Error
+// @Failure 402 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Failure 502 {object} model.Error
+// @Router /payment/{paymentId}/refunds [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PaymentHandler) RefundPayment(c *gin.Context) {
+	paymentID, ok := parsePaymentID(c)
+	if !ok {
+		return
+	}
+
+	var req model.RefundPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	payment, err := h.service.RefundPayment(paymentID, &req)
+	if err != nil {
+		writePaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// parsePaymentID reads the paymentId path parameter, writing a 400 if it is invalid
+func parsePaymentID(c *gin.Context) (int, bool) {
+	paymentID, err := strconv.Atoi(c.Param("paymentId"))
+	if err != nil || paymentID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid payment ID",
+			Details: "Payment ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return paymentID, true
+}
+
+// writePaymentError maps payment errors to responses
+func writePaymentError(c *gin.Context, err error) {
+	if writeCardError(c, err) {
+		return
+	}
+
+	switch err {
+	case service.ErrPaymentNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Payment not found",
+			Details: "No payment exists with the specified ID",
+		})
+	case service.ErrInvalidTransition, service.ErrPaymentChanged:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Payment cannot be changed in its current status",
+			Details: err.Error(),
+		})
+	case service.ErrGatewayDeclined:
+		c.JSON(http.StatusPaymentRequired, model.Error{
+			Error:   "PAYMENT_DECLINED",
+			Message: "Payment gateway declined the operation",
+			Details: err.Error(),
+		})
+	case service.ErrGatewayFailed:
+		c.JSON(http.StatusBadGateway, model.Error{
+			Error:   "GATEWAY_ERROR",
+			Message: "Payment gateway unavailable",
+			Details: "The gateway timed out or could not be reached; the operation can be retried",
+		})
+	case service.ErrAmountExceeded:
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "AMOUNT_EXCEEDED",
+			Message: "Amount is too large",
+			Details: err.Error(),
+		})
+	case service.ErrInvalidPayment:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
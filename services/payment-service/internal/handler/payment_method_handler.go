@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/payment-service/internal/service"
+	"github.com/gocart-v2/shared/model"
+)
+
+type PaymentMethodHandler struct {
+	service *service.PaymentMethodService
+}
+
+func NewPaymentMethodHandler(service *service.PaymentMethodService) *PaymentMethodHandler {
+	return &PaymentMethodHandler{service: service}
+}
+
+// CreatePaymentMethod handles POST /payment-method
+// @Summary Save payment method
+// @Description Save a tokenized card for a customer. It becomes the default when asked to, or when the customer has no usable default yet.
+// @ID createPaymentMethod
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param request body model.CreatePaymentMethodRequest true "Customer and card token"
+// @Success 201 {object} model.PaymentMethod
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /payment-method [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PaymentMethodHandler) CreatePaymentMethod(c *gin.Context) {
+	var req model.CreatePaymentMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	method, err := h.service.CreatePaymentMethod(&req)
+	if err != nil {
+		writePaymentMethodError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, method)
+}
+
+// ListPaymentMethods handles GET /payment-method
+// @Summary List payment methods
+// @Description Retrieve a customer's saved payment methods, newest first, flagging cards that have expired
+// @ID listPaymentMethods
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param customer_id query int true "Customer whose payment methods to list" minimum(1)
+// @Success 200 {array} model.PaymentMethod
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /payment-method [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PaymentMethodHandler) ListPaymentMethods(c *gin.Context) {
+	customerID, err := strconv.Atoi(c.Query("customer_id"))
+	if err != nil || customerID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid customer ID",
+			Details: "customer_id must be a positive integer",
+		})
+		return
+	}
+
+	methods, err := h.service.ListPaymentMethods(customerID)
+	if err != nil {
+		writePaymentMethodError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, methods)
+}
+
+// GetPaymentMethod handles GET /payment-method/{paymentMethodId}
+// @Summary Get payment method by ID
+// @Description Retrieve a saved payment method
+// @ID getPaymentMethod
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param paymentMethodId path int true "Unique identifier for the payment method" minimum(1)
+// @Success 200 {object} model.PaymentMethod
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /payment-method/{paymentMethodId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PaymentMethodHandler) GetPaymentMethod(c *gin.Context) {
+	paymentMethodID, ok := parsePaymentMethodID(c)
+	if !ok {
+		return
+	}
+
+	method, err := h.service.GetPaymentMethod(paymentMethodID)
+	if err != nil {
+		writePaymentMethodError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, method)
+}
+
+// SetDefaultPaymentMethod handles PUT /payment-method/{paymentMethodId}/default
+// @Summary Set default payment method
+// @Description Make a saved payment method its customer's default. Expired cards cannot be the default.
+// @ID setDefaultPaymentMethod
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param paymentMethodId path int true "Unique identifier for the payment method" minimum(1)
+// @Success 200 {object} model.PaymentMethod
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /payment-method/{paymentMethodId}/default [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PaymentMethodHandler) SetDefaultPaymentMethod(c *gin.Context) {
+	paymentMethodID, ok := parsePaymentMethodID(c)
+	if !ok {
+		return
+	}
+
+	method, err := h.service.SetDefaultPaymentMethod(paymentMethodID)
+	if err != nil {
+		writePaymentMethodError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, method)
+}
+
+// DeletePaymentMethod handles DELETE /payment-method/{paymentMethodId}
+// @Summary Delete payment method
+// @Description Remove a saved payment method. When it was the default, the customer's newest card that has not expired takes over.
+// @ID deletePaymentMethod
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param paymentMethodId path int true "Unique identifier for the payment method" minimum(1)
+// @Success 204 "Payment method deleted"
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /payment-method/{paymentMethodId} [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PaymentMethodHandler) DeletePaymentMethod(c *gin.Context) {
+	paymentMethodID, ok := parsePaymentMethodID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePaymentMethod(paymentMethodID); err != nil {
+		writePaymentMethodError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parsePaymentMethodID reads the paymentMethodId path parameter, writing a 400 if it is invalid
+func parsePaymentMethodID(c *gin.Context) (int, bool) {
+	paymentMethodID, err := strconv.Atoi(c.Param("paymentMethodId"))
+	if err != nil || paymentMethodID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid payment method ID",
+			Details: "Payment method ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return paymentMethodID, true
+}
+
+// writePaymentMethodError maps payment method errors to responses
+func writePaymentMethodError(c *gin.Context, err error) {
+	if writeCardError(c, err) {
+		return
+	}
+
+	switch err {
+	case service.ErrInvalidPaymentMethod:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/payment-service/internal/service"
+	"github.com/gocart-v2/shared/model"
+)
+
+// maxSettlementFileSize is the largest settlement file accepted
+const maxSettlementFileSize = 10 << 20
+
+type SettlementHandler struct {
+	service *service.SettlementService
+}
+
+func NewSettlementHandler(service *service.SettlementService) *SettlementHandler {
+	return &SettlementHandler{service: service}
+}
+
+// ImportSettlementFile handles POST /settlement/imports
+// @Summary Import settlement file
+// @Description Import a processor settlement CSV sent as the request body. The header row must name reference, amount and settlement_date columns and may name currency; amounts are in major units with no more decimals than their currency has ("51.87" USD, "5187" JPY, "5.187" KWD) and dates are YYYY-MM-DD. A file with any unreadable row is refused as a whole, as is a file that was already imported.
+// @ID importSettlementFile
+// @Tags Settlement
+// @Accept text/csv
+// @Produce json
+// @Param file_name query string false "Name of the file, for reference"
+// @Param file body string true "Settlement CSV"
+// @Success 201 {object} model.SettlementImport
+// @Failure 400 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /settlement/imports [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *SettlementHandler) ImportSettlementFile(c *gin.Context) {
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSettlementFileSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+	if len(data) > maxSettlementFileSize {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Settlement file is too large",
+			Details: "Settlement files may be at most 10 MiB",
+		})
+		return
+	}
+
+	settlementImport, err := h.service.ImportFile(c.Query("file_name"), data)
+	if err != nil {
+		writeSettlementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, settlementImport)
+}
+
+// ListSettlementImports handles GET /settlement/imports
+// @Summary List settlement imports
+// @Description Retrieve imported settlement files in the order they were imported, without their rows
+// @ID listSettlementImports
+// @Tags Settlement
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.SettlementImport
+// @Failure 500 {object} model.Error
+// @Router /settlement/imports [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *SettlementHandler) ListSettlementImports(c *gin.Context) {
+	imports, err := h.service.ListImports()
+	if err != nil {
+		writeSettlementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, imports)
+}
+
+// GetSettlementImport handles GET /settlement/imports/{importId}
+// @Summary Get settlement import by ID
+// @Description Retrieve an imported settlement file with its rows
+// @ID getSettlementImport
+// @Tags Settlement
+// @Accept json
+// @Produce json
+// @Param importId path int true "Unique identifier for the import" minimum(1)
+// @Success 200 {object} model.SettlementImport
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /settlement/imports/{importId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *SettlementHandler) GetSettlementImport(c *gin.Context) {
+	importID, err := strconv.Atoi(c.Param("importId"))
+	if err != nil || importID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid import ID",
+			Details: "Import ID must be a positive integer",
+		})
+		return
+	}
+
+	settlementImport, err := h.service.GetImport(importID)
+	if err != nil {
+		writeSettlementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settlementImport)
+}
+
+// GetReconciliation handles GET /settlement/reconciliation
+// @Summary Get reconciliation report
+// @Description Check the payments captured on a date (UTC) against every imported settlement row by reference and amount. Captures are matched, missing, duplicate or amount_mismatch; rows settled on the date for no known capture are unmatched. Captured and settled totals are given separately for each currency.
+// @ID getReconciliation
+// @Tags Settlement
+// @Accept json
+// @Produce json
+// @Param date query string true "Capture date, YYYY-MM-DD" example(2026-10-19)
+// @Success 200 {object} model.ReconciliationReport
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /settlement/reconciliation [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *SettlementHandler) GetReconciliation(c *gin.Context) {
+	report, err := h.service.Reconcile(c.Query("date"))
+	if err != nil {
+		writeSettlementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// writeSettlementError maps settlement errors to responses
+func writeSettlementError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSettlementFile), errors.Is(err, service.ErrInvalidSettlementDate):
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	case errors.Is(err, service.ErrSettlementImported):
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "CONFLICT",
+			Message: "Settlement file already imported",
+			Details: err.Error(),
+		})
+	case errors.Is(err, service.ErrSettlementImportNotFound):
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Settlement import not found",
+			Details: "No settlement import exists with the specified ID",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
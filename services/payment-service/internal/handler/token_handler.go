@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/payment-service/internal/service"
+	"github.com/gocart-v2/shared/model"
+)
+
+type TokenHandler struct {
+	service *service.TokenService
+}
+
+func NewTokenHandler(service *service.TokenService) *TokenHandler {
+	return &TokenHandler{service: service}
+}
+
+// TokenizeCard handles POST /card-token
+// @Summary Tokenize card
+// @Description Validate a card (checksum, expiry and brand) and store it encrypted in the vault. The returned token is what every other API accepts in place of the card.
+// @ID tokenizeCard
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param request body model.CardDetails true "Card to tokenize"
+// @Success 201 {object} model.CardToken
+// @Failure 400 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /card-token [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *TokenHandler) TokenizeCard(c *gin.Context) {
+	var req model.CardDetails
+	if err := c.ShouldBindJSON(&req); err != nil {
+		// Binding errors echo field values, so never include them for card data
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: "Card number, expiry and security code are required and must be numeric",
+		})
+		return
+	}
+
+	cardToken, err := h.service.TokenizeCard(&req)
+	if err != nil {
+		writeTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, cardToken)
+}
+
+// GetCardToken handles GET /card-token/{cardToken}
+// @Summary Get card token
+// @Description Retrieve the brand, last four digits and expiry of a tokenized card
+// @ID getCardToken
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param cardToken path string true "Card token"
+// @Success 200 {object} model.CardToken
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /card-token/{cardToken} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *TokenHandler) GetCardToken(c *gin.Context) {
+	cardToken, err := h.service.GetCardToken(c.Param("cardToken"))
+	if err != nil {
+		writeTokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cardToken)
+}
+
+// writeTokenError maps card vault errors to responses
+func writeTokenError(c *gin.Context, err error) {
+	if writeCardError(c, err) {
+		return
+	}
+
+	switch err {
+	case service.ErrInvalidCard:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
+
+// writeCardError writes the response for card, token, saved payment method
+// and gift card errors, reporting whether err was one of them
+func writeCardError(c *gin.Context, err error) bool {
+	switch err {
+	case service.ErrTokenNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Card token not found",
+			Details: "No card is stored under the specified token",
+		})
+	case service.ErrCardNumberInvalid, service.ErrUnsupportedBrand:
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "INVALID_CARD",
+			Message: "Card number is not valid",
+			Details: err.Error(),
+		})
+	case service.ErrPaymentMethodNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Payment method not found",
+			Details: "No payment method exists with the specified ID for this customer",
+		})
+	case service.ErrGiftCardNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Gift card not found",
+			Details: "No gift card exists with the specified code",
+		})
+	case service.ErrGiftCardCurrency:
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "INVALID_CARD",
+			Message: "Gift card cannot pay in this currency",
+			Details: err.Error(),
+		})
+	case service.ErrCardExpired, service.ErrPaymentMethodExpired, service.ErrGiftCardExpired:
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "CARD_EXPIRED",
+			Message: "Card has expired",
+			Details: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrDenyListEntryNotFound = errors.New("deny list entry not found")
+	ErrDenyListEntryExists   = errors.New("deny list entry already exists")
+)
+
+type DenyListRepository struct {
+	entries     map[int]*model.DenyListEntry
+	mu          sync.RWMutex
+	nextEntryID int
+}
+
+func NewDenyListRepository() *DenyListRepository {
+	return &DenyListRepository{
+		entries:     make(map[int]*model.DenyListEntry),
+		nextEntryID: 1,
+	}
+}
+
+// Create stores a new entry and assigns its ID. A value can only be
+// denied once for each kind.
+func (r *DenyListRepository) Create(entry *model.DenyListEntry) (*model.DenyListEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.find(entry.Kind, entry.Value) != nil {
+		return nil, ErrDenyListEntryExists
+	}
+
+	stored := *entry
+	stored.EntryID = r.nextEntryID
+	r.entries[stored.EntryID] = &stored
+	r.nextEntryID++
+
+	entryCopy := stored
+	return &entryCopy, nil
+}
+
+// List returns entries in ID order, optionally only those of one kind
+func (r *DenyListRepository) List(kind model.DenyListKind) ([]*model.DenyListEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []*model.DenyListEntry{}
+	for _, entry := range r.entries {
+		if kind == "" || entry.Kind == kind {
+			entryCopy := *entry
+			entries = append(entries, &entryCopy)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].EntryID < entries[j].EntryID })
+	return entries, nil
+}
+
+// Delete removes an entry
+func (r *DenyListRepository) Delete(entryID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.entries[entryID]; !exists {
+		return ErrDenyListEntryNotFound
+	}
+	delete(r.entries, entryID)
+
+	return nil
+}
+
+// Denied reports whether a value is on the deny list
+func (r *DenyListRepository) Denied(kind model.DenyListKind, value string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.find(kind, value) != nil
+}
+
+// find returns the entry for a value, or nil. Callers must hold the lock.
+func (r *DenyListRepository) find(kind model.DenyListKind, value string) *model.DenyListEntry {
+	for _, entry := range r.entries {
+		if entry.Kind == kind && entry.Value == value {
+			return entry
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrDisputeNotFound  = errors.New("dispute not found")
+	ErrDisputeConflict  = errors.New("dispute is not in the expected status")
+	ErrEvidenceNotFound = errors.New("evidence not found")
+)
+
+type DisputeRepository struct {
+	disputes       map[int]*model.Dispute
+	evidence       map[int][]byte
+	mu             sync.RWMutex
+	nextDisputeID  int
+	nextEvidenceID int
+}
+
+func NewDisputeRepository() *DisputeRepository {
+	return &DisputeRepository{
+		disputes:       make(map[int]*model.Dispute),
+		evidence:       make(map[int][]byte),
+		nextDisputeID:  1,
+		nextEvidenceID: 1,
+	}
+}
+
+// Create stores a new dispute and assigns its ID
+func (r *DisputeRepository) Create(dispute *model.Dispute) (*model.Dispute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyDispute(dispute)
+	stored.DisputeID = r.nextDisputeID
+	r.disputes[stored.DisputeID] = stored
+	r.nextDisputeID++
+
+	return copyDispute(stored), nil
+}
+
+// GetByID retrieves a dispute by its ID
+func (r *DisputeRepository) GetByID(disputeID int) (*model.Dispute, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	dispute, exists := r.disputes[disputeID]
+	if !exists {
+		return nil, ErrDisputeNotFound
+	}
+
+	return copyDispute(dispute), nil
+}
+
+// List returns disputes in ID order. Empty filters match every dispute.
+func (r *DisputeRepository) List(paymentID int, status model.DisputeStatus) ([]*model.Dispute, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	disputes := []*model.Dispute{}
+	for _, dispute := range r.disputes {
+		if (paymentID == 0 || dispute.PaymentID == paymentID) && (status == "" || dispute.Status == status) {
+			disputes = append(disputes, copyDispute(dispute))
+		}
+	}
+
+	sort.Slice(disputes, func(i, j int) bool { return disputes[i].DisputeID < disputes[j].DisputeID })
+	return disputes, nil
+}
+
+// Update replaces a dispute, failing if its status or reminders have
+// changed since it was read so that concurrent changes cannot both apply
+func (r *DisputeRepository) Update(dispute *model.Dispute, from model.DisputeStatus, remindersSent int) (*model.Dispute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.disputes[dispute.DisputeID]
+	if !exists {
+		return nil, ErrDisputeNotFound
+	}
+	if existing.Status != from || existing.RemindersSent != remindersSent {
+		return nil, ErrDisputeConflict
+	}
+
+	stored := copyDispute(dispute)
+	stored.Evidence = append([]model.DisputeEvidence{}, existing.Evidence...)
+	r.disputes[stored.DisputeID] = stored
+
+	return copyDispute(stored), nil
+}
+
+// AddEvidence attaches a file to a dispute that is still in the given
+// status and assigns the evidence its ID
+func (r *DisputeRepository) AddEvidence(disputeID int, status model.DisputeStatus, evidence *model.DisputeEvidence, content []byte) (*model.Dispute, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	dispute, exists := r.disputes[disputeID]
+	if !exists {
+		return nil, ErrDisputeNotFound
+	}
+	if dispute.Status != status {
+		return nil, ErrDisputeConflict
+	}
+
+	stored := *evidence
+	stored.EvidenceID = r.nextEvidenceID
+	r.nextEvidenceID++
+	dispute.Evidence = append(dispute.Evidence, stored)
+	dispute.UpdatedAt = stored.UploadedAt
+	r.evidence[stored.EvidenceID] = append([]byte(nil), content...)
+
+	return copyDispute(dispute), nil
+}
+
+// GetEvidence retrieves a dispute's evidence with its content
+func (r *DisputeRepository) GetEvidence(disputeID, evidenceID int) (*model.DisputeEvidence, []byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	dispute, exists := r.disputes[disputeID]
+	if !exists {
+		return nil, nil, ErrDisputeNotFound
+	}
+	for _, evidence := range dispute.Evidence {
+		if evidence.EvidenceID == evidenceID {
+			evidenceCopy := evidence
+			return &evidenceCopy, append([]byte(nil), r.evidence[evidenceID]...), nil
+		}
+	}
+
+	return nil, nil, ErrEvidenceNotFound
+}
+
+// copyDispute returns a copy of a dispute that shares no slices with the original
+func copyDispute(dispute *model.Dispute) *model.Dispute {
+	disputeCopy := *dispute
+	disputeCopy.Evidence = append([]model.DisputeEvidence{}, dispute.Evidence...)
+	return &disputeCopy
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrGiftCardNotFound    = errors.New("gift card not found")
+	ErrGiftCardExists      = errors.New("gift card code already exists")
+	ErrInsufficientBalance = errors.New("gift card balance is too low")
+)
+
+type GiftCardRepository struct {
+	cards             map[int]*model.GiftCard
+	codes             map[string]int
+	mu                sync.RWMutex
+	nextGiftCardID    int
+	nextTransactionID int
+}
+
+func NewGiftCardRepository() *GiftCardRepository {
+	return &GiftCardRepository{
+		cards:             make(map[int]*model.GiftCard),
+		codes:             make(map[string]int),
+		nextGiftCardID:    1,
+		nextTransactionID: 1,
+	}
+}
+
+// Create stores a new gift card under its code and assigns IDs to it and
+// its transactions
+func (r *GiftCardRepository) Create(card *model.GiftCard) (*model.GiftCard, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.codes[card.Code]; exists {
+		return nil, ErrGiftCardExists
+	}
+
+	stored := copyGiftCard(card)
+	stored.GiftCardID = r.nextGiftCardID
+	r.nextGiftCardID++
+	for i := range stored.Transactions {
+		stored.Transactions[i].TransactionID = r.nextTransactionID
+		r.nextTransactionID++
+	}
+	r.cards[stored.GiftCardID] = stored
+	r.codes[stored.Code] = stored.GiftCardID
+
+	return copyGiftCard(stored), nil
+}
+
+// GetByCode retrieves a gift card by its code
+func (r *GiftCardRepository) GetByCode(code string) (*model.GiftCard, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	giftCardID, exists := r.codes[code]
+	if !exists {
+		return nil, ErrGiftCardNotFound
+	}
+
+	return copyGiftCard(r.cards[giftCardID]), nil
+}
+
+// Apply adds a transaction's amount to a gift card's balance, failing
+// without a change if the balance would go below zero
+func (r *GiftCardRepository) Apply(giftCardID int, transaction *model.GiftCardTransaction) (*model.GiftCard, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	card, exists := r.cards[giftCardID]
+	if !exists {
+		return nil, ErrGiftCardNotFound
+	}
+	if card.Balance+transaction.Amount < 0 {
+		return nil, ErrInsufficientBalance
+	}
+
+	stored := *transaction
+	stored.TransactionID = r.nextTransactionID
+	r.nextTransactionID++
+	card.Balance += stored.Amount
+	stored.Balance = card.Balance
+	card.Transactions = append(card.Transactions, stored)
+	card.UpdatedAt = stored.CreatedAt
+
+	return copyGiftCard(card), nil
+}
+
+// copyGiftCard returns a copy of a gift card that shares no slices with the original
+func copyGiftCard(card *model.GiftCard) *model.GiftCard {
+	cardCopy := *card
+	cardCopy.Transactions = append([]model.GiftCardTransaction{}, card.Transactions...)
+	return &cardCopy
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrJournalNotFound       = errors.New("journal not found")
+	ErrFailedPostingNotFound = errors.New("failed posting not found")
+)
+
+// LedgerRepository is an append-only store of journals. Journals cannot be
+// changed or removed once posted; mistakes are corrected by posting more.
+// Journals that could not be posted are kept apart until they are.
+type LedgerRepository struct {
+	journals      []*model.Journal
+	failed        []*model.FailedPosting
+	nextPostingID int
+	mu            sync.RWMutex
+}
+
+func NewLedgerRepository() *LedgerRepository {
+	return &LedgerRepository{
+		journals:      []*model.Journal{},
+		failed:        []*model.FailedPosting{},
+		nextPostingID: 1,
+	}
+}
+
+// Append posts a journal and assigns its ID
+func (r *LedgerRepository) Append(journal *model.Journal) (*model.Journal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyJournal(journal)
+	stored.JournalID = len(r.journals) + 1
+	r.journals = append(r.journals, stored)
+
+	return copyJournal(stored), nil
+}
+
+// GetByID retrieves a journal by its ID
+func (r *LedgerRepository) GetByID(journalID int) (*model.Journal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if journalID < 1 || journalID > len(r.journals) {
+		return nil, ErrJournalNotFound
+	}
+
+	return copyJournal(r.journals[journalID-1]), nil
+}
+
+// List returns journals in the order they were posted, optionally only
+// those for one payment
+func (r *LedgerRepository) List(paymentID int) ([]*model.Journal, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	journals := []*model.Journal{}
+	for _, journal := range r.journals {
+		if paymentID == 0 || journal.PaymentID == paymentID {
+			journals = append(journals, copyJournal(journal))
+		}
+	}
+
+	return journals, nil
+}
+
+// AddFailed queues a journal that could not be posted and assigns the
+// posting its ID
+func (r *LedgerRepository) AddFailed(journal *model.Journal, reason string, at time.Time) (*model.FailedPosting, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := &model.FailedPosting{
+		PostingID:     r.nextPostingID,
+		Journal:       *copyJournal(journal),
+		Status:        model.PostingPending,
+		Attempts:      1,
+		LastError:     reason,
+		FailedAt:      at,
+		LastAttemptAt: at,
+	}
+	r.nextPostingID++
+	r.failed = append(r.failed, stored)
+
+	return copyFailedPosting(stored), nil
+}
+
+// ListFailed returns the journals waiting to be posted, oldest first
+func (r *LedgerRepository) ListFailed() ([]*model.FailedPosting, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	failed := make([]*model.FailedPosting, 0, len(r.failed))
+	for _, posting := range r.failed {
+		failed = append(failed, copyFailedPosting(posting))
+	}
+
+	return failed, nil
+}
+
+// RecordFailedAttempt notes another failed attempt to post a queued
+// journal, dead-lettering it once it has been tried maxAttempts times
+func (r *LedgerRepository) RecordFailedAttempt(postingID int, reason string, at time.Time, maxAttempts int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, posting := range r.failed {
+		if posting.PostingID == postingID {
+			posting.Attempts++
+			posting.LastError = reason
+			posting.LastAttemptAt = at
+			if posting.Attempts >= maxAttempts {
+				posting.Status = model.PostingDeadLettered
+			}
+			return nil
+		}
+	}
+	return ErrFailedPostingNotFound
+}
+
+// RemoveFailed drops a queued journal once it has been posted
+func (r *LedgerRepository) RemoveFailed(postingID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, posting := range r.failed {
+		if posting.PostingID == postingID {
+			r.failed = append(r.failed[:i], r.failed[i+1:]...)
+			return nil
+		}
+	}
+	return ErrFailedPostingNotFound
+}
+
+// copyFailedPosting returns a copy of a failed posting that shares no slices with the original
+func copyFailedPosting(posting *model.FailedPosting) *model.FailedPosting {
+	postingCopy := *posting
+	postingCopy.Journal = *copyJournal(&posting.Journal)
+	return &postingCopy
+}
+
+// copyJournal returns a copy of a journal that shares no slices with the original
+func copyJournal(journal *model.Journal) *model.Journal {
+	journalCopy := *journal
+	journalCopy.Lines = append([]model.JournalLine(nil), journal.Lines...)
+	return &journalCopy
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrPaymentMethodNotFound = errors.New("payment method not found")
+)
+
+type PaymentMethodRepository struct {
+	methods             map[int]*model.PaymentMethod
+	mu                  sync.RWMutex
+	nextPaymentMethodID int
+}
+
+func NewPaymentMethodRepository() *PaymentMethodRepository {
+	return &PaymentMethodRepository{
+		methods:             make(map[int]*model.PaymentMethod),
+		nextPaymentMethodID: 1,
+	}
+}
+
+// Create stores a new payment method and assigns its ID. A default method
+// takes over from the customer's previous default.
+func (r *PaymentMethodRepository) Create(method *model.PaymentMethod) (*model.PaymentMethod, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *method
+	stored.PaymentMethodID = r.nextPaymentMethodID
+	if stored.IsDefault {
+		r.clearDefault(stored.CustomerID)
+	}
+	r.methods[stored.PaymentMethodID] = &stored
+	r.nextPaymentMethodID++
+
+	methodCopy := stored
+	return &methodCopy, nil
+}
+
+// GetByID retrieves a payment method by its ID
+func (r *PaymentMethodRepository) GetByID(paymentMethodID int) (*model.PaymentMethod, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	method, exists := r.methods[paymentMethodID]
+	if !exists {
+		return nil, ErrPaymentMethodNotFound
+	}
+
+	methodCopy := *method
+	return &methodCopy, nil
+}
+
+// ListByCustomer returns a customer's payment methods, newest first
+func (r *PaymentMethodRepository) ListByCustomer(customerID int) ([]*model.PaymentMethod, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	methods := []*model.PaymentMethod{}
+	for _, method := range r.methods {
+		if method.CustomerID == customerID {
+			methodCopy := *method
+			methods = append(methods, &methodCopy)
+		}
+	}
+
+	sort.Slice(methods, func(i, j int) bool { return methods[i].PaymentMethodID > methods[j].PaymentMethodID })
+	return methods, nil
+}
+
+// SetDefault makes a payment method its customer's default
+func (r *PaymentMethodRepository) SetDefault(paymentMethodID int) (*model.PaymentMethod, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	method, exists := r.methods[paymentMethodID]
+	if !exists {
+		return nil, ErrPaymentMethodNotFound
+	}
+
+	r.clearDefault(method.CustomerID)
+	method.IsDefault = true
+
+	methodCopy := *method
+	return &methodCopy, nil
+}
+
+// Delete removes a payment method, returning it as it was
+func (r *PaymentMethodRepository) Delete(paymentMethodID int) (*model.PaymentMethod, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	method, exists := r.methods[paymentMethodID]
+	if !exists {
+		return nil, ErrPaymentMethodNotFound
+	}
+	delete(r.methods, paymentMethodID)
+
+	return method, nil
+}
+
+// clearDefault unsets a customer's default method. The caller must hold the lock.
+func (r *PaymentMethodRepository) clearDefault(customerID int) {
+	for _, method := range r.methods {
+		if method.CustomerID == customerID {
+			method.IsDefault = false
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrPaymentNotFound = errors.New("payment not found")
+	ErrPaymentConflict = errors.New("payment is not in the expected status")
+)
+
+type PaymentRepository struct {
+	payments      map[int]*model.Payment
+	mu            sync.RWMutex
+	nextPaymentID int
+	nextRefundID  int
+}
+
+func NewPaymentRepository() *PaymentRepository {
+	return &PaymentRepository{
+		payments:      make(map[int]*model.Payment),
+		nextPaymentID: 1,
+		nextRefundID:  1,
+	}
+}
+
+// Create stores a new payment and assigns its ID
+func (r *PaymentRepository) Create(payment *model.Payment) (*model.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyPayment(payment)
+	stored.PaymentID = r.nextPaymentID
+	r.payments[stored.PaymentID] = stored
+	r.nextPaymentID++
+
+	return copyPayment(stored), nil
+}
+
+// GetByID retrieves a payment by its ID
+func (r *PaymentRepository) GetByID(paymentID int) (*model.Payment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	payment, exists := r.payments[paymentID]
+	if !exists {
+		return nil, ErrPaymentNotFound
+	}
+
+	return copyPayment(payment), nil
+}
+
+// List returns payments in ID order. Empty filters match every payment.
+func (r *PaymentRepository) List(orderRef string, status model.PaymentStatus) ([]*model.Payment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	payments := []*model.Payment{}
+	for _, payment := range r.payments {
+		if (orderRef == "" || payment.OrderRef == orderRef) && (status == "" || payment.Status == status) {
+			payments = append(payments, copyPayment(payment))
+		}
+	}
+
+	sort.Slice(payments, func(i, j int) bool { return payments[i].PaymentID < payments[j].PaymentID })
+	return payments, nil
+}
+
+// Update replaces a payment, failing if its status or refunded amount has
+// changed since it was read so that concurrent changes cannot both apply.
+// Refunds without an ID are assigned one.
+func (r *PaymentRepository) Update(payment *model.Payment, from model.PaymentStatus, refundedAmount int) (*model.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.payments[payment.PaymentID]
+	if !exists {
+		return nil, ErrPaymentNotFound
+	}
+	if existing.Status != from || existing.RefundedAmount != refundedAmount {
+		return nil, ErrPaymentConflict
+	}
+
+	stored := copyPayment(payment)
+	for i := range stored.Refunds {
+		if stored.Refunds[i].RefundID == 0 {
+			stored.Refunds[i].RefundID = r.nextRefundID
+			r.nextRefundID++
+		}
+	}
+	r.payments[stored.PaymentID] = stored
+
+	return copyPayment(stored), nil
+}
+
+// copyPayment returns a copy of a payment that shares no slices with the original
+func copyPayment(payment *model.Payment) *model.Payment {
+	paymentCopy := *payment
+	paymentCopy.Refunds = append([]model.Refund{}, payment.Refunds...)
+	paymentCopy.Events = append([]model.PaymentEvent{}, payment.Events...)
+	return &paymentCopy
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrSettlementImportNotFound = errors.New("settlement import not found")
+	ErrSettlementImportExists   = errors.New("settlement file has already been imported")
+)
+
+// SettlementRepository keeps imported settlement files and their rows.
+// Imports cannot be changed once stored.
+type SettlementRepository struct {
+	imports []*model.SettlementImport
+	rows    []model.SettlementRow
+	mu      sync.RWMutex
+}
+
+func NewSettlementRepository() *SettlementRepository {
+	return &SettlementRepository{
+		imports: []*model.SettlementImport{},
+		rows:    []model.SettlementRow{},
+	}
+}
+
+// CreateImport stores an import with its rows and assigns their IDs. An
+// import with the same checksum as an earlier one is refused.
+func (r *SettlementRepository) CreateImport(settlementImport *model.SettlementImport, rows []model.SettlementRow) (*model.SettlementImport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.imports {
+		if existing.Checksum == settlementImport.Checksum {
+			return nil, ErrSettlementImportExists
+		}
+	}
+
+	stored := *settlementImport
+	stored.ImportID = len(r.imports) + 1
+	stored.Rows = nil
+	stored.Totals = append([]model.Money{}, settlementImport.Totals...)
+	r.imports = append(r.imports, &stored)
+	for _, row := range rows {
+		row.RowID = len(r.rows) + 1
+		row.ImportID = stored.ImportID
+		r.rows = append(r.rows, row)
+	}
+
+	return r.withRows(&stored), nil
+}
+
+// GetImport retrieves an import with its rows
+func (r *SettlementRepository) GetImport(importID int) (*model.SettlementImport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if importID < 1 || importID > len(r.imports) {
+		return nil, ErrSettlementImportNotFound
+	}
+
+	return r.withRows(r.imports[importID-1]), nil
+}
+
+// ListImports returns imports in the order they were made, without rows
+func (r *SettlementRepository) ListImports() ([]*model.SettlementImport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	imports := make([]*model.SettlementImport, 0, len(r.imports))
+	for _, settlementImport := range r.imports {
+		importCopy := *settlementImport
+		importCopy.Totals = append([]model.Money{}, settlementImport.Totals...)
+		imports = append(imports, &importCopy)
+	}
+
+	return imports, nil
+}
+
+// ListRows returns every imported row in the order they were imported
+func (r *SettlementRepository) ListRows() ([]model.SettlementRow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]model.SettlementRow(nil), r.rows...), nil
+}
+
+// withRows returns a copy of an import with its rows. Callers must hold the lock.
+func (r *SettlementRepository) withRows(settlementImport *model.SettlementImport) *model.SettlementImport {
+	importCopy := *settlementImport
+	importCopy.Totals = append([]model.Money{}, settlementImport.Totals...)
+	importCopy.Rows = []model.SettlementRow{}
+	for _, row := range r.rows {
+		if row.ImportID == settlementImport.ImportID {
+			importCopy.Rows = append(importCopy.Rows, row)
+		}
+	}
+	return &importCopy
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrTokenNotFound = errors.New("card token not found")
+	ErrTokenExists   = errors.New("card token already exists")
+)
+
+// VaultedCard is a tokenized card. The card number is only kept sealed.
+type VaultedCard struct {
+	model.CardToken
+	SealedNumber []byte
+}
+
+type TokenRepository struct {
+	cards map[string]*VaultedCard
+	mu    sync.RWMutex
+}
+
+func NewTokenRepository() *TokenRepository {
+	return &TokenRepository{
+		cards: make(map[string]*VaultedCard),
+	}
+}
+
+// Create stores a tokenized card under its token
+func (r *TokenRepository) Create(card *VaultedCard) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.cards[card.Token]; exists {
+		return ErrTokenExists
+	}
+	r.cards[card.Token] = copyVaultedCard(card)
+
+	return nil
+}
+
+// GetByToken retrieves a tokenized card
+func (r *TokenRepository) GetByToken(token string) (*VaultedCard, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	card, exists := r.cards[token]
+	if !exists {
+		return nil, ErrTokenNotFound
+	}
+
+	return copyVaultedCard(card), nil
+}
+
+// copyVaultedCard returns a copy of a card that shares no bytes with the original
+func copyVaultedCard(card *VaultedCard) *VaultedCard {
+	cardCopy := *card
+	cardCopy.SealedNumber = append([]byte(nil), card.SealedNumber...)
+	return &cardCopy
+}
@@ -0,0 +1,127 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"golang.org/x/net/webdav"
+
+	"github.com/gocart-v2/payment-service/internal/handler"
+)
+
+type AllHandlers struct {
+	RootHandler          *handler.RootHandler
+	PaymentHandler       *handler.PaymentHandler
+	TokenHandler         *handler.TokenHandler
+	PaymentMethodHandler *handler.PaymentMethodHandler
+	GiftCardHandler      *handler.GiftCardHandler
+	LedgerHandler        *handler.LedgerHandler
+	FraudHandler         *handler.FraudHandler
+	SettlementHandler    *handler.SettlementHandler
+	DisputeHandler       *handler.DisputeHandler
+	WebhookHandler       *handler.WebhookHandler
+	SwaggerHandler       *webdav.Handler
+}
+
+func SetupRoutes(e *gin.Engine, h *AllHandlers) {
+	root := e.Group("")
+	{
+		root.GET("/health", h.RootHandler.GetHealthStatus)
+	}
+
+	v1 := e.Group("/v1")
+	{
+		// Payment routes
+		payments := v1.Group("/payment")
+		{
+			payments.POST("", h.PaymentHandler.AuthorizePayment)
+			payments.GET("", h.PaymentHandler.ListPayments)
+			payments.GET("/:paymentId", h.PaymentHandler.GetPayment)
+			payments.POST("/:paymentId/capture", h.PaymentHandler.CapturePayment)
+			payments.POST("/:paymentId/void", h.PaymentHandler.VoidPayment)
+			payments.POST("/:paymentId/refunds", h.PaymentHandler.RefundPayment)
+		}
+
+		// Card vault routes
+		tokens := v1.Group("/card-token")
+		{
+			tokens.POST("", h.TokenHandler.TokenizeCard)
+			tokens.GET("/:cardToken", h.TokenHandler.GetCardToken)
+		}
+
+		// Saved payment method routes
+		methods := v1.Group("/payment-method")
+		{
+			methods.POST("", h.PaymentMethodHandler.CreatePaymentMethod)
+			methods.GET("", h.PaymentMethodHandler.ListPaymentMethods)
+			methods.GET("/:paymentMethodId", h.PaymentMethodHandler.GetPaymentMethod)
+			methods.PUT("/:paymentMethodId/default", h.PaymentMethodHandler.SetDefaultPaymentMethod)
+			methods.DELETE("/:paymentMethodId", h.PaymentMethodHandler.DeletePaymentMethod)
+		}
+
+		// Gift card routes
+		giftCards := v1.Group("/gift-card")
+		{
+			giftCards.POST("", h.GiftCardHandler.IssueGiftCard)
+			giftCards.GET("/:code", h.GiftCardHandler.GetGiftCard)
+			giftCards.POST("/:code/redeem", h.GiftCardHandler.RedeemGiftCard)
+		}
+
+		// Ledger routes
+		ledger := v1.Group("/ledger")
+		{
+			ledger.GET("/journals", h.LedgerHandler.ListJournals)
+			ledger.GET("/journals/:journalId", h.LedgerHandler.GetJournal)
+			ledger.GET("/trial-balance", h.LedgerHandler.GetTrialBalance)
+			ledger.GET("/check", h.LedgerHandler.CheckJournals)
+			ledger.GET("/failed-postings", h.LedgerHandler.ListFailedPostings)
+			ledger.POST("/failed-postings/retry", h.LedgerHandler.RetryFailedPostings)
+		}
+		// Fraud screening routes
+		fraud := v1.Group("/fraud")
+		{
+			fraud.GET("/reviews", h.FraudHandler.ListReviews)
+			fraud.POST("/reviews/:paymentId/release", h.FraudHandler.ReleasePayment)
+			fraud.POST("/reviews/:paymentId/cancel", h.FraudHandler.CancelPayment)
+			fraud.POST("/deny-list", h.FraudHandler.CreateDenyListEntry)
+			fraud.GET("/deny-list", h.FraudHandler.ListDenyList)
+			fraud.DELETE("/deny-list/:entryId", h.FraudHandler.DeleteDenyListEntry)
+		}
+
+		// Settlement reconciliation routes
+		settlement := v1.Group("/settlement")
+		{
+			settlement.POST("/imports", h.SettlementHandler.ImportSettlementFile)
+			settlement.GET("/imports", h.SettlementHandler.ListSettlementImports)
+			settlement.GET("/imports/:importId", h.SettlementHandler.GetSettlementImport)
+			settlement.GET("/reconciliation", h.SettlementHandler.GetReconciliation)
+		}
+
+		// Dispute routes
+		disputes := v1.Group("/dispute")
+		{
+			disputes.POST("", h.DisputeHandler.OpenDispute)
+			disputes.GET("", h.DisputeHandler.ListDisputes)
+			disputes.GET("/:disputeId", h.DisputeHandler.GetDispute)
+			disputes.POST("/:disputeId/evidence", h.DisputeHandler.AddEvidence)
+			disputes.GET("/:disputeId/evidence/:evidenceId", h.DisputeHandler.GetEvidence)
+			disputes.POST("/:disputeId/submit", h.DisputeHandler.SubmitDispute)
+			disputes.POST("/:disputeId/resolve", h.DisputeHandler.ResolveDispute)
+		}
+
+		// Webhook routes
+		webhooks := v1.Group("/webhook")
+		{
+			webhooks.POST("/subscriptions", h.WebhookHandler.CreateSubscription)
+			webhooks.GET("/subscriptions", h.WebhookHandler.ListSubscriptions)
+			webhooks.DELETE("/subscriptions/:subscriptionId", h.WebhookHandler.DeleteSubscription)
+			webhooks.GET("/deliveries", h.WebhookHandler.ListDeliveries)
+			webhooks.POST("/deliveries/:deliveryId/redeliver", h.WebhookHandler.RedeliverDelivery)
+			webhooks.GET("/dead-letters", h.WebhookHandler.ListDeadLetters)
+		}
+	}
+
+	swagger := e.Group("/swagger")
+	{
+		swagger.GET("/*any", ginSwagger.WrapHandler(h.SwaggerHandler))
+	}
+}
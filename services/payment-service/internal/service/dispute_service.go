@@ -0,0 +1,404 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/payment-service/internal/repository"
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/shared/webhook"
+)
+
+var (
+	ErrInvalidDispute           = errors.New("invalid dispute data")
+	ErrDisputeNotFound          = errors.New("dispute not found")
+	ErrDisputeExists            = errors.New("payment already has an open dispute")
+	ErrPaymentNotDisputable     = errors.New("only captured card payments with an amount neither refunded nor disputed can be disputed")
+	ErrInvalidDisputeTransition = errors.New("dispute cannot make this change in its current status")
+	ErrDisputeChanged           = errors.New("dispute was changed by another request")
+	ErrEvidenceRequired         = errors.New("dispute has no evidence to submit")
+	ErrEvidenceNotFound         = errors.New("evidence not found")
+	ErrEvidenceTooLarge         = errors.New("evidence file is too large")
+	ErrResponseOverdue          = errors.New("dispute response deadline has passed")
+)
+
+// DefaultResponseWindow is how long there is to respond to a dispute when
+// the processor does not give a deadline
+const DefaultResponseWindow = 7 * 24 * time.Hour
+
+// MaxEvidenceSize is the largest evidence file accepted, in bytes
+const MaxEvidenceSize = 5 << 20
+
+// DefaultReminders are how long before a response deadline reminders go out
+var DefaultReminders = []time.Duration{72 * time.Hour, 24 * time.Hour}
+
+// disputeTransitions lists the statuses each dispute status may move to.
+// Accepting a dispute without responding loses it.
+var disputeTransitions = map[model.DisputeStatus][]model.DisputeStatus{
+	model.DisputeNeedsResponse: {model.DisputeUnderReview, model.DisputeLost},
+	model.DisputeUnderReview:   {model.DisputeWon, model.DisputeLost},
+}
+
+type DisputeService struct {
+	repo      *repository.DisputeRepository
+	payments  *PaymentService
+	ledger    *LedgerService
+	webhooks  *webhook.Dispatcher
+	reminders []time.Duration
+
+	// opening serializes opening disputes so a payment cannot get two
+	opening sync.Mutex
+}
+
+func NewDisputeService(
+	repo *repository.DisputeRepository,
+	payments *PaymentService,
+	ledger *LedgerService,
+	webhooks *webhook.Dispatcher,
+	reminders []time.Duration,
+) *DisputeService {
+	return &DisputeService{repo: repo, payments: payments, ledger: ledger, webhooks: webhooks, reminders: reminders}
+}
+
+// OpenDispute records a dispute reported by the processor and withholds
+// the disputed amount on the payment and in the ledger, so that it cannot
+// also be refunded
+func (s *DisputeService) OpenDispute(req *model.OpenDisputeRequest) (*model.Dispute, error) {
+	now := time.Now().UTC()
+	respondBy := now.Add(DefaultResponseWindow)
+	if req.RespondBy != nil {
+		respondBy = req.RespondBy.UTC()
+	}
+	if req.Amount < 0 || req.Reason == "" || !respondBy.After(now) {
+		return nil, ErrInvalidDispute
+	}
+
+	s.opening.Lock()
+	defer s.opening.Unlock()
+
+	payment, err := s.payments.GetPayment(req.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Instrument == model.InstrumentGiftCard || payment.Refundable() == 0 ||
+		(payment.Status != model.PaymentCaptured && payment.Status != model.PaymentPartiallyRefunded) {
+		return nil, ErrPaymentNotDisputable
+	}
+	amount := req.Amount
+	if amount == 0 {
+		amount = payment.Refundable()
+	}
+	if amount > payment.Refundable() {
+		return nil, ErrAmountExceeded
+	}
+	// Check the ledger entry before withholding anything; it is built
+	// again with the dispute's ID once the dispute is recorded
+	if _, err := s.ledger.DisputeJournals(payment, &model.Dispute{Amount: amount}, model.JournalDisputeOpened); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.List(payment.PaymentID, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, dispute := range existing {
+		if dispute.Status == model.DisputeNeedsResponse || dispute.Status == model.DisputeUnderReview {
+			return nil, ErrDisputeExists
+		}
+	}
+
+	if payment, err = s.payments.withholdDisputed(payment.PaymentID, amount); err != nil {
+		return nil, err
+	}
+	dispute, err := s.repo.Create(&model.Dispute{
+		PaymentID:  payment.PaymentID,
+		Amount:     amount,
+		Currency:   payment.Currency,
+		Reason:     req.Reason,
+		ReasonCode: req.ReasonCode,
+		Status:     model.DisputeNeedsResponse,
+		RespondBy:  respondBy,
+		Evidence:   []model.DisputeEvidence{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		if _, releaseErr := s.payments.releaseDisputed(payment.PaymentID, amount); releaseErr != nil {
+			log.Printf("Failed to release disputed amount of payment %d: %v", payment.PaymentID, releaseErr)
+		}
+		return nil, err
+	}
+
+	journals, err := s.ledger.DisputeJournals(payment, dispute, model.JournalDisputeOpened)
+	if err == nil {
+		err = s.ledger.Post(journals)
+	}
+	if err != nil {
+		log.Printf("Failed to post dispute %d to the ledger, queued for retry: %v", dispute.DisputeID, err)
+	}
+	if err := s.webhooks.Publish(model.EventDisputeOpened, dispute); err != nil {
+		log.Printf("Failed to publish dispute %d: %v", dispute.DisputeID, err)
+	}
+	return dispute, nil
+}
+
+// GetDispute retrieves a dispute
+func (s *DisputeService) GetDispute(disputeID int) (*model.Dispute, error) {
+	if disputeID < 1 {
+		return nil, ErrInvalidDispute
+	}
+
+	dispute, err := s.repo.GetByID(disputeID)
+	if err == repository.ErrDisputeNotFound {
+		return nil, ErrDisputeNotFound
+	}
+	return dispute, err
+}
+
+// ListDisputes returns disputes, optionally for one payment or status
+func (s *DisputeService) ListDisputes(paymentID int, status model.DisputeStatus) ([]*model.Dispute, error) {
+	switch status {
+	case "", model.DisputeNeedsResponse, model.DisputeUnderReview, model.DisputeWon, model.DisputeLost:
+	default:
+		return nil, ErrInvalidDispute
+	}
+	if paymentID < 0 {
+		return nil, ErrInvalidDispute
+	}
+
+	return s.repo.List(paymentID, status)
+}
+
+// AddEvidence attaches a file to a dispute that still needs a response
+func (s *DisputeService) AddEvidence(disputeID int, kind model.EvidenceKind, fileName, contentType string, content []byte) (*model.Dispute, error) {
+	switch kind {
+	case model.EvidenceReceipt, model.EvidenceShippingDocumentation, model.EvidenceCustomerCommunication,
+		model.EvidenceRefundPolicy, model.EvidenceOther:
+	default:
+		return nil, ErrInvalidDispute
+	}
+	if fileName == "" || len(content) == 0 {
+		return nil, ErrInvalidDispute
+	}
+	if len(content) > MaxEvidenceSize {
+		return nil, ErrEvidenceTooLarge
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	dispute, err := s.GetDispute(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status != model.DisputeNeedsResponse {
+		return nil, ErrInvalidDisputeTransition
+	}
+
+	updated, err := s.repo.AddEvidence(disputeID, model.DisputeNeedsResponse, &model.DisputeEvidence{
+		Kind:        kind,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        len(content),
+		UploadedAt:  time.Now().UTC(),
+	}, content)
+	if err == repository.ErrDisputeConflict {
+		return nil, ErrInvalidDisputeTransition
+	}
+	return updated, err
+}
+
+// GetEvidence retrieves a piece of a dispute's evidence with its content
+func (s *DisputeService) GetEvidence(disputeID, evidenceID int) (*model.DisputeEvidence, []byte, error) {
+	if disputeID < 1 || evidenceID < 1 {
+		return nil, nil, ErrInvalidDispute
+	}
+
+	evidence, content, err := s.repo.GetEvidence(disputeID, evidenceID)
+	switch err {
+	case repository.ErrDisputeNotFound:
+		return nil, nil, ErrDisputeNotFound
+	case repository.ErrEvidenceNotFound:
+		return nil, nil, ErrEvidenceNotFound
+	}
+	return evidence, content, err
+}
+
+// SubmitDispute sends a dispute's evidence to the card network for review.
+// It must be submitted before the response deadline.
+func (s *DisputeService) SubmitDispute(disputeID int) (*model.Dispute, error) {
+	dispute, err := s.GetDispute(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status != model.DisputeNeedsResponse {
+		return nil, ErrInvalidDisputeTransition
+	}
+	if len(dispute.Evidence) == 0 {
+		return nil, ErrEvidenceRequired
+	}
+	now := time.Now().UTC()
+	if now.After(dispute.RespondBy) {
+		return nil, ErrResponseOverdue
+	}
+
+	dispute.SubmittedAt = &now
+	return s.transition(dispute, model.DisputeUnderReview, dispute.RemindersSent)
+}
+
+// ResolveDispute records the card network's ruling. A won dispute restores
+// the withheld amount, which can then be refunded again, and a lost one
+// reverses it out of the merchant's balance for good.
+func (s *DisputeService) ResolveDispute(disputeID int, outcome model.DisputeStatus) (*model.Dispute, error) {
+	if outcome != model.DisputeWon && outcome != model.DisputeLost {
+		return nil, ErrInvalidDispute
+	}
+
+	dispute, err := s.GetDispute(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.close(dispute, outcome)
+}
+
+// SendReminders sends the reminders that have come due for disputes still
+// needing a response, and loses those whose deadline has passed. It
+// returns how many reminders were sent.
+func (s *DisputeService) SendReminders() (int, error) {
+	disputes, err := s.repo.List(0, model.DisputeNeedsResponse)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	sent := 0
+	for _, dispute := range disputes {
+		if now.After(dispute.RespondBy) {
+			if _, err := s.close(dispute, model.DisputeLost); err != nil {
+				log.Printf("Failed to close overdue dispute %d: %v", dispute.DisputeID, err)
+			}
+			continue
+		}
+
+		due := 0
+		for _, before := range s.reminders {
+			if !now.Before(dispute.RespondBy.Add(-before)) {
+				due++
+			}
+		}
+		if due <= dispute.RemindersSent {
+			continue
+		}
+
+		updated, err := s.transition(dispute, model.DisputeNeedsResponse, due)
+		if err != nil {
+			log.Printf("Failed to record reminder for dispute %d: %v", dispute.DisputeID, err)
+			continue
+		}
+		log.Printf("Dispute %d on payment %d needs a response by %s", updated.DisputeID, updated.PaymentID, updated.RespondBy.Format(time.RFC3339))
+		if err := s.webhooks.Publish(model.EventDisputeDue, updated); err != nil {
+			log.Printf("Failed to publish reminder for dispute %d: %v", updated.DisputeID, err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// RunReminders sends due reminders every interval. It never returns.
+func (s *DisputeService) RunReminders(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if count, err := s.SendReminders(); err != nil {
+			log.Println("Failed to send dispute reminders:", err)
+		} else if count > 0 {
+			log.Printf("Sent %d dispute reminders", count)
+		}
+	}
+}
+
+// close moves a dispute to won or lost and posts the outcome to the ledger
+func (s *DisputeService) close(dispute *model.Dispute, outcome model.DisputeStatus) (*model.Dispute, error) {
+	if !canTransitionDispute(dispute.Status, outcome) {
+		return nil, ErrInvalidDisputeTransition
+	}
+
+	payment, err := s.payments.GetPayment(dispute.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	kind := model.JournalDisputeWon
+	if outcome == model.DisputeLost {
+		kind = model.JournalDisputeLost
+	}
+	journals, err := s.ledger.DisputeJournals(payment, dispute, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	// A won dispute's amount can be refunded again; a lost one stays
+	// withheld since the cardholder already has it back
+	if outcome == model.DisputeWon {
+		if payment, err = s.payments.releaseDisputed(payment.PaymentID, dispute.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	dispute.ClosedAt = &now
+	updated, err := s.transition(dispute, outcome, dispute.RemindersSent)
+	if err != nil {
+		if outcome == model.DisputeWon {
+			if _, withholdErr := s.payments.withholdDisputed(payment.PaymentID, dispute.Amount); withholdErr != nil {
+				log.Printf("Failed to withhold disputed amount of payment %d again: %v", payment.PaymentID, withholdErr)
+			}
+		}
+		return nil, err
+	}
+
+	if err := s.ledger.Post(journals); err != nil {
+		log.Printf("Failed to post outcome of dispute %d to the ledger, queued for retry: %v", updated.DisputeID, err)
+	}
+	if err := s.webhooks.Publish(model.EventDisputeClosed, updated); err != nil {
+		log.Printf("Failed to publish outcome of dispute %d: %v", updated.DisputeID, err)
+	}
+	return updated, nil
+}
+
+// transition moves a dispute read from the repository to a new status. A
+// dispute needing a response may stay in it to record reminders.
+func (s *DisputeService) transition(dispute *model.Dispute, to model.DisputeStatus, remindersSent int) (*model.Dispute, error) {
+	from, sent := dispute.Status, dispute.RemindersSent
+	if !(to == from && to == model.DisputeNeedsResponse) && !canTransitionDispute(from, to) {
+		return nil, ErrInvalidDisputeTransition
+	}
+
+	dispute.Status = to
+	dispute.RemindersSent = remindersSent
+	dispute.UpdatedAt = time.Now().UTC()
+
+	updated, err := s.repo.Update(dispute, from, sent)
+	switch err {
+	case nil:
+		return updated, nil
+	case repository.ErrDisputeNotFound:
+		return nil, ErrDisputeNotFound
+	case repository.ErrDisputeConflict:
+		return nil, ErrDisputeChanged
+	default:
+		return nil, err
+	}
+}
+
+// canTransitionDispute reports whether a dispute may move from one status to another
+func canTransitionDispute(from, to model.DisputeStatus) bool {
+	for _, allowed := range disputeTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocart-v2/payment-service/internal/fraud"
+	"github.com/gocart-v2/payment-service/internal/repository"
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrInvalidDenyListEntry  = errors.New("invalid deny list entry")
+	ErrDenyListEntryNotFound = errors.New("deny list entry not found")
+	ErrDenyListEntryExists   = errors.New("value is already on the deny list")
+)
+
+type FraudService struct {
+	denyList *repository.DenyListRepository
+	engine   *fraud.Engine
+}
+
+func NewFraudService(denyList *repository.DenyListRepository, engine *fraud.Engine) *FraudService {
+	return &FraudService{denyList: denyList, engine: engine}
+}
+
+// Screen scores a payment before it is sent to the gateway
+func (s *FraudService) Screen(in fraud.Input) *model.FraudScreening {
+	return s.engine.Screen(in)
+}
+
+// AddDenyListEntry denies a customer, card token or country. Customers are
+// given by ID and countries by ISO code.
+func (s *FraudService) AddDenyListEntry(req *model.CreateDenyListEntryRequest) (*model.DenyListEntry, error) {
+	value := strings.TrimSpace(req.Value)
+	switch req.Kind {
+	case model.DenyCustomer:
+		if customerID, err := strconv.Atoi(value); err != nil || customerID < 1 {
+			return nil, ErrInvalidDenyListEntry
+		}
+	case model.DenyCardToken:
+		if value == "" {
+			return nil, ErrInvalidDenyListEntry
+		}
+	case model.DenyCountry:
+		value = strings.ToUpper(value)
+		if len(value) != 2 {
+			return nil, ErrInvalidDenyListEntry
+		}
+	default:
+		return nil, ErrInvalidDenyListEntry
+	}
+
+	entry, err := s.denyList.Create(&model.DenyListEntry{
+		Kind:      req.Kind,
+		Value:     value,
+		Reason:    req.Reason,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err == repository.ErrDenyListEntryExists {
+		return nil, ErrDenyListEntryExists
+	}
+	return entry, err
+}
+
+// ListDenyList returns deny list entries, optionally only those of one kind
+func (s *FraudService) ListDenyList(kind model.DenyListKind) ([]*model.DenyListEntry, error) {
+	switch kind {
+	case "", model.DenyCustomer, model.DenyCardToken, model.DenyCountry:
+	default:
+		return nil, ErrInvalidDenyListEntry
+	}
+
+	return s.denyList.List(kind)
+}
+
+// RemoveDenyListEntry takes a value off the deny list
+func (s *FraudService) RemoveDenyListEntry(entryID int) error {
+	if entryID < 1 {
+		return ErrInvalidDenyListEntry
+	}
+
+	err := s.denyList.Delete(entryID)
+	if err == repository.ErrDenyListEntryNotFound {
+		return ErrDenyListEntryNotFound
+	}
+	return err
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gocart-v2/payment-service/internal/repository"
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrInvalidGiftCard     = errors.New("invalid gift card data")
+	ErrGiftCardNotFound    = errors.New("gift card not found")
+	ErrGiftCardExpired     = errors.New("gift card has expired")
+	ErrGiftCardCurrency    = errors.New("gift card is in a different currency")
+	ErrInsufficientBalance = errors.New("gift card balance is too low")
+)
+
+// GiftCardGateway names gift cards as the gateway on payments made with them
+const GiftCardGateway = "GIFT_CARD"
+
+// giftCardAlphabet leaves out characters that are easily misread, such as 0 and O
+const giftCardAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// giftCardCodeLength is the number of characters in a code, not counting separators
+const giftCardCodeLength = 16
+
+type GiftCardService struct {
+	repo *repository.GiftCardRepository
+}
+
+func NewGiftCardService(repo *repository.GiftCardRepository) *GiftCardService {
+	return &GiftCardService{repo: repo}
+}
+
+// IssueGiftCard creates a gift card with a new random code holding the
+// requested balance
+func (s *GiftCardService) IssueGiftCard(req *model.IssueGiftCardRequest) (*model.GiftCard, error) {
+	now := time.Now().UTC()
+	if req.Amount < 1 || (req.ExpiresAt != nil && !req.ExpiresAt.After(now)) {
+		return nil, ErrInvalidGiftCard
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil {
+		expires := req.ExpiresAt.UTC()
+		expiresAt = &expires
+	}
+
+	// Retry the rare code that is already taken
+	for {
+		code, err := newGiftCardCode()
+		if err != nil {
+			return nil, err
+		}
+
+		card, err := s.repo.Create(&model.GiftCard{
+			Code:           code,
+			Currency:       currency,
+			InitialBalance: req.Amount,
+			Balance:        req.Amount,
+			ExpiresAt:      expiresAt,
+			Transactions: []model.GiftCardTransaction{{
+				Kind:      model.GiftCardIssue,
+				Amount:    req.Amount,
+				Balance:   req.Amount,
+				CreatedAt: now,
+			}},
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != repository.ErrGiftCardExists {
+			return card, err
+		}
+	}
+}
+
+// GetGiftCard retrieves a gift card, with its balance and transactions, by
+// its code. Codes match regardless of case, spaces and dashes.
+func (s *GiftCardService) GetGiftCard(code string) (*model.GiftCard, error) {
+	code, ok := normalizeGiftCardCode(code)
+	if !ok {
+		return nil, ErrGiftCardNotFound
+	}
+
+	card, err := s.repo.GetByCode(code)
+	if err == repository.ErrGiftCardNotFound {
+		return nil, ErrGiftCardNotFound
+	}
+	return card, err
+}
+
+// RedeemGiftCard spends part of a gift card's balance outside of a payment
+func (s *GiftCardService) RedeemGiftCard(code string, req *model.RedeemGiftCardRequest) (*model.GiftCard, error) {
+	if req.Amount < 1 || req.Reference == "" {
+		return nil, ErrInvalidGiftCard
+	}
+
+	card, err := s.GetGiftCard(code)
+	if err != nil {
+		return nil, err
+	}
+	if giftCardExpired(card, time.Now()) {
+		return nil, ErrGiftCardExpired
+	}
+
+	return s.apply(card.GiftCardID, model.GiftCardRedeem, -req.Amount, req.Reference)
+}
+
+// hold takes a payment's amount from a gift card's balance until the
+// payment is captured, voided or refunded. The card is returned even when
+// its balance is too low.
+func (s *GiftCardService) hold(code string, payment *model.Payment) (*model.GiftCard, error) {
+	card, err := s.GetGiftCard(code)
+	if err != nil {
+		return nil, err
+	}
+	if giftCardExpired(card, time.Now()) {
+		return nil, ErrGiftCardExpired
+	}
+	if card.Currency != payment.Currency {
+		return nil, ErrGiftCardCurrency
+	}
+
+	if _, err := s.apply(card.GiftCardID, model.GiftCardHold, -payment.Amount, payment.GatewayRef); err != nil {
+		return card, err
+	}
+	return card, nil
+}
+
+// release returns part of a gift card payment's hold that will not be
+// captured to the card's balance
+func (s *GiftCardService) release(payment *model.Payment, amount int) error {
+	if amount == 0 {
+		return nil
+	}
+	_, err := s.apply(payment.GiftCard.GiftCardID, model.GiftCardRelease, amount, payment.GatewayRef)
+	return err
+}
+
+// refund returns part of a captured gift card payment to the card's balance
+func (s *GiftCardService) refund(payment *model.Payment, amount int) error {
+	_, err := s.apply(payment.GiftCard.GiftCardID, model.GiftCardRefund, amount, payment.GatewayRef)
+	return err
+}
+
+// apply records a change to a gift card's balance
+func (s *GiftCardService) apply(giftCardID int, kind model.GiftCardTransactionKind, amount int, reference string) (*model.GiftCard, error) {
+	card, err := s.repo.Apply(giftCardID, &model.GiftCardTransaction{
+		Kind:      kind,
+		Amount:    amount,
+		Reference: reference,
+		CreatedAt: time.Now().UTC(),
+	})
+	switch err {
+	case repository.ErrGiftCardNotFound:
+		return nil, ErrGiftCardNotFound
+	case repository.ErrInsufficientBalance:
+		return nil, ErrInsufficientBalance
+	default:
+		return card, err
+	}
+}
+
+// giftCardExpired reports whether a gift card stopped working before now
+func giftCardExpired(card *model.GiftCard, now time.Time) bool {
+	return card.ExpiresAt != nil && !now.Before(*card.ExpiresAt)
+}
+
+// normalizeGiftCardCode uppercases a code and groups it in fours with
+// dashes, reporting false if it cannot be a gift card code
+func normalizeGiftCardCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != giftCardCodeLength {
+		return "", false
+	}
+
+	var b strings.Builder
+	for i, c := range code {
+		if !strings.ContainsRune(giftCardAlphabet, c) {
+			return "", false
+		}
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(c)
+	}
+	return b.String(), true
+}
+
+// newGiftCardCode returns a random gift card code
+func newGiftCardCode() (string, error) {
+	b := make([]byte, giftCardCodeLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = giftCardAlphabet[int(b[i])%len(giftCardAlphabet)]
+	}
+
+	code, _ := normalizeGiftCardCode(string(b))
+	return code, nil
+}
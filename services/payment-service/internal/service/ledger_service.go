@@ -0,0 +1,361 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/payment-service/internal/repository"
+	"github.com/gocart-v2/shared/currency"
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrInvalidJournal    = errors.New("invalid journal data")
+	ErrUnbalancedJournal = errors.New("journal lines do not sum to zero")
+	ErrJournalNotFound   = errors.New("journal not found")
+)
+
+// FeeSchedule is what the processor charges per capture: a rate in basis
+// points of the amount plus a fixed amount in hundredths of the payment
+// currency's major unit, scaled to its minor units
+type FeeSchedule struct {
+	RateBasisPoints int
+	Fixed           int
+}
+
+// MaxPostingAttempts is how many times a journal is tried before it is
+// dead-lettered and left for someone to retry by hand
+const MaxPostingAttempts = 10
+
+// DefaultFeeSchedule is a typical card-not-present rate of 2.9% + 0.30
+var DefaultFeeSchedule = FeeSchedule{RateBasisPoints: 290, Fixed: 30}
+
+// FeeFor returns the fee for capturing amount in the minor units of
+// currencyCode, rounded half up and never more than the amount itself
+func (f FeeSchedule) FeeFor(amount int, currencyCode string) (int, error) {
+	fixed, err := currency.FromHundredths(f.Fixed, currencyCode)
+	if err != nil {
+		return 0, err
+	}
+	fee := (amount*f.RateBasisPoints+5000)/10000 + fixed
+	if fee > amount {
+		return amount, nil
+	}
+	return fee, nil
+}
+
+type LedgerService struct {
+	repo *repository.LedgerRepository
+	fees FeeSchedule
+
+	// retrying serializes retries so a queued journal is posted once
+	retrying sync.Mutex
+}
+
+func NewLedgerService(repo *repository.LedgerRepository, fees FeeSchedule) *LedgerService {
+	return &LedgerService{repo: repo, fees: fees}
+}
+
+// CaptureJournals builds the journals for a captured payment: the
+// receivable from the processor against the merchant's balance, and the
+// processor's fee taken out of the receivable. Gift card payments never
+// reach the processor, so they are posted against the gift card balance
+// spent, without a fee.
+func (s *LedgerService) CaptureJournals(payment *model.Payment) ([]*model.Journal, error) {
+	capture, err := newJournal(payment, model.JournalCapture, fmt.Sprintf("Captured payment %d", payment.PaymentID), []model.JournalLine{
+		{Account: fundingAccount(payment), Amount: payment.CapturedAmount},
+		{Account: model.AccountMerchantBalance, Amount: -payment.CapturedAmount},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := s.fees.FeeFor(payment.CapturedAmount, payment.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if fee == 0 || payment.Instrument == model.InstrumentGiftCard {
+		return []*model.Journal{capture}, nil
+	}
+	feeJournal, err := newJournal(payment, model.JournalFee, fmt.Sprintf("Processor fee for payment %d", payment.PaymentID), []model.JournalLine{
+		{Account: model.AccountProcessorFees, Amount: fee},
+		{Account: model.AccountCustomerReceivable, Amount: -fee},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []*model.Journal{capture, feeJournal}, nil
+}
+
+// RefundJournals builds the journal for money returned to the card or gift
+// card, taken from the merchant's balance. The processor keeps its fee.
+func (s *LedgerService) RefundJournals(payment *model.Payment, amount int) ([]*model.Journal, error) {
+	refund, err := newJournal(payment, model.JournalRefund, fmt.Sprintf("Refunded payment %d", payment.PaymentID), []model.JournalLine{
+		{Account: model.AccountMerchantBalance, Amount: amount},
+		{Account: fundingAccount(payment), Amount: -amount},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []*model.Journal{refund}, nil
+}
+
+// DisputeJournals builds the journal for a dispute event. Opening one
+// posts the processor withholding the disputed amount from what it owes;
+// winning restores it to what the processor owes, and losing returns it to
+// the cardholder out of the merchant's balance.
+func (s *LedgerService) DisputeJournals(payment *model.Payment, dispute *model.Dispute, kind model.JournalKind) ([]*model.Journal, error) {
+	var description string
+	var debit, credit model.LedgerAccount
+	switch kind {
+	case model.JournalDisputeOpened:
+		description = fmt.Sprintf("Dispute %d opened on payment %d", dispute.DisputeID, payment.PaymentID)
+		debit, credit = model.AccountDisputedFunds, model.AccountCustomerReceivable
+	case model.JournalDisputeWon:
+		description = fmt.Sprintf("Dispute %d won on payment %d", dispute.DisputeID, payment.PaymentID)
+		debit, credit = model.AccountCustomerReceivable, model.AccountDisputedFunds
+	case model.JournalDisputeLost:
+		description = fmt.Sprintf("Dispute %d lost on payment %d", dispute.DisputeID, payment.PaymentID)
+		debit, credit = model.AccountMerchantBalance, model.AccountDisputedFunds
+	default:
+		return nil, ErrInvalidJournal
+	}
+
+	journal, err := newJournal(payment, kind, description, []model.JournalLine{
+		{Account: debit, Amount: dispute.Amount},
+		{Account: credit, Amount: -dispute.Amount},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []*model.Journal{journal}, nil
+}
+
+// Post appends journals built by CaptureJournals, RefundJournals or
+// DisputeJournals. A journal the repository fails to store is queued to be
+// retried, and the error is still returned.
+func (s *LedgerService) Post(journals []*model.Journal) error {
+	var errs []error
+	for _, journal := range journals {
+		journal.PostedAt = time.Now().UTC()
+		if _, err := s.repo.Append(journal); err != nil {
+			if _, queueErr := s.repo.AddFailed(journal, err.Error(), journal.PostedAt); queueErr != nil {
+				err = errors.Join(err, queueErr)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetJournal retrieves a journal
+func (s *LedgerService) GetJournal(journalID int) (*model.Journal, error) {
+	if journalID < 1 {
+		return nil, ErrInvalidJournal
+	}
+
+	journal, err := s.repo.GetByID(journalID)
+	if err == repository.ErrJournalNotFound {
+		return nil, ErrJournalNotFound
+	}
+	return journal, err
+}
+
+// ListJournals returns journals in the order they were posted, optionally
+// only those for one payment
+func (s *LedgerService) ListJournals(paymentID int) ([]*model.Journal, error) {
+	if paymentID < 0 {
+		return nil, ErrInvalidJournal
+	}
+
+	return s.repo.List(paymentID)
+}
+
+// TrialBalance totals the debits and credits posted to every account,
+// separately for each currency
+func (s *LedgerService) TrialBalance() (*model.TrialBalance, error) {
+	journals, err := s.repo.List(0)
+	if err != nil {
+		return nil, err
+	}
+
+	type accountKey struct {
+		account  model.LedgerAccount
+		currency string
+	}
+	balances := make(map[accountKey]*model.AccountBalance)
+	totals := make(map[string]*model.CurrencyTotals)
+	for _, journal := range journals {
+		total, exists := totals[journal.Currency]
+		if !exists {
+			total = &model.CurrencyTotals{Currency: journal.Currency}
+			totals[journal.Currency] = total
+		}
+		for _, line := range journal.Lines {
+			key := accountKey{account: line.Account, currency: journal.Currency}
+			balance, exists := balances[key]
+			if !exists {
+				balance = &model.AccountBalance{Account: line.Account, Currency: journal.Currency}
+				balances[key] = balance
+			}
+			if line.Amount > 0 {
+				balance.Debits += line.Amount
+				total.TotalDebits += line.Amount
+			} else {
+				balance.Credits -= line.Amount
+				total.TotalCredits -= line.Amount
+			}
+			balance.Balance += line.Amount
+		}
+	}
+
+	trialBalance := &model.TrialBalance{
+		Accounts:   make([]model.AccountBalance, 0, len(balances)),
+		Currencies: make([]model.CurrencyTotals, 0, len(totals)),
+		Balanced:   true,
+		AsOf:       time.Now().UTC(),
+	}
+	for _, balance := range balances {
+		trialBalance.Accounts = append(trialBalance.Accounts, *balance)
+	}
+	sort.Slice(trialBalance.Accounts, func(i, j int) bool {
+		a, b := trialBalance.Accounts[i], trialBalance.Accounts[j]
+		if a.Currency != b.Currency {
+			return a.Currency < b.Currency
+		}
+		return a.Account < b.Account
+	})
+	for _, total := range totals {
+		total.Balanced = total.TotalDebits == total.TotalCredits
+		trialBalance.Balanced = trialBalance.Balanced && total.Balanced
+		trialBalance.Currencies = append(trialBalance.Currencies, *total)
+	}
+	sort.Slice(trialBalance.Currencies, func(i, j int) bool {
+		return trialBalance.Currencies[i].Currency < trialBalance.Currencies[j].Currency
+	})
+	return trialBalance, nil
+}
+
+// CheckJournals verifies that every posted journal sums to zero and that
+// no journal is still waiting to be posted
+func (s *LedgerService) CheckJournals() (*model.LedgerCheck, error) {
+	journals, err := s.repo.List(0)
+	if err != nil {
+		return nil, err
+	}
+	failed, err := s.repo.ListFailed()
+	if err != nil {
+		return nil, err
+	}
+
+	check := &model.LedgerCheck{JournalsChecked: len(journals), UnbalancedJournals: []int{}, FailedPostings: []int{}}
+	for _, journal := range journals {
+		if sumLines(journal.Lines) != 0 {
+			check.UnbalancedJournals = append(check.UnbalancedJournals, journal.JournalID)
+		}
+	}
+	for _, posting := range failed {
+		check.FailedPostings = append(check.FailedPostings, posting.PostingID)
+	}
+	check.Balanced = len(check.UnbalancedJournals) == 0 && len(check.FailedPostings) == 0
+	return check, nil
+}
+
+// ListFailedPostings returns the journals waiting to be posted, oldest
+// first, including those that gave up retrying
+func (s *LedgerService) ListFailedPostings() ([]*model.FailedPosting, error) {
+	return s.repo.ListFailed()
+}
+
+// RetryFailedPostings tries again to post queued journals and returns how
+// many were posted. Journals that fail again stay queued, and a pending one
+// is dead-lettered after MaxPostingAttempts. Dead-lettered journals are
+// only retried when includeDeadLettered is set.
+func (s *LedgerService) RetryFailedPostings(includeDeadLettered bool) (int, error) {
+	s.retrying.Lock()
+	defer s.retrying.Unlock()
+
+	failed, err := s.repo.ListFailed()
+	if err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	for _, posting := range failed {
+		if posting.Status == model.PostingDeadLettered && !includeDeadLettered {
+			continue
+		}
+		journal := posting.Journal
+		journal.PostedAt = time.Now().UTC()
+		if _, err := s.repo.Append(&journal); err != nil {
+			if err := s.repo.RecordFailedAttempt(posting.PostingID, err.Error(), journal.PostedAt, MaxPostingAttempts); err != nil {
+				return posted, err
+			}
+			continue
+		}
+		if err := s.repo.RemoveFailed(posting.PostingID); err != nil {
+			return posted, err
+		}
+		posted++
+	}
+	return posted, nil
+}
+
+// RunRetry retries pending failed postings on a fixed interval. It blocks,
+// so run it in its own goroutine.
+func (s *LedgerService) RunRetry(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if count, err := s.RetryFailedPostings(false); err != nil {
+			log.Println("Failed to retry ledger postings:", err)
+		} else if count > 0 {
+			log.Printf("Posted %d queued ledger journals", count)
+		}
+	}
+}
+
+// newJournal builds a journal for a payment after checking that it balances
+func newJournal(payment *model.Payment, kind model.JournalKind, description string, lines []model.JournalLine) (*model.Journal, error) {
+	if len(lines) < 2 {
+		return nil, ErrInvalidJournal
+	}
+	for _, line := range lines {
+		if line.Account == "" || line.Amount == 0 {
+			return nil, ErrInvalidJournal
+		}
+	}
+	if sumLines(lines) != 0 {
+		return nil, ErrUnbalancedJournal
+	}
+
+	return &model.Journal{
+		PaymentID:   payment.PaymentID,
+		Kind:        kind,
+		Description: description,
+		Currency:    payment.Currency,
+		Lines:       lines,
+	}, nil
+}
+
+// fundingAccount is the account a payment's money comes from
+func fundingAccount(payment *model.Payment) model.LedgerAccount {
+	if payment.Instrument == model.InstrumentGiftCard {
+		return model.AccountGiftCardsRedeemed
+	}
+	return model.AccountCustomerReceivable
+}
+
+// sumLines adds up the signed amounts of journal lines
+func sumLines(lines []model.JournalLine) int {
+	sum := 0
+	for _, line := range lines {
+		sum += line.Amount
+	}
+	return sum
+}
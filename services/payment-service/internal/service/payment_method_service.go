@@ -0,0 +1,210 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/payment-service/internal/gateway"
+	"github.com/gocart-v2/payment-service/internal/repository"
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrInvalidPaymentMethod  = errors.New("invalid payment method data")
+	ErrPaymentMethodNotFound = errors.New("payment method not found")
+	ErrPaymentMethodExpired  = errors.New("payment method card has expired")
+)
+
+// brandNames are how card brands are shown to customers
+var brandNames = map[model.CardBrand]string{
+	model.CardVisa:       "Visa",
+	model.CardMastercard: "Mastercard",
+	model.CardAmex:       "American Express",
+	model.CardDiscover:   "Discover",
+}
+
+type PaymentMethodService struct {
+	repo   *repository.PaymentMethodRepository
+	tokens *TokenService
+
+	// defaults serializes changes that choose a customer's default method
+	defaults sync.Mutex
+}
+
+func NewPaymentMethodService(repo *repository.PaymentMethodRepository, tokens *TokenService) *PaymentMethodService {
+	return &PaymentMethodService{repo: repo, tokens: tokens}
+}
+
+// CreatePaymentMethod saves a tokenized card for a customer. It becomes the
+// default when asked to, or when the customer has no usable default yet.
+func (s *PaymentMethodService) CreatePaymentMethod(req *model.CreatePaymentMethodRequest) (*model.PaymentMethod, error) {
+	if req.CustomerID < 1 || req.CardToken == "" {
+		return nil, ErrInvalidPaymentMethod
+	}
+
+	card, err := s.tokens.GetCardToken(req.CardToken)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if gateway.CardExpired(card.ExpMonth, card.ExpYear, now) {
+		return nil, ErrCardExpired
+	}
+
+	s.defaults.Lock()
+	defer s.defaults.Unlock()
+
+	isDefault := req.MakeDefault
+	if !isDefault {
+		current, err := s.defaultMethod(req.CustomerID, now)
+		if err != nil {
+			return nil, err
+		}
+		isDefault = current == nil
+	}
+
+	method, err := s.repo.Create(&model.PaymentMethod{
+		CustomerID: req.CustomerID,
+		CardToken:  card.Token,
+		Brand:      card.Brand,
+		Last4:      card.Last4,
+		ExpMonth:   card.ExpMonth,
+		ExpYear:    card.ExpYear,
+		Display:    brandNames[card.Brand] + " ending in " + card.Last4,
+		IsDefault:  isDefault,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return withExpiry(method, now), nil
+}
+
+// GetPaymentMethod retrieves a payment method
+func (s *PaymentMethodService) GetPaymentMethod(paymentMethodID int) (*model.PaymentMethod, error) {
+	if paymentMethodID < 1 {
+		return nil, ErrInvalidPaymentMethod
+	}
+
+	method, err := s.repo.GetByID(paymentMethodID)
+	if err == repository.ErrPaymentMethodNotFound {
+		return nil, ErrPaymentMethodNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return withExpiry(method, time.Now().UTC()), nil
+}
+
+// ListPaymentMethods returns a customer's payment methods, newest first
+func (s *PaymentMethodService) ListPaymentMethods(customerID int) ([]*model.PaymentMethod, error) {
+	if customerID < 1 {
+		return nil, ErrInvalidPaymentMethod
+	}
+
+	methods, err := s.repo.ListByCustomer(customerID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	for _, method := range methods {
+		withExpiry(method, now)
+	}
+	return methods, nil
+}
+
+// SetDefaultPaymentMethod makes a payment method its customer's default.
+// Expired cards cannot be the default.
+func (s *PaymentMethodService) SetDefaultPaymentMethod(paymentMethodID int) (*model.PaymentMethod, error) {
+	method, err := s.GetPaymentMethod(paymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+	if method.Expired {
+		return nil, ErrPaymentMethodExpired
+	}
+
+	s.defaults.Lock()
+	defer s.defaults.Unlock()
+
+	method, err = s.repo.SetDefault(paymentMethodID)
+	if err == repository.ErrPaymentMethodNotFound {
+		return nil, ErrPaymentMethodNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return withExpiry(method, time.Now().UTC()), nil
+}
+
+// DeletePaymentMethod removes a payment method. When it was the default,
+// the customer's newest card that has not expired takes over.
+func (s *PaymentMethodService) DeletePaymentMethod(paymentMethodID int) error {
+	if paymentMethodID < 1 {
+		return ErrInvalidPaymentMethod
+	}
+
+	s.defaults.Lock()
+	defer s.defaults.Unlock()
+
+	deleted, err := s.repo.Delete(paymentMethodID)
+	if err == repository.ErrPaymentMethodNotFound {
+		return ErrPaymentMethodNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !deleted.IsDefault {
+		return nil
+	}
+
+	methods, err := s.repo.ListByCustomer(deleted.CustomerID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, method := range methods {
+		if !gateway.CardExpired(method.ExpMonth, method.ExpYear, now) {
+			_, err := s.repo.SetDefault(method.PaymentMethodID)
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve returns the card token of a customer's saved payment method. A
+// method belonging to another customer is reported as not found.
+func (s *PaymentMethodService) resolve(paymentMethodID, customerID int) (string, error) {
+	method, err := s.GetPaymentMethod(paymentMethodID)
+	if err != nil {
+		return "", err
+	}
+	if method.CustomerID != customerID {
+		return "", ErrPaymentMethodNotFound
+	}
+	if method.Expired {
+		return "", ErrPaymentMethodExpired
+	}
+	return method.CardToken, nil
+}
+
+// defaultMethod returns a customer's default method if it has not expired
+func (s *PaymentMethodService) defaultMethod(customerID int, now time.Time) (*model.PaymentMethod, error) {
+	methods, err := s.repo.ListByCustomer(customerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, method := range methods {
+		if method.IsDefault && !gateway.CardExpired(method.ExpMonth, method.ExpYear, now) {
+			return method, nil
+		}
+	}
+	return nil, nil
+}
+
+// withExpiry marks a payment method whose card has expired
+func withExpiry(method *model.PaymentMethod, now time.Time) *model.PaymentMethod {
+	method.Expired = gateway.CardExpired(method.ExpMonth, method.ExpYear, now)
+	return method
+}
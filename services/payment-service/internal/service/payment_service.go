@@ -0,0 +1,604 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/payment-service/internal/fraud"
+	"github.com/gocart-v2/payment-service/internal/gateway"
+	"github.com/gocart-v2/payment-service/internal/repository"
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/shared/webhook"
+)
+
+var (
+	ErrInvalidPayment    = errors.New("invalid payment data")
+	ErrPaymentNotFound   = errors.New("payment not found")
+	ErrInvalidTransition = errors.New("payment cannot make this change in its current status")
+	ErrAmountExceeded    = errors.New("amount exceeds what remains on the payment")
+	ErrPaymentChanged    = errors.New("payment was changed by another request")
+	ErrGatewayDeclined   = errors.New("gateway declined the operation")
+	ErrGatewayFailed     = errors.New("gateway could not complete the operation")
+)
+
+// DefaultCurrency is used when an authorization does not name a currency
+const DefaultCurrency = "USD"
+
+// transitions lists the statuses each payment status may move to. Failed,
+// voided and refunded payments are final.
+var transitions = map[model.PaymentStatus][]model.PaymentStatus{
+	model.PaymentPendingReview:     {model.PaymentAuthorized, model.PaymentFailed},
+	model.PaymentAuthorized:        {model.PaymentCaptured, model.PaymentVoided},
+	model.PaymentCaptured:          {model.PaymentPartiallyRefunded, model.PaymentRefunded},
+	model.PaymentPartiallyRefunded: {model.PaymentPartiallyRefunded, model.PaymentRefunded},
+}
+
+// CanTransition reports whether a payment may move from one status to another
+func CanTransition(from, to model.PaymentStatus) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+type PaymentService struct {
+	repo      *repository.PaymentRepository
+	tokens    *TokenService
+	methods   *PaymentMethodService
+	giftCards *GiftCardService
+	ledger    *LedgerService
+	fraud     *FraudService
+	gateway   gateway.Gateway
+	webhooks  *webhook.Dispatcher
+
+	// changing serializes captures, voids and refunds so that the gateway
+	// and the repository see them in the same order
+	changing sync.Mutex
+}
+
+func NewPaymentService(
+	repo *repository.PaymentRepository,
+	tokens *TokenService,
+	methods *PaymentMethodService,
+	giftCards *GiftCardService,
+	ledger *LedgerService,
+	fraud *FraudService,
+	gateway gateway.Gateway,
+	webhooks *webhook.Dispatcher,
+) *PaymentService {
+	return &PaymentService{
+		repo:      repo,
+		tokens:    tokens,
+		methods:   methods,
+		giftCards: giftCards,
+		ledger:    ledger,
+		fraud:     fraud,
+		gateway:   gateway,
+		webhooks:  webhooks,
+	}
+}
+
+// AuthorizePayment places a hold on a card for an order, given either as a
+// card token or as one of the customer's saved payment methods. The payment
+// is screened for fraud first: declined payments are recorded as failed
+// without reaching the gateway, and suspicious ones are held for review.
+// A hold the gateway refuses is recorded as a failed payment with the reason.
+// A gift card code holds the amount against the gift card's balance instead.
+func (s *PaymentService) AuthorizePayment(req *model.AuthorizePaymentRequest) (*model.Payment, error) {
+	instruments := 0
+	for _, given := range []bool{req.CardToken != "", req.PaymentMethodID != 0, req.GiftCardCode != ""} {
+		if given {
+			instruments++
+		}
+	}
+	if req.OrderRef == "" || req.Amount < 1 || instruments != 1 {
+		return nil, ErrInvalidPayment
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if req.GiftCardCode != "" {
+		return s.authorizeGiftCard(req, currency)
+	}
+
+	cardToken := req.CardToken
+	if req.PaymentMethodID != 0 {
+		if req.CustomerID < 1 {
+			return nil, ErrInvalidPayment
+		}
+		var err error
+		cardToken, err = s.methods.resolve(req.PaymentMethodID, req.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	card, number, err := s.tokens.detokenize(cardToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	payment := &model.Payment{
+		OrderRef:   req.OrderRef,
+		Amount:     req.Amount,
+		Currency:   currency,
+		Instrument: model.InstrumentCard,
+		Card: model.PaymentCard{
+			Brand:      card.Brand,
+			Last4:      card.Last4,
+			ExpMonth:   card.ExpMonth,
+			ExpYear:    card.ExpYear,
+			HolderName: card.HolderName,
+		},
+		Gateway: s.gateway.Name(),
+		Screening: s.fraud.Screen(fraud.Input{
+			CustomerID:      req.CustomerID,
+			CardToken:       cardToken,
+			Amount:          req.Amount,
+			Currency:        currency,
+			BillingCountry:  req.BillingCountry,
+			ShippingCountry: req.ShippingCountry,
+			IPCountry:       req.IPCountry,
+			At:              now,
+		}),
+		Refunds:   []model.Refund{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	switch payment.Screening.Decision {
+	case model.FraudDecline:
+		payment.Status = model.PaymentFailed
+		payment.FailureReason = "fraud_declined"
+	case model.FraudReview:
+		payment.Status = model.PaymentPendingReview
+		payment.CardToken = cardToken
+	default:
+		payment.Status = model.PaymentAuthorized
+		if err := s.authorize(payment, card, number); err != nil {
+			payment.Status = model.PaymentFailed
+			payment.FailureReason = failureReason(err)
+		}
+	}
+	payment.Events = []model.PaymentEvent{{To: payment.Status, Amount: req.Amount, OccurredAt: now}}
+
+	return s.repo.Create(payment)
+}
+
+// authorizeGiftCard holds a payment's amount against a gift card's
+// balance. Gift card payments skip fraud screening and the gateway; a
+// balance too low for the amount is recorded as a failed payment.
+func (s *PaymentService) authorizeGiftCard(req *model.AuthorizePaymentRequest, currency string) (*model.Payment, error) {
+	reference, err := newReference()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	payment := &model.Payment{
+		OrderRef:   req.OrderRef,
+		Amount:     req.Amount,
+		Currency:   currency,
+		Status:     model.PaymentAuthorized,
+		Instrument: model.InstrumentGiftCard,
+		Gateway:    GiftCardGateway,
+		GatewayRef: reference,
+		Refunds:    []model.Refund{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	card, err := s.giftCards.hold(req.GiftCardCode, payment)
+	switch err {
+	case nil:
+	case ErrInsufficientBalance:
+		payment.Status = model.PaymentFailed
+		payment.FailureReason = "insufficient_balance"
+	default:
+		return nil, err
+	}
+	payment.GiftCard = &model.PaymentGiftCard{
+		GiftCardID: card.GiftCardID,
+		Last4:      card.Code[len(card.Code)-4:],
+	}
+	payment.Events = []model.PaymentEvent{{To: payment.Status, Amount: req.Amount, OccurredAt: now}}
+
+	return s.repo.Create(payment)
+}
+
+// ListReviews returns the payments held by fraud screening, oldest first
+func (s *PaymentService) ListReviews() ([]*model.Payment, error) {
+	return s.repo.List("", model.PaymentPendingReview)
+}
+
+// ReleasePayment sends a payment held for review to the gateway. It is
+// authorized, or fails with the reason the gateway gave.
+func (s *PaymentService) ReleasePayment(paymentID int) (*model.Payment, error) {
+	s.changing.Lock()
+	defer s.changing.Unlock()
+
+	payment, err := s.GetPayment(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != model.PaymentPendingReview {
+		return nil, ErrInvalidTransition
+	}
+
+	card, number, err := s.tokens.detokenize(payment.CardToken)
+	if err != nil {
+		return nil, err
+	}
+
+	to := model.PaymentAuthorized
+	if err := s.authorize(payment, card, number); err != nil {
+		to = model.PaymentFailed
+		payment.FailureReason = failureReason(err)
+	}
+	payment.CardToken = ""
+	return s.transition(payment, to, payment.Amount)
+}
+
+// CancelPayment fails a payment held for review without contacting the gateway
+func (s *PaymentService) CancelPayment(paymentID int) (*model.Payment, error) {
+	s.changing.Lock()
+	defer s.changing.Unlock()
+
+	payment, err := s.GetPayment(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != model.PaymentPendingReview {
+		return nil, ErrInvalidTransition
+	}
+
+	payment.FailureReason = "review_canceled"
+	payment.CardToken = ""
+	return s.transition(payment, model.PaymentFailed, payment.Amount)
+}
+
+// GetPayment retrieves a payment
+func (s *PaymentService) GetPayment(paymentID int) (*model.Payment, error) {
+	if paymentID < 1 {
+		return nil, ErrInvalidPayment
+	}
+
+	payment, err := s.repo.GetByID(paymentID)
+	if err == repository.ErrPaymentNotFound {
+		return nil, ErrPaymentNotFound
+	}
+	return payment, err
+}
+
+// ListPayments returns payments, optionally for one order or status
+func (s *PaymentService) ListPayments(orderRef string, status model.PaymentStatus) ([]*model.Payment, error) {
+	switch status {
+	case "", model.PaymentPendingReview, model.PaymentAuthorized, model.PaymentCaptured,
+		model.PaymentPartiallyRefunded, model.PaymentRefunded, model.PaymentVoided, model.PaymentFailed:
+	default:
+		return nil, ErrInvalidPayment
+	}
+
+	return s.repo.List(orderRef, status)
+}
+
+// CapturePayment takes an authorized payment. A nil amount captures
+// everything authorized; a smaller amount releases the rest of the hold.
+func (s *PaymentService) CapturePayment(paymentID int, amount *int) (*model.Payment, error) {
+	s.changing.Lock()
+	defer s.changing.Unlock()
+
+	payment, err := s.GetPayment(paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	captured := payment.Amount
+	if amount != nil {
+		captured = *amount
+	}
+	if captured < 1 {
+		return nil, ErrInvalidPayment
+	}
+	if !CanTransition(payment.Status, model.PaymentCaptured) {
+		return nil, ErrInvalidTransition
+	}
+	if captured > payment.Amount {
+		return nil, ErrAmountExceeded
+	}
+	draft := *payment
+	draft.CapturedAmount = captured
+	journals, err := s.ledger.CaptureJournals(&draft)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.Instrument == model.InstrumentGiftCard {
+		if err := s.giftCards.release(payment, payment.Amount-captured); err != nil {
+			return nil, err
+		}
+	} else if err := s.call(payment.GatewayRef, func() (*gateway.Transaction, error) {
+		return s.gateway.Capture(payment.GatewayRef, captured)
+	}, func(transaction *gateway.Transaction) bool {
+		return transaction.Status == model.PaymentCaptured
+	}); err != nil {
+		return nil, gatewayError(err)
+	}
+
+	payment.CapturedAmount = captured
+	updated, err := s.transition(payment, model.PaymentCaptured, captured)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ledger.Post(journals); err != nil {
+		log.Printf("Failed to post capture of payment %d to the ledger, queued for retry: %v", updated.PaymentID, err)
+	}
+	if err := s.webhooks.Publish(model.EventPaymentCaptured, updated); err != nil {
+		log.Printf("Failed to publish capture of payment %d: %v", updated.PaymentID, err)
+	}
+	return updated, nil
+}
+
+// VoidPayment releases the hold of an authorized payment
+func (s *PaymentService) VoidPayment(paymentID int) (*model.Payment, error) {
+	s.changing.Lock()
+	defer s.changing.Unlock()
+
+	payment, err := s.GetPayment(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if !CanTransition(payment.Status, model.PaymentVoided) {
+		return nil, ErrInvalidTransition
+	}
+
+	if payment.Instrument == model.InstrumentGiftCard {
+		if err := s.giftCards.release(payment, payment.Amount); err != nil {
+			return nil, err
+		}
+	} else if err := s.call(payment.GatewayRef, func() (*gateway.Transaction, error) {
+		return s.gateway.Void(payment.GatewayRef)
+	}, func(transaction *gateway.Transaction) bool {
+		return transaction.Status == model.PaymentVoided
+	}); err != nil {
+		return nil, gatewayError(err)
+	}
+
+	return s.transition(payment, model.PaymentVoided, payment.Amount)
+}
+
+// RefundPayment returns part or all of a captured payment to the card or
+// gift card it was made with. Money withheld or charged back by disputes
+// cannot be refunded. The payment is refunded once nothing captured
+// remains.
+func (s *PaymentService) RefundPayment(paymentID int, req *model.RefundPaymentRequest) (*model.Payment, error) {
+	if req.Amount < 1 {
+		return nil, ErrInvalidPayment
+	}
+
+	s.changing.Lock()
+	defer s.changing.Unlock()
+
+	payment, err := s.GetPayment(paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	to := model.PaymentPartiallyRefunded
+	if payment.RefundedAmount+req.Amount == payment.CapturedAmount {
+		to = model.PaymentRefunded
+	}
+	if !CanTransition(payment.Status, to) {
+		return nil, ErrInvalidTransition
+	}
+	if req.Amount > payment.Refundable() {
+		return nil, ErrAmountExceeded
+	}
+	journals, err := s.ledger.RefundJournals(payment, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	refunded := payment.RefundedAmount + req.Amount
+	if payment.Instrument == model.InstrumentGiftCard {
+		if err := s.giftCards.refund(payment, req.Amount); err != nil {
+			return nil, err
+		}
+	} else if err := s.call(payment.GatewayRef, func() (*gateway.Transaction, error) {
+		return s.gateway.Refund(payment.GatewayRef, req.Amount)
+	}, func(transaction *gateway.Transaction) bool {
+		return transaction.Refunded == refunded
+	}); err != nil {
+		return nil, gatewayError(err)
+	}
+
+	payment.Refunds = append(payment.Refunds, model.Refund{
+		Amount:    req.Amount,
+		Reason:    req.Reason,
+		CreatedAt: time.Now().UTC(),
+	})
+	updated, err := s.transition(payment, to, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ledger.Post(journals); err != nil {
+		log.Printf("Failed to post refund of payment %d to the ledger, queued for retry: %v", updated.PaymentID, err)
+	}
+	if err := s.webhooks.Publish(model.EventPaymentRefunded, updated); err != nil {
+		log.Printf("Failed to publish refund of payment %d: %v", updated.PaymentID, err)
+	}
+	return updated, nil
+}
+
+// withholdDisputed sets aside part of a captured payment for a dispute, so
+// that it can be neither refunded nor disputed again
+func (s *PaymentService) withholdDisputed(paymentID, amount int) (*model.Payment, error) {
+	s.changing.Lock()
+	defer s.changing.Unlock()
+
+	payment, err := s.GetPayment(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if amount > payment.Refundable() {
+		return nil, ErrAmountExceeded
+	}
+
+	payment.DisputedAmount += amount
+	return s.update(payment)
+}
+
+// releaseDisputed returns the amount of a dispute that was won, or never
+// opened, to what can be refunded
+func (s *PaymentService) releaseDisputed(paymentID, amount int) (*model.Payment, error) {
+	s.changing.Lock()
+	defer s.changing.Unlock()
+
+	payment, err := s.GetPayment(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if amount > payment.DisputedAmount {
+		return nil, ErrAmountExceeded
+	}
+
+	payment.DisputedAmount -= amount
+	return s.update(payment)
+}
+
+// update stores a change to a payment read from the repository that
+// leaves its status as it is
+func (s *PaymentService) update(payment *model.Payment) (*model.Payment, error) {
+	payment.UpdatedAt = time.Now().UTC()
+
+	updated, err := s.repo.Update(payment, payment.Status, payment.RefundedAmount)
+	switch err {
+	case nil:
+		return updated, nil
+	case repository.ErrPaymentNotFound:
+		return nil, ErrPaymentNotFound
+	case repository.ErrPaymentConflict:
+		return nil, ErrPaymentChanged
+	default:
+		return nil, err
+	}
+}
+
+// transition moves a payment read from the repository to a new status,
+// recording the change in its history
+func (s *PaymentService) transition(payment *model.Payment, to model.PaymentStatus, amount int) (*model.Payment, error) {
+	from, refunded := payment.Status, payment.RefundedAmount
+	if to == model.PaymentPartiallyRefunded || to == model.PaymentRefunded {
+		payment.RefundedAmount += amount
+	}
+
+	now := time.Now().UTC()
+	payment.Status = to
+	payment.UpdatedAt = now
+	payment.Events = append(payment.Events, model.PaymentEvent{
+		From:       from,
+		To:         to,
+		Amount:     amount,
+		OccurredAt: now,
+	})
+
+	updated, err := s.repo.Update(payment, from, refunded)
+	switch err {
+	case nil:
+		return updated, nil
+	case repository.ErrPaymentNotFound:
+		return nil, ErrPaymentNotFound
+	case repository.ErrPaymentConflict:
+		return nil, ErrPaymentChanged
+	default:
+		return nil, err
+	}
+}
+
+// authorize asks the gateway to hold the payment's amount on the card,
+// under a new reference that is recorded on the payment
+func (s *PaymentService) authorize(payment *model.Payment, card *model.CardToken, number string) error {
+	reference, err := newReference()
+	if err != nil {
+		return err
+	}
+	payment.GatewayRef = reference
+
+	return s.call(reference, func() (*gateway.Transaction, error) {
+		return s.gateway.Authorize(&gateway.AuthorizeRequest{
+			Reference: reference,
+			Amount:    payment.Amount,
+			Currency:  payment.Currency,
+			Card: gateway.Card{
+				Number:     number,
+				ExpMonth:   card.ExpMonth,
+				ExpYear:    card.ExpYear,
+				HolderName: card.HolderName,
+			},
+		})
+	}, func(transaction *gateway.Transaction) bool {
+		return transaction.Status == model.PaymentAuthorized
+	})
+}
+
+// call runs a gateway operation. When the gateway times out, the outcome
+// is looked up by reference and done decides whether the operation went
+// through.
+func (s *PaymentService) call(reference string, operation func() (*gateway.Transaction, error), done func(*gateway.Transaction) bool) error {
+	_, err := operation()
+	if err != gateway.ErrTimeout {
+		return err
+	}
+
+	transaction, lookupErr := s.gateway.Status(reference)
+	if lookupErr != nil || !done(transaction) {
+		return gateway.ErrTimeout
+	}
+	return nil
+}
+
+// gatewayError maps a failed gateway operation to a service error
+func gatewayError(err error) error {
+	switch err {
+	case gateway.ErrDeclined, gateway.ErrInsufficientFunds, gateway.ErrExpiredCard, gateway.ErrUnknownTransaction:
+		return ErrGatewayDeclined
+	default:
+		return ErrGatewayFailed
+	}
+}
+
+// failureReason describes why the gateway refused an authorization
+func failureReason(err error) string {
+	switch err {
+	case gateway.ErrDeclined:
+		return "card_declined"
+	case gateway.ErrInsufficientFunds:
+		return "insufficient_funds"
+	case gateway.ErrExpiredCard:
+		return "card_expired"
+	case gateway.ErrTimeout:
+		return "gateway_timeout"
+	case gateway.ErrNetwork:
+		return "gateway_unavailable"
+	default:
+		return "gateway_error"
+	}
+}
+
+// newReference returns a random reference for a gateway transaction
+func newReference() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "pay_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gocart-v2/payment-service/internal/repository"
+	"github.com/gocart-v2/payment-service/internal/settlement"
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrInvalidSettlementFile    = errors.New("invalid settlement file")
+	ErrInvalidSettlementDate    = errors.New("date must be YYYY-MM-DD")
+	ErrSettlementImported       = errors.New("settlement file has already been imported")
+	ErrSettlementImportNotFound = errors.New("settlement import not found")
+)
+
+type SettlementService struct {
+	repo     *repository.SettlementRepository
+	payments *PaymentService
+}
+
+func NewSettlementService(repo *repository.SettlementRepository, payments *PaymentService) *SettlementService {
+	return &SettlementService{repo: repo, payments: payments}
+}
+
+// ImportFile reads a processor settlement file and stores its rows. A file
+// with any unreadable row is refused as a whole, as is a file that was
+// already imported.
+func (s *SettlementService) ImportFile(fileName string, data []byte) (*model.SettlementImport, error) {
+	rows, err := settlement.Parse(data, DefaultCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSettlementFile, err)
+	}
+
+	checksum := sha256.Sum256(data)
+	settlementImport := &model.SettlementImport{
+		FileName:   fileName,
+		Checksum:   hex.EncodeToString(checksum[:]),
+		RowCount:   len(rows),
+		ImportedAt: time.Now().UTC(),
+	}
+	totals := make(map[string]int)
+	currencies := []string{}
+	for _, row := range rows {
+		if _, seen := totals[row.Currency]; !seen {
+			currencies = append(currencies, row.Currency)
+		}
+		totals[row.Currency] += row.Amount
+	}
+	sort.Strings(currencies)
+	settlementImport.Totals = make([]model.Money, 0, len(currencies))
+	for _, code := range currencies {
+		settlementImport.Totals = append(settlementImport.Totals, model.Money{Amount: totals[code], Currency: code})
+	}
+
+	created, err := s.repo.CreateImport(settlementImport, rows)
+	if err == repository.ErrSettlementImportExists {
+		return nil, ErrSettlementImported
+	}
+	return created, err
+}
+
+// GetImport retrieves an import with its rows
+func (s *SettlementService) GetImport(importID int) (*model.SettlementImport, error) {
+	if importID < 1 {
+		return nil, ErrSettlementImportNotFound
+	}
+
+	settlementImport, err := s.repo.GetImport(importID)
+	if err == repository.ErrSettlementImportNotFound {
+		return nil, ErrSettlementImportNotFound
+	}
+	return settlementImport, err
+}
+
+// ListImports returns imports in the order they were made
+func (s *SettlementService) ListImports() ([]*model.SettlementImport, error) {
+	return s.repo.ListImports()
+}
+
+// Reconcile checks the payments captured on a date (UTC) against every
+// imported settlement row, matching by gateway reference and amount, and
+// adds the rows settled on that date that match no capture at all
+func (s *SettlementService) Reconcile(date string) (*model.ReconciliationReport, error) {
+	day, err := time.Parse(settlement.DateLayout, date)
+	if err != nil {
+		return nil, ErrInvalidSettlementDate
+	}
+	date = day.Format(settlement.DateLayout)
+
+	payments, err := s.payments.ListPayments("", "")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.ListRows()
+	if err != nil {
+		return nil, err
+	}
+
+	rowsByReference := make(map[string][]model.SettlementRow)
+	for _, row := range rows {
+		rowsByReference[row.Reference] = append(rowsByReference[row.Reference], row)
+	}
+
+	report := &model.ReconciliationReport{
+		Date:        date,
+		Items:       []model.ReconciliationItem{},
+		GeneratedAt: time.Now().UTC(),
+	}
+	totals := make(map[string]*model.ReconciliationTotals)
+	totalFor := func(currency string) *model.ReconciliationTotals {
+		total, exists := totals[currency]
+		if !exists {
+			total = &model.ReconciliationTotals{Currency: currency}
+			totals[currency] = total
+		}
+		return total
+	}
+	captured := make(map[string]bool)
+	for _, payment := range payments {
+		// Gift card payments never reach the processor, so are never settled
+		if payment.Instrument == model.InstrumentGiftCard {
+			continue
+		}
+		capturedAt, ok := capturedAt(payment)
+		if !ok {
+			continue
+		}
+		captured[payment.GatewayRef] = true
+		if capturedAt.UTC().Format(settlement.DateLayout) != date {
+			continue
+		}
+
+		item := reconcileCapture(payment, rowsByReference[payment.GatewayRef])
+		report.Captures++
+		totalFor(payment.Currency).CapturedTotal += item.CapturedAmount
+		for _, row := range rowsByReference[payment.GatewayRef] {
+			totalFor(row.Currency).SettledTotal += row.Amount
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	unmatched := make(map[string]*model.ReconciliationItem)
+	references := []string{}
+	for _, row := range rows {
+		if row.SettledOn != date || captured[row.Reference] {
+			continue
+		}
+		item, exists := unmatched[row.Reference]
+		if !exists {
+			item = &model.ReconciliationItem{
+				Status:    model.ReconciliationUnmatched,
+				Reference: row.Reference,
+				Currency:  row.Currency,
+				RowIDs:    []int{},
+				Detail:    "no captured payment has this reference",
+			}
+			unmatched[row.Reference] = item
+			references = append(references, row.Reference)
+		}
+		item.SettledAmount += row.Amount
+		item.RowIDs = append(item.RowIDs, row.RowID)
+		totalFor(row.Currency).SettledTotal += row.Amount
+	}
+	sort.Strings(references)
+	for _, reference := range references {
+		report.Items = append(report.Items, *unmatched[reference])
+	}
+
+	report.Totals = make([]model.ReconciliationTotals, 0, len(totals))
+	for _, total := range totals {
+		report.Totals = append(report.Totals, *total)
+	}
+	sort.Slice(report.Totals, func(i, j int) bool {
+		return report.Totals[i].Currency < report.Totals[j].Currency
+	})
+
+	for _, item := range report.Items {
+		switch item.Status {
+		case model.ReconciliationMatched:
+			report.Matched++
+		case model.ReconciliationMissing:
+			report.Missing++
+		case model.ReconciliationDuplicate:
+			report.Duplicates++
+		case model.ReconciliationAmountMismatch:
+			report.AmountMismatches++
+		case model.ReconciliationUnmatched:
+			report.Unmatched++
+		}
+	}
+	return report, nil
+}
+
+// reconcileCapture compares a captured payment with the settlement rows
+// for its reference
+func reconcileCapture(payment *model.Payment, rows []model.SettlementRow) model.ReconciliationItem {
+	item := model.ReconciliationItem{
+		PaymentID:      payment.PaymentID,
+		Reference:      payment.GatewayRef,
+		Currency:       payment.Currency,
+		CapturedAmount: payment.CapturedAmount,
+		RowIDs:         []int{},
+	}
+	for _, row := range rows {
+		item.SettledAmount += row.Amount
+		item.RowIDs = append(item.RowIDs, row.RowID)
+	}
+
+	switch {
+	case len(rows) == 0:
+		item.Status = model.ReconciliationMissing
+		item.Detail = "no settlement row has this reference"
+	case len(rows) > 1:
+		item.Status = model.ReconciliationDuplicate
+		item.Detail = fmt.Sprintf("settled %d times for %d in total", len(rows), item.SettledAmount)
+	case rows[0].Amount != payment.CapturedAmount || rows[0].Currency != payment.Currency:
+		item.Status = model.ReconciliationAmountMismatch
+		item.Detail = fmt.Sprintf("settled %d %s for a capture of %d %s", rows[0].Amount, rows[0].Currency, payment.CapturedAmount, payment.Currency)
+	default:
+		item.Status = model.ReconciliationMatched
+	}
+	return item
+}
+
+// capturedAt returns when a payment was captured, if it was
+func capturedAt(payment *model.Payment) (time.Time, bool) {
+	for _, event := range payment.Events {
+		if event.To == model.PaymentCaptured {
+			return event.OccurredAt, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gocart-v2/payment-service/internal/gateway"
+	"github.com/gocart-v2/payment-service/internal/repository"
+	"github.com/gocart-v2/payment-service/internal/vault"
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrInvalidCard       = errors.New("invalid card data")
+	ErrCardNumberInvalid = errors.New("card number failed the checksum")
+	ErrCardExpired       = errors.New("card has expired")
+	ErrUnsupportedBrand  = errors.New("card brand is not accepted")
+	ErrTokenNotFound     = errors.New("card token not found")
+)
+
+type TokenService struct {
+	repo   *repository.TokenRepository
+	cipher *vault.Cipher
+}
+
+func NewTokenService(repo *repository.TokenRepository, cipher *vault.Cipher) *TokenService {
+	return &TokenService{repo: repo, cipher: cipher}
+}
+
+// TokenizeCard validates a card and stores its number encrypted, returning
+// an opaque token to use in its place
+func (s *TokenService) TokenizeCard(card *model.CardDetails) (*model.CardToken, error) {
+	if len(card.Number) < 12 || card.ExpMonth < 1 || card.ExpMonth > 12 {
+		return nil, ErrInvalidCard
+	}
+	if !luhnValid(card.Number) {
+		return nil, ErrCardNumberInvalid
+	}
+	brand := DetectBrand(card.Number)
+	if brand == "" {
+		return nil, ErrUnsupportedBrand
+	}
+	now := time.Now().UTC()
+	if gateway.CardExpired(card.ExpMonth, card.ExpYear, now) {
+		return nil, ErrCardExpired
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.cipher.Seal([]byte(card.Number), token)
+	if err != nil {
+		return nil, err
+	}
+
+	vaulted := &repository.VaultedCard{
+		CardToken: model.CardToken{
+			Token:      token,
+			Brand:      brand,
+			Last4:      card.Number[len(card.Number)-4:],
+			ExpMonth:   card.ExpMonth,
+			ExpYear:    card.ExpYear,
+			HolderName: card.HolderName,
+			CreatedAt:  now,
+		},
+		SealedNumber: sealed,
+	}
+	if err := s.repo.Create(vaulted); err != nil {
+		return nil, err
+	}
+
+	return &vaulted.CardToken, nil
+}
+
+// GetCardToken retrieves what is known about a tokenized card without its number
+func (s *TokenService) GetCardToken(token string) (*model.CardToken, error) {
+	card, err := s.repo.GetByToken(token)
+	if err == repository.ErrTokenNotFound {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &card.CardToken, nil
+}
+
+// detokenize returns a tokenized card with its decrypted number. Only
+// payment-service may see the number, to hand it to the gateway.
+func (s *TokenService) detokenize(token string) (*model.CardToken, string, error) {
+	card, err := s.repo.GetByToken(token)
+	if err == repository.ErrTokenNotFound {
+		return nil, "", ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	number, err := s.cipher.Open(card.SealedNumber, token)
+	if err != nil {
+		return nil, "", err
+	}
+	return &card.CardToken, string(number), nil
+}
+
+// DetectBrand returns the card network a card number belongs to, or an
+// empty brand if it is not one that is accepted
+func DetectBrand(number string) model.CardBrand {
+	prefix := func(digits int) int {
+		if len(number) < digits {
+			return 0
+		}
+		n, _ := strconv.Atoi(number[:digits])
+		return n
+	}
+
+	switch length := len(number); {
+	case (prefix(2) == 34 || prefix(2) == 37) && length == 15:
+		return model.CardAmex
+	case prefix(1) == 4 && (length == 13 || length == 16 || length == 19):
+		return model.CardVisa
+	case ((prefix(2) >= 51 && prefix(2) <= 55) || (prefix(4) >= 2221 && prefix(4) <= 2720)) && length == 16:
+		return model.CardMastercard
+	case (prefix(4) == 6011 || prefix(2) == 65 || (prefix(3) >= 644 && prefix(3) <= 649)) && length >= 16 && length <= 19:
+		return model.CardDiscover
+	}
+	return ""
+}
+
+// luhnValid reports whether a card number passes the Luhn checksum
+func luhnValid(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		if number[i] < '0' || number[i] > '9' {
+			return false
+		}
+		digit := int(number[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// newToken returns a random card token
+func newToken() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "tok_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,150 @@
+package settlement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocart-v2/shared/currency"
+	"github.com/gocart-v2/shared/model"
+)
+
+// DateLayout is how settlement dates are written
+const DateLayout = "2006-01-02"
+
+// Columns a settlement file must have. Currency may also be given, and any
+// other columns are ignored.
+const (
+	ColumnReference = "reference"
+	ColumnAmount    = "amount"
+	ColumnCurrency  = "currency"
+	ColumnDate      = "settlement_date"
+)
+
+var (
+	ErrEmptyFile = errors.New("settlement file has no rows")
+)
+
+// LineError describes a line of a settlement file that cannot be read
+type LineError struct {
+	Line    int
+	Message string
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Parse reads a processor settlement file: a header row naming the
+// columns, then one row per settled capture. Amounts are in major units
+// with no more decimals than the currency has ("51.87" USD, "5187" JPY)
+// and dates are YYYY-MM-DD. Rows without a currency use defaultCurrency.
+func Parse(data []byte, defaultCurrency string) ([]model.SettlementRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrEmptyFile
+	}
+	if err != nil {
+		return nil, &LineError{Line: 1, Message: err.Error()}
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{ColumnReference, ColumnAmount, ColumnDate} {
+		if _, ok := columns[required]; !ok {
+			return nil, &LineError{Line: 1, Message: fmt.Sprintf("missing %s column", required)}
+		}
+	}
+
+	rows := []model.SettlementRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			return nil, &LineError{Line: line, Message: err.Error()}
+		}
+
+		field := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := model.SettlementRow{
+			Line:      line,
+			Reference: field(ColumnReference),
+			Currency:  strings.ToUpper(field(ColumnCurrency)),
+		}
+		if row.Reference == "" {
+			return nil, &LineError{Line: line, Message: "reference is empty"}
+		}
+		if row.Currency == "" {
+			row.Currency = defaultCurrency
+		}
+		minorUnits, err := currency.MinorUnits(row.Currency)
+		if err != nil {
+			return nil, &LineError{Line: line, Message: fmt.Sprintf("currency %q is not supported", row.Currency)}
+		}
+		row.Amount, err = parseAmount(field(ColumnAmount), minorUnits)
+		if err != nil || row.Amount < 1 {
+			return nil, &LineError{Line: line, Message: fmt.Sprintf("amount %q is not a positive amount", field(ColumnAmount))}
+		}
+		settledOn, err := time.Parse(DateLayout, field(ColumnDate))
+		if err != nil {
+			return nil, &LineError{Line: line, Message: fmt.Sprintf("settlement date %q is not YYYY-MM-DD", field(ColumnDate))}
+		}
+		row.SettledOn = settledOn.Format(DateLayout)
+
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrEmptyFile
+	}
+	return rows, nil
+}
+
+// parseAmount converts a decimal amount in major units to minor units,
+// of which there are 10^minorUnits to the major unit, without going
+// through floating point
+func parseAmount(value string, minorUnits int) (int, error) {
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" || len(fraction) > minorUnits || strings.HasPrefix(whole, "-") || strings.HasPrefix(whole, "+") {
+		return 0, strconv.ErrSyntax
+	}
+	for len(fraction) < minorUnits {
+		fraction += "0"
+	}
+
+	units, err := strconv.Atoi(whole)
+	if err != nil {
+		return 0, err
+	}
+	if minorUnits == 0 {
+		return units, nil
+	}
+	minor, err := strconv.Atoi(fraction)
+	if err != nil || strings.ContainsAny(fraction, "+-") {
+		return 0, strconv.ErrSyntax
+	}
+	for i := 0; i < minorUnits; i++ {
+		units *= 10
+	}
+	return units + minor, nil
+}
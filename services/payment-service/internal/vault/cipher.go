@@ -0,0 +1,80 @@
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the length of a vault key in bytes, selecting AES-256
+const KeySize = 32
+
+var (
+	ErrInvalidKey = errors.New("vault key must be 32 bytes, hex encoded")
+	ErrDecrypt    = errors.New("card data could not be decrypted")
+)
+
+// Cipher encrypts card data at rest with AES-GCM under a local key. It is
+// safe for concurrent use.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// ParseKey decodes a hex encoded key
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := hex.DecodeString(encoded)
+	if err != nil || len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// GenerateKey returns a random key
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate vault key: %w", err)
+	}
+	return key, nil
+}
+
+// Seal encrypts plaintext, binding it to context so that it cannot be
+// opened under another token. The nonce is stored in front of the ciphertext.
+func (c *Cipher) Seal(plaintext []byte, context string) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, []byte(context)), nil
+}
+
+// Open decrypts data sealed with the same context
+func (c *Cipher) Open(sealed []byte, context string) ([]byte, error) {
+	size := c.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := c.aead.Open(nil, sealed[:size], sealed[size:], []byte(context))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
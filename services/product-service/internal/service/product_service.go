@@ -4,7 +4,9 @@ import (
 	"errors"
 
 	"github.com/gocart-v2/product-service/internal/repository"
+	"github.com/gocart-v2/shared/currency"
 	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/shared/units"
 )
 
 var (
@@ -48,6 +50,14 @@ func (s *ProductService) AddProductDetails(productID int, product *model.Product
 		return errors.New("product ID mismatch")
 	}
 
+	// Legacy clients send weights in grams and no dimensions
+	if product.WeightUnit == "" {
+		product.WeightUnit = units.Gram
+	}
+	if product.DimensionUnit == "" {
+		product.DimensionUnit = units.Millimeter
+	}
+
 	// Validate product data
 	if err := s.validateProduct(product); err != nil {
 		return err
@@ -73,6 +83,33 @@ func (s *ProductService) validateProduct(product *model.Product) error {
 	if product.Weight < 0 {
 		return errors.New("weight cannot be negative")
 	}
+	if !product.WeightUnit.Valid() {
+		return errors.New("weight_unit must be one of g, kg, oz, lb")
+	}
+	if product.Length < 0 || product.Width < 0 || product.Height < 0 {
+		return errors.New("dimensions cannot be negative")
+	}
+	if !product.DimensionUnit.Valid() {
+		return errors.New("dimension_unit must be one of mm, cm, in")
+	}
+	if product.Price < 0 {
+		return errors.New("price cannot be negative")
+	}
+	if product.Currency != "" && !currency.Valid(product.Currency) {
+		return errors.New("currency is not a supported ISO 4217 code")
+	}
+	switch product.StockPolicy {
+	case "", model.StockPolicyDeny, model.StockPolicyBackorder:
+	case model.StockPolicyPreorder:
+		if product.ReleaseDate == nil {
+			return errors.New("release_date is required for pre-order products")
+		}
+	default:
+		return errors.New("stock_policy must be one of deny, backorder, preorder")
+	}
+	if product.LeadTimeDays < 0 || product.LeadTimeDays > 365 {
+		return errors.New("lead_time_days must be between 0 and 365")
+	}
 	if product.SomeOtherID < 1 {
 		return errors.New("some_other_id must be positive")
 	}
@@ -0,0 +1,141 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrUnknownRounding = errors.New("unknown rounding mode")
+	ErrInvalidRate     = errors.New("exchange rate must be a positive decimal")
+)
+
+// minorUnits is the number of decimal places in each supported currency's
+// minor unit, as given by ISO 4217
+var minorUnits = map[string]int{
+	"AUD": 2,
+	"BRL": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CNY": 2,
+	"DKK": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"HKD": 2,
+	"INR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"MXN": 2,
+	"NOK": 2,
+	"NZD": 2,
+	"SEK": 2,
+	"SGD": 2,
+	"USD": 2,
+}
+
+// Valid reports whether code is a supported ISO 4217 currency code
+func Valid(code string) bool {
+	_, ok := minorUnits[code]
+	return ok
+}
+
+// MinorUnits returns the number of decimal places in a currency's minor unit
+func MinorUnits(code string) (int, error) {
+	units, ok := minorUnits[code]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCurrency, code)
+	}
+	return units, nil
+}
+
+// ParseRate parses a decimal exchange rate such as "0.9213" exactly
+func ParseRate(rate string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRate, rate)
+	}
+	return r, nil
+}
+
+// Convert converts an amount in the minor units of one currency to the
+// minor units of another at rate, the units of to that one unit of from
+// buys. The exact result is rounded to a whole minor unit by mode.
+func Convert(amount int, from, to string, rate *big.Rat, mode model.RoundingMode) (int, error) {
+	fromUnits, err := MinorUnits(from)
+	if err != nil {
+		return 0, err
+	}
+	toUnits, err := MinorUnits(to)
+	if err != nil {
+		return 0, err
+	}
+
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(amount)), rate)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(toUnits-fromUnits))), nil))
+	if toUnits > fromUnits {
+		converted.Mul(converted, scale)
+	} else {
+		converted.Quo(converted, scale)
+	}
+	return Round(converted, mode)
+}
+
+// FromHundredths converts a fixed amount configured in hundredths of a
+// major unit, such as 30 for a fee of 0.30, to the minor units of a
+// currency, rounding half up: 30 is 30 USD cents, 0 yen or 300 fils.
+func FromHundredths(amount int, code string) (int, error) {
+	units, err := MinorUnits(code)
+	if err != nil {
+		return 0, err
+	}
+
+	scaled := new(big.Rat).SetFrac(
+		new(big.Int).Mul(big.NewInt(int64(amount)), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(units)), nil)),
+		big.NewInt(100),
+	)
+	return Round(scaled, model.RoundHalfUp)
+}
+
+// Round rounds an exact amount to a whole number by mode
+func Round(amount *big.Rat, mode model.RoundingMode) (int, error) {
+	quotient, remainder := new(big.Int).QuoRem(amount.Num(), amount.Denom(), new(big.Int))
+	if remainder.Sign() == 0 {
+		return int(quotient.Int64()), nil
+	}
+
+	// Compare twice the remainder with the denominator to find which side
+	// of the half way point the fraction falls on
+	half := new(big.Int).Abs(remainder)
+	half.Lsh(half, 1)
+	cmp := half.Cmp(amount.Denom())
+
+	awayFromZero := false
+	switch mode {
+	case model.RoundHalfUp:
+		awayFromZero = cmp >= 0
+	case model.RoundHalfEven:
+		awayFromZero = cmp > 0 || (cmp == 0 && quotient.Bit(0) == 1)
+	case model.RoundDown:
+	case model.RoundUp:
+		awayFromZero = true
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownRounding, mode)
+	}
+
+	if awayFromZero {
+		quotient.Add(quotient, big.NewInt(int64(amount.Sign())))
+	}
+	return int(quotient.Int64()), nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
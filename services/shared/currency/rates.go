@@ -0,0 +1,146 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrInvalidRateFile = errors.New("invalid exchange rate file")
+	ErrNoRate          = errors.New("no exchange rate between these currencies")
+)
+
+// RateTable holds exchange rates read from a JSON file in the form of
+// model.ExchangeRates, such as
+//
+//	{"base": "USD", "as_of": "2025-01-06T00:00:00Z", "rates": {"EUR": "0.9213", "JPY": "157.42"}}
+//
+// The base currency is the one the store settles in. Reload reads the file
+// again, keeping the current rates if it is unreadable or invalid. It is
+// safe for concurrent use.
+type RateTable struct {
+	path  string
+	mu    sync.RWMutex
+	rates model.ExchangeRates
+}
+
+// NewRateTable loads the rate table from a file
+func NewRateTable(path string) (*RateTable, error) {
+	t := &RateTable{path: path}
+	if err := t.Reload(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Reload reads the rate table file again and replaces the current rates
+func (t *RateTable) Reload() error {
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return err
+	}
+	rates, err := ParseRates(data)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rates = *rates
+	return nil
+}
+
+// Rates returns a copy of the current rates
+func (t *RateTable) Rates() model.ExchangeRates {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	rates := t.rates
+	rates.Rates = make(map[string]string, len(t.rates.Rates))
+	for code, rate := range t.rates.Rates {
+		rates.Rates[code] = rate
+	}
+	return rates
+}
+
+// Base returns the currency the rates are quoted against
+func (t *RateTable) Base() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.rates.Base
+}
+
+// Run reloads the rate table file every interval. It never returns.
+func (t *RateTable) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := t.Reload(); err != nil {
+			log.Println("Failed to reload exchange rates:", err)
+		}
+	}
+}
+
+// ParseRates parses and checks a rate table. The base currency is added
+// at a rate of 1 if the file leaves it out.
+func ParseRates(data []byte) (*model.ExchangeRates, error) {
+	var rates model.ExchangeRates
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRateFile, err)
+	}
+	if !Valid(rates.Base) {
+		return nil, fmt.Errorf("%w: unknown base currency %q", ErrInvalidRateFile, rates.Base)
+	}
+	if rates.Rates == nil {
+		rates.Rates = make(map[string]string)
+	}
+
+	for code, rate := range rates.Rates {
+		if !Valid(code) {
+			return nil, fmt.Errorf("%w: unknown currency %q", ErrInvalidRateFile, code)
+		}
+		if _, err := ParseRate(rate); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidRateFile, code, err)
+		}
+	}
+	if rate, ok := rates.Rates[rates.Base]; ok && rate != "1" {
+		if r, _ := ParseRate(rate); r.Cmp(big.NewRat(1, 1)) != 0 {
+			return nil, fmt.Errorf("%w: base currency rate must be 1", ErrInvalidRateFile)
+		}
+	}
+	rates.Rates[rates.Base] = "1"
+	return &rates, nil
+}
+
+// Rate returns how many units of to one unit of from buys, crossing
+// through the base currency when neither is the base
+func Rate(rates model.ExchangeRates, from, to string) (*big.Rat, error) {
+	fromRate, ok := rates.Rates[from]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s to %s", ErrNoRate, from, to)
+	}
+	toRate, ok := rates.Rates[to]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s to %s", ErrNoRate, from, to)
+	}
+
+	fromBase, err := ParseRate(fromRate)
+	if err != nil {
+		return nil, err
+	}
+	toBase, err := ParseRate(toRate)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).Quo(toBase, fromBase), nil
+}
@@ -0,0 +1,16 @@
+package model
+
+// Address represents a shipping destination
+// @name Address
+type Address struct {
+	Country    string  `json:"country" binding:"required,len=2" example:"US" dynamodbav:"country"`
+	Region     string  `json:"region,omitempty" binding:"max=3" example:"CA" dynamodbav:"region,omitempty"`
+	PostalCode string  `json:"postal_code,omitempty" binding:"max=20" example:"94105" dynamodbav:"postal_code,omitempty"`
+	Latitude   float64 `json:"latitude,omitempty" binding:"min=-90,max=90" example:"37.7897" dynamodbav:"latitude,omitempty"`
+	Longitude  float64 `json:"longitude,omitempty" binding:"min=-180,max=180" example:"-122.3972" dynamodbav:"longitude,omitempty"`
+}
+
+// HasCoordinates reports whether the address carries a geographic position
+func (a *Address) HasCoordinates() bool {
+	return a.Latitude != 0 || a.Longitude != 0
+}
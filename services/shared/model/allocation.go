@@ -0,0 +1,47 @@
+package model
+
+// AllocationStrategy selects how an order is split across warehouses
+type AllocationStrategy string
+
+const (
+	AllocateNearest         AllocationStrategy = "nearest"
+	AllocateFewestShipments AllocationStrategy = "fewest_shipments"
+	AllocateLowestCost      AllocationStrategy = "lowest_cost"
+)
+
+// Warehouse represents a fulfillment site
+// @name Warehouse
+type Warehouse struct {
+	WarehouseID  int     `json:"warehouse_id" example:"1" dynamodbav:"warehouse_id"`
+	Code         string  `json:"code" binding:"required,min=1,max=20" example:"SFO-1" dynamodbav:"code"`
+	Name         string  `json:"name" binding:"required,min=1,max=200" example:"San Francisco DC" dynamodbav:"name"`
+	Address      Address `json:"address" binding:"required" dynamodbav:"address"`
+	HandlingCost int     `json:"handling_cost" binding:"min=0" example:"250" dynamodbav:"handling_cost"`
+	Active       bool    `json:"active" example:"true" dynamodbav:"active"`
+}
+
+// AllocationRequest represents a request to plan fulfillment of cart items
+// @name AllocationRequest
+type AllocationRequest struct {
+	Strategy    AllocationStrategy `json:"strategy,omitempty" binding:"omitempty,oneof=nearest fewest_shipments lowest_cost" example:"nearest"`
+	Destination Address            `json:"destination" binding:"required"`
+	Items       []CartItem         `json:"items" binding:"required,min=1,dive"`
+}
+
+// AllocatedShipment represents the items one warehouse ships for an order
+// @name AllocatedShipment
+type AllocatedShipment struct {
+	WarehouseID   int        `json:"warehouse_id" example:"1" dynamodbav:"warehouse_id"`
+	DistanceKm    float64    `json:"distance_km,omitempty" example:"14.2" dynamodbav:"distance_km,omitempty"`
+	EstimatedCost int        `json:"estimated_cost" example:"712" dynamodbav:"estimated_cost"`
+	Items         []CartItem `json:"items" dynamodbav:"items"`
+}
+
+// Allocation represents how an order's items are split across warehouses
+// @name Allocation
+type Allocation struct {
+	Strategy      AllocationStrategy  `json:"strategy" example:"nearest"`
+	Split         bool                `json:"split" example:"false"`
+	EstimatedCost int                 `json:"estimated_cost" example:"712"`
+	Shipments     []AllocatedShipment `json:"shipments"`
+}
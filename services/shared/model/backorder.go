@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// StockPolicy decides what checkout does when a product is out of stock
+type StockPolicy string
+
+const (
+	StockPolicyDeny      StockPolicy = "deny"
+	StockPolicyBackorder StockPolicy = "backorder"
+	StockPolicyPreorder  StockPolicy = "preorder"
+)
+
+// DefaultBackorderLeadDays is the expected restock time for products that set none
+const DefaultBackorderLeadDays = 14
+
+// BackorderStatus is the lifecycle state of a backorder
+type BackorderStatus string
+
+const (
+	BackorderOpen               BackorderStatus = "open"
+	BackorderPartiallyAllocated BackorderStatus = "partially_allocated"
+	BackorderAllocated          BackorderStatus = "allocated"
+	BackorderCancelled          BackorderStatus = "cancelled"
+)
+
+// BackorderTerms lets a reservation line backorder whatever cannot be held
+// now. Pre-orders set the release date, before which nothing ships.
+// @name BackorderTerms
+type BackorderTerms struct {
+	ExpectedShipDate time.Time  `json:"expected_ship_date" binding:"required" example:"2025-03-01T00:00:00Z"`
+	ReleaseDate      *time.Time `json:"release_date,omitempty" example:"2025-03-01T00:00:00Z"`
+}
+
+// BackorderLine represents units of a product a reservation could not hold.
+// BackorderID is set once the reservation is confirmed.
+// @name BackorderLine
+type BackorderLine struct {
+	BackorderID      int        `json:"backorder_id,omitempty" example:"3" dynamodbav:"backorder_id,omitempty"`
+	ProductID        int        `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	Quantity         int        `json:"quantity" example:"2" dynamodbav:"quantity"`
+	ExpectedShipDate time.Time  `json:"expected_ship_date" dynamodbav:"expected_ship_date"`
+	ReleaseDate      *time.Time `json:"release_date,omitempty" dynamodbav:"release_date,omitempty"`
+}
+
+// Backorder represents units of a product owed to an order, allocated
+// oldest first as the warehouse receives stock
+// @name Backorder
+type Backorder struct {
+	BackorderID      int               `json:"backorder_id" example:"3" dynamodbav:"backorder_id"`
+	OrderRef         string            `json:"order_ref" example:"1000" dynamodbav:"order_ref"`
+	CustomerID       int               `json:"customer_id,omitempty" example:"1" dynamodbav:"customer_id,omitempty"`
+	ProductID        int               `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	Quantity         int               `json:"quantity" example:"2" dynamodbav:"quantity"`
+	Allocated        int               `json:"allocated" example:"0" dynamodbav:"allocated"`
+	Status           BackorderStatus   `json:"status" example:"open" dynamodbav:"status"`
+	Allocations      []ReservationLine `json:"allocations" dynamodbav:"allocations"`
+	ExpectedShipDate time.Time         `json:"expected_ship_date" dynamodbav:"expected_ship_date"`
+	ReleaseDate      *time.Time        `json:"release_date,omitempty" dynamodbav:"release_date,omitempty"`
+	CreatedAt        time.Time         `json:"created_at" dynamodbav:"created_at"`
+	AllocatedAt      *time.Time        `json:"allocated_at,omitempty" dynamodbav:"allocated_at,omitempty"`
+}
@@ -1,11 +1,18 @@
 package model
 
-// Cart represents a shopping cart
+// Cart represents a shopping cart. Currency is the currency the customer
+// sees and pays in, priced at the rates in RateLock; carts without one are
+// priced in the settlement currency.
 // @name Cart
 type Cart struct {
-	CartID     int        `json:"cart_id" dynamodbav:"cart_id"`
-	CustomerID int        `json:"customer_id" dynamodbav:"customer_id"`
-	Items      []CartItem `json:"items,omitempty" dynamodbav:"items,omitempty"`
+	CartID           int         `json:"cart_id" dynamodbav:"cart_id"`
+	CustomerID       int         `json:"customer_id" dynamodbav:"customer_id"`
+	Items            []CartItem  `json:"items,omitempty" dynamodbav:"items,omitempty"`
+	ShippingAddress  *Address    `json:"shipping_address,omitempty" dynamodbav:"shipping_address,omitempty"`
+	ShippingOptionID string      `json:"shipping_option_id,omitempty" dynamodbav:"shipping_option_id,omitempty"`
+	Currency         string      `json:"currency,omitempty" example:"EUR" dynamodbav:"currency,omitempty"`
+	RateLock         *RateLock   `json:"rate_lock,omitempty" dynamodbav:"rate_lock,omitempty"`
+	Totals           *CartTotals `json:"totals,omitempty" dynamodbav:"-"`
 }
 
 // CartItem represents an item in a shopping cart
@@ -31,11 +38,42 @@ type CreateCartResponse struct {
 // @name AddItemRequest
 type AddItemRequest struct {
 	ProductID int `json:"product_id" binding:"required,min=1" example:"1"`
-	Quantity  int `json:"quantity" binding:"required,min=1" example:"1"`
+	Quantity  int `json:"quantity" binding:"required,min=1,max=999" example:"1"`
 }
 
-// CheckoutResponse represents a response after checkout
+// CheckoutPayment represents one instrument paying part of a cart: a card
+// token, a saved payment method or a gift card code
+// @name CheckoutPayment
+type CheckoutPayment struct {
+	Amount          int    `json:"amount" binding:"required,min=1" example:"2500"`
+	CardToken       string `json:"card_token,omitempty" binding:"max=100" example:"tok_9c1d4e7a2b5f8c3e6a0d1b4f"`
+	PaymentMethodID int    `json:"payment_method_id,omitempty" binding:"omitempty,min=1" example:"1"`
+	GiftCardCode    string `json:"gift_card_code,omitempty" binding:"max=30" example:"GC7H-2KQD-9XWB-7QKM"`
+}
+
+// CheckoutRequest represents how to pay for a cart: either a single card,
+// given as a token from the payment service's card vault or one of the
+// customer's saved payment methods, or several payments that add up to the
+// total. The billing country is passed on to fraud screening.
+// @name CheckoutRequest
+type CheckoutRequest struct {
+	CardToken       string            `json:"card_token,omitempty" binding:"max=100" example:"tok_9c1d4e7a2b5f8c3e6a0d1b4f"`
+	PaymentMethodID int               `json:"payment_method_id,omitempty" binding:"omitempty,min=1" example:"1"`
+	Payments        []CheckoutPayment `json:"payments,omitempty" binding:"omitempty,max=5,dive"`
+	BillingCountry  string            `json:"billing_country,omitempty" binding:"omitempty,len=2,uppercase" example:"US"`
+}
+
+// CheckoutResponse represents a response after checkout. Amounts are in
+// Currency, the currency the customer paid in.
 // @name CheckoutResponse
 type CheckoutResponse struct {
-	OrderID int `json:"order_id" example:"0"`
+	OrderID    int              `json:"order_id" example:"0"`
+	PaymentID  int              `json:"payment_id" example:"1"`
+	Payments   []OrderPayment   `json:"payments"`
+	Currency   string           `json:"currency" example:"EUR"`
+	Subtotal   int              `json:"subtotal" example:"3998"`
+	Tax        int              `json:"tax" example:"290"`
+	Shipping   int              `json:"shipping" example:"899"`
+	Total      int              `json:"total" example:"5187"`
+	Settlement SettlementTotals `json:"settlement"`
 }
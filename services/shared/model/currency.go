@@ -0,0 +1,81 @@
+package model
+
+import "time"
+
+// RoundingMode decides how an amount converted between currencies is
+// rounded to the minor unit of the currency it is converted to
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds to the nearest minor unit, halves away from zero
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds to the nearest minor unit, halves to the even one
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundDown drops any fraction of a minor unit
+	RoundDown RoundingMode = "down"
+	// RoundUp charges any fraction of a minor unit as a whole one
+	RoundUp RoundingMode = "up"
+)
+
+// Valid reports whether m is a known rounding mode
+func (m RoundingMode) Valid() bool {
+	switch m {
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+		return true
+	default:
+		return false
+	}
+}
+
+// Money represents an amount in the minor units of a currency, such as
+// cents for USD or yen for JPY
+// @name Money
+type Money struct {
+	Amount   int    `json:"amount" example:"1999" dynamodbav:"amount"`
+	Currency string `json:"currency" example:"USD" dynamodbav:"currency"`
+}
+
+// ExchangeRates represents a rate table: how many units of each currency
+// one unit of the base currency buys. Rates are decimal strings so they
+// are never rounded by a float.
+// @name ExchangeRates
+type ExchangeRates struct {
+	Base  string            `json:"base" example:"USD" dynamodbav:"base"`
+	Rates map[string]string `json:"rates" dynamodbav:"rates"`
+	AsOf  time.Time         `json:"as_of" dynamodbav:"as_of"`
+}
+
+// RateLock represents the exchange rates a cart is priced at, fixed when
+// its currency was chosen so the amounts do not move under the customer.
+// Rate is what one unit of the settlement currency buys in the cart's
+// currency. Checkout is refused once the lock has expired.
+// @name RateLock
+type RateLock struct {
+	Currency           string        `json:"currency" example:"EUR" dynamodbav:"currency"`
+	SettlementCurrency string        `json:"settlement_currency" example:"USD" dynamodbav:"settlement_currency"`
+	Rate               string        `json:"rate" example:"0.9213" dynamodbav:"rate"`
+	Rounding           RoundingMode  `json:"rounding" example:"half_up" dynamodbav:"rounding"`
+	Rates              ExchangeRates `json:"-" dynamodbav:"rates"`
+	LockedAt           time.Time     `json:"locked_at" dynamodbav:"locked_at"`
+	ExpiresAt          time.Time     `json:"expires_at" dynamodbav:"expires_at"`
+}
+
+// SettlementTotals represents what a cart or order comes to in the
+// currency the store settles in, before conversion to the currency the
+// customer sees
+// @name SettlementTotals
+type SettlementTotals struct {
+	Currency string `json:"currency" example:"USD" dynamodbav:"currency"`
+	Subtotal int    `json:"subtotal" example:"3998" dynamodbav:"subtotal"`
+	Tax      int    `json:"tax" example:"290" dynamodbav:"tax"`
+	Shipping int    `json:"shipping" example:"899" dynamodbav:"shipping"`
+	Total    int    `json:"total" example:"5187" dynamodbav:"total"`
+}
+
+// SetCurrencyRequest represents a request to price a cart in a currency.
+// Leaving out the rounding rounds half up.
+// @name SetCurrencyRequest
+type SetCurrencyRequest struct {
+	Currency string       `json:"currency" binding:"required,len=3,uppercase" example:"EUR"`
+	Rounding RoundingMode `json:"rounding,omitempty" binding:"omitempty,oneof=half_up half_even down up" example:"half_up"`
+}
@@ -0,0 +1,79 @@
+package model
+
+import "time"
+
+// CycleCountStatus is the lifecycle state of a cycle count
+type CycleCountStatus string
+
+const (
+	CycleCountCounting        CycleCountStatus = "counting"
+	CycleCountPendingApproval CycleCountStatus = "pending_approval"
+	CycleCountPosted          CycleCountStatus = "posted"
+	CycleCountCancelled       CycleCountStatus = "cancelled"
+)
+
+// CycleCountLine represents the expected and counted stock of one lot of a
+// product, or of its untracked stock when LotNumber is empty. Serialized
+// products are counted by serial number.
+// @name CycleCountLine
+type CycleCountLine struct {
+	ProductID        int      `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	LotNumber        string   `json:"lot_number,omitempty" example:"L2024-118" dynamodbav:"lot_number,omitempty"`
+	Serialized       bool     `json:"serialized,omitempty" example:"false" dynamodbav:"serialized,omitempty"`
+	Expected         int      `json:"expected" example:"12" dynamodbav:"expected"`
+	Counted          *int     `json:"counted,omitempty" example:"11" dynamodbav:"counted,omitempty"`
+	Variance         int      `json:"variance" example:"-1" dynamodbav:"variance"`
+	RequiresApproval bool     `json:"requires_approval" example:"false" dynamodbav:"requires_approval"`
+	ExpectedSerials  []string `json:"expected_serials,omitempty" dynamodbav:"expected_serials,omitempty"`
+	CountedSerials   []string `json:"counted_serials,omitempty" dynamodbav:"counted_serials,omitempty"`
+	CountedBy        string   `json:"counted_by,omitempty" example:"jane.doe" dynamodbav:"counted_by,omitempty"`
+}
+
+// CycleCount represents a count of selected products in one warehouse
+// against a snapshot of the stock expected when the count started
+// @name CycleCount
+type CycleCount struct {
+	CycleCountID int              `json:"cycle_count_id" example:"1" dynamodbav:"cycle_count_id"`
+	WarehouseID  int              `json:"warehouse_id" example:"1" dynamodbav:"warehouse_id"`
+	Status       CycleCountStatus `json:"status" example:"counting" dynamodbav:"status"`
+	Lines        []CycleCountLine `json:"lines" dynamodbav:"lines"`
+	CreatedBy    string           `json:"created_by" example:"jane.doe" dynamodbav:"created_by"`
+	CreatedAt    time.Time        `json:"created_at" dynamodbav:"created_at"`
+	SubmittedBy  string           `json:"submitted_by,omitempty" example:"jane.doe" dynamodbav:"submitted_by,omitempty"`
+	SubmittedAt  *time.Time       `json:"submitted_at,omitempty" dynamodbav:"submitted_at,omitempty"`
+	ApprovedBy   string           `json:"approved_by,omitempty" example:"john.smith" dynamodbav:"approved_by,omitempty"`
+	ClosedAt     *time.Time       `json:"closed_at,omitempty" dynamodbav:"closed_at,omitempty"`
+}
+
+// CreateCycleCountRequest represents a request to start counting products in a warehouse
+// @name CreateCycleCountRequest
+type CreateCycleCountRequest struct {
+	WarehouseID int    `json:"warehouse_id" binding:"required,min=1" example:"1"`
+	ProductIDs  []int  `json:"product_ids" binding:"required,min=1,dive,min=1"`
+	Actor       string `json:"actor" binding:"required,min=1,max=100" example:"jane.doe"`
+}
+
+// CountLineRequest represents the counted stock of one lot of a product.
+// Serialized products list the serial number of every unit found instead
+// of a quantity.
+// @name CountLineRequest
+type CountLineRequest struct {
+	ProductID     int      `json:"product_id" binding:"required,min=1" example:"12345"`
+	LotNumber     string   `json:"lot_number,omitempty" binding:"max=100" example:"L2024-118"`
+	Counted       *int     `json:"counted,omitempty" binding:"omitempty,min=0" example:"11"`
+	SerialNumbers []string `json:"serial_numbers,omitempty" binding:"omitempty,dive,min=1,max=100"`
+}
+
+// RecordCountsRequest represents counted quantities for lines of a cycle count.
+// Recounting a line replaces its earlier count.
+// @name RecordCountsRequest
+type RecordCountsRequest struct {
+	Lines []CountLineRequest `json:"lines" binding:"required,min=1,dive"`
+	Actor string             `json:"actor" binding:"required,min=1,max=100" example:"jane.doe"`
+}
+
+// CycleCountActionRequest represents who is submitting, approving or cancelling a cycle count
+// @name CycleCountActionRequest
+type CycleCountActionRequest struct {
+	Actor string `json:"actor" binding:"required,min=1,max=100" example:"john.smith"`
+}
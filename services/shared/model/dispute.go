@@ -0,0 +1,86 @@
+package model
+
+import "time"
+
+// DisputeStatus is where a dispute is in its lifecycle
+type DisputeStatus string
+
+const (
+	DisputeNeedsResponse DisputeStatus = "needs_response"
+	DisputeUnderReview   DisputeStatus = "under_review"
+	DisputeWon           DisputeStatus = "won"
+	DisputeLost          DisputeStatus = "lost"
+)
+
+// DisputeReason is why the cardholder disputed a payment
+type DisputeReason string
+
+const (
+	DisputeFraudulent         DisputeReason = "fraudulent"
+	DisputeNotReceived        DisputeReason = "product_not_received"
+	DisputeNotAsDescribed     DisputeReason = "product_not_as_described"
+	DisputeDuplicate          DisputeReason = "duplicate"
+	DisputeCreditNotProcessed DisputeReason = "credit_not_processed"
+	DisputeGeneral            DisputeReason = "general"
+)
+
+// EvidenceKind is what a piece of dispute evidence shows
+type EvidenceKind string
+
+const (
+	EvidenceReceipt               EvidenceKind = "receipt"
+	EvidenceShippingDocumentation EvidenceKind = "shipping_documentation"
+	EvidenceCustomerCommunication EvidenceKind = "customer_communication"
+	EvidenceRefundPolicy          EvidenceKind = "refund_policy"
+	EvidenceOther                 EvidenceKind = "other"
+)
+
+// DisputeEvidence represents a file attached to a dispute. The content is
+// downloaded separately.
+// @name DisputeEvidence
+type DisputeEvidence struct {
+	EvidenceID  int          `json:"evidence_id" example:"1" dynamodbav:"evidence_id"`
+	Kind        EvidenceKind `json:"kind" example:"shipping_documentation" dynamodbav:"kind"`
+	FileName    string       `json:"file_name" example:"proof-of-delivery.pdf" dynamodbav:"file_name"`
+	ContentType string       `json:"content_type" example:"application/pdf" dynamodbav:"content_type"`
+	Size        int          `json:"size" example:"48213" dynamodbav:"size"`
+	UploadedAt  time.Time    `json:"uploaded_at" dynamodbav:"uploaded_at"`
+}
+
+// Dispute represents a cardholder disputing a captured payment with their
+// bank. The disputed amount is withheld while the dispute is open.
+// @name Dispute
+type Dispute struct {
+	DisputeID     int               `json:"dispute_id" example:"1" dynamodbav:"dispute_id"`
+	PaymentID     int               `json:"payment_id" example:"1" dynamodbav:"payment_id"`
+	Amount        int               `json:"amount" example:"5187" dynamodbav:"amount"`
+	Currency      string            `json:"currency" example:"USD" dynamodbav:"currency"`
+	Reason        DisputeReason     `json:"reason" example:"product_not_received" dynamodbav:"reason"`
+	ReasonCode    string            `json:"reason_code,omitempty" example:"13.1" dynamodbav:"reason_code,omitempty"`
+	Status        DisputeStatus     `json:"status" example:"needs_response" dynamodbav:"status"`
+	RespondBy     time.Time         `json:"respond_by" dynamodbav:"respond_by"`
+	Evidence      []DisputeEvidence `json:"evidence" dynamodbav:"evidence"`
+	RemindersSent int               `json:"reminders_sent" example:"0" dynamodbav:"reminders_sent"`
+	SubmittedAt   *time.Time        `json:"submitted_at,omitempty" dynamodbav:"submitted_at,omitempty"`
+	ClosedAt      *time.Time        `json:"closed_at,omitempty" dynamodbav:"closed_at,omitempty"`
+	CreatedAt     time.Time         `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// OpenDisputeRequest represents a dispute reported by the processor.
+// Leaving out the amount disputes everything not yet refunded, and leaving
+// out the deadline allows the default response window.
+// @name OpenDisputeRequest
+type OpenDisputeRequest struct {
+	PaymentID  int           `json:"payment_id" binding:"required,min=1" example:"1"`
+	Amount     int           `json:"amount,omitempty" binding:"omitempty,min=1" example:"5187"`
+	Reason     DisputeReason `json:"reason" binding:"required,oneof=fraudulent product_not_received product_not_as_described duplicate credit_not_processed general" example:"product_not_received"`
+	ReasonCode string        `json:"reason_code,omitempty" binding:"max=20" example:"13.1"`
+	RespondBy  *time.Time    `json:"respond_by,omitempty"`
+}
+
+// ResolveDisputeRequest represents the card network's ruling on a dispute
+// @name ResolveDisputeRequest
+type ResolveDisputeRequest struct {
+	Outcome DisputeStatus `json:"outcome" binding:"required,oneof=won lost" example:"won"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// FraudDecision is what fraud screening decided to do with a payment
+type FraudDecision string
+
+const (
+	FraudApprove FraudDecision = "approve"
+	FraudReview  FraudDecision = "review"
+	FraudDecline FraudDecision = "decline"
+)
+
+// DenyListKind is what a deny list entry matches
+type DenyListKind string
+
+const (
+	DenyCustomer  DenyListKind = "customer"
+	DenyCardToken DenyListKind = "card_token"
+	DenyCountry   DenyListKind = "country"
+)
+
+// FraudSignal represents a fraud rule that matched and the score it added
+// @name FraudSignal
+type FraudSignal struct {
+	Rule   string `json:"rule" example:"customer_velocity" dynamodbav:"rule"`
+	Score  int    `json:"score" example:"60" dynamodbav:"score"`
+	Reason string `json:"reason" example:"6 attempts by customer 1 in 10m0s" dynamodbav:"reason"`
+}
+
+// FraudScreening represents the outcome of screening a payment before
+// authorization. Score is the total of the matched signals.
+// @name FraudScreening
+type FraudScreening struct {
+	Score      int           `json:"score" example:"60" dynamodbav:"score"`
+	Decision   FraudDecision `json:"decision" example:"review" dynamodbav:"decision"`
+	Signals    []FraudSignal `json:"signals" dynamodbav:"signals"`
+	ScreenedAt time.Time     `json:"screened_at" dynamodbav:"screened_at"`
+}
+
+// DenyListEntry represents a customer, card token or country whose
+// payments are always declined
+// @name DenyListEntry
+type DenyListEntry struct {
+	EntryID   int          `json:"entry_id" example:"1" dynamodbav:"entry_id"`
+	Kind      DenyListKind `json:"kind" example:"country" dynamodbav:"kind"`
+	Value     string       `json:"value" example:"KP" dynamodbav:"value"`
+	Reason    string       `json:"reason,omitempty" example:"Sanctioned country" dynamodbav:"reason,omitempty"`
+	CreatedAt time.Time    `json:"created_at" dynamodbav:"created_at"`
+}
+
+// CreateDenyListEntryRequest represents a request to deny a customer, card token or country
+// @name CreateDenyListEntryRequest
+type CreateDenyListEntryRequest struct {
+	Kind   DenyListKind `json:"kind" binding:"required,oneof=customer card_token country" example:"country"`
+	Value  string       `json:"value" binding:"required,min=1,max=100" example:"KP"`
+	Reason string       `json:"reason,omitempty" binding:"max=200" example:"Sanctioned country"`
+}
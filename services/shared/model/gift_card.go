@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// GiftCardTransactionKind is what changed a gift card's balance
+type GiftCardTransactionKind string
+
+const (
+	GiftCardIssue   GiftCardTransactionKind = "issue"
+	GiftCardRedeem  GiftCardTransactionKind = "redeem"
+	GiftCardHold    GiftCardTransactionKind = "hold"
+	GiftCardRelease GiftCardTransactionKind = "release"
+	GiftCardRefund  GiftCardTransactionKind = "refund"
+)
+
+// GiftCardTransaction represents a change to a gift card's balance. Amount
+// is negative when it spends the balance. Holds, releases and refunds carry
+// the gateway reference of the payment they belong to.
+// @name GiftCardTransaction
+type GiftCardTransaction struct {
+	TransactionID int                     `json:"transaction_id" example:"1" dynamodbav:"transaction_id"`
+	Kind          GiftCardTransactionKind `json:"kind" example:"hold" dynamodbav:"kind"`
+	Amount        int                     `json:"amount" example:"-2500" dynamodbav:"amount"`
+	Balance       int                     `json:"balance" example:"2500" dynamodbav:"balance"`
+	Reference     string                  `json:"reference,omitempty" example:"pay_5f2b8c1e9a7d4e3f6a0b1c2d" dynamodbav:"reference,omitempty"`
+	CreatedAt     time.Time               `json:"created_at" dynamodbav:"created_at"`
+}
+
+// GiftCard represents a stored-value card. The code is what customers
+// present to spend it.
+// @name GiftCard
+type GiftCard struct {
+	GiftCardID     int                   `json:"gift_card_id" example:"1" dynamodbav:"gift_card_id"`
+	Code           string                `json:"code" example:"GC7H-2KQD-9XWB-7QKM" dynamodbav:"code"`
+	Currency       string                `json:"currency" example:"USD" dynamodbav:"currency"`
+	InitialBalance int                   `json:"initial_balance" example:"5000" dynamodbav:"initial_balance"`
+	Balance        int                   `json:"balance" example:"2500" dynamodbav:"balance"`
+	ExpiresAt      *time.Time            `json:"expires_at,omitempty" dynamodbav:"expires_at,omitempty"`
+	Transactions   []GiftCardTransaction `json:"transactions" dynamodbav:"transactions"`
+	CreatedAt      time.Time             `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// IssueGiftCardRequest represents a request to issue a gift card. Leaving
+// out the expiry issues a card that never expires.
+// @name IssueGiftCardRequest
+type IssueGiftCardRequest struct {
+	Amount    int        `json:"amount" binding:"required,min=1" example:"5000"`
+	Currency  string     `json:"currency,omitempty" binding:"omitempty,len=3,uppercase" example:"USD"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// RedeemGiftCardRequest represents spending a gift card outside of a
+// payment, such as at a till. The reference identifies the sale.
+// @name RedeemGiftCardRequest
+type RedeemGiftCardRequest struct {
+	Amount    int    `json:"amount" binding:"required,min=1" example:"1500"`
+	Reference string `json:"reference" binding:"required,max=100" example:"store-12-receipt-4821"`
+}
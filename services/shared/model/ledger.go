@@ -0,0 +1,116 @@
+package model
+
+import "time"
+
+// LedgerAccount names an account in the payment ledger
+type LedgerAccount string
+
+const (
+	// AccountCustomerReceivable is money captured from cards that the processor has not paid out yet
+	AccountCustomerReceivable LedgerAccount = "customer_receivable"
+	// AccountMerchantBalance is what the merchant has earned from payments, net of refunds
+	AccountMerchantBalance LedgerAccount = "merchant_balance"
+	// AccountProcessorFees is what the processor has charged for handling payments
+	AccountProcessorFees LedgerAccount = "processor_fees"
+	// AccountDisputedFunds is money the processor is withholding while a dispute is open
+	AccountDisputedFunds LedgerAccount = "disputed_funds"
+	// AccountGiftCardsRedeemed is gift card balance spent on payments, net of refunds
+	AccountGiftCardsRedeemed LedgerAccount = "gift_cards_redeemed"
+)
+
+// JournalKind is the business event a journal records
+type JournalKind string
+
+const (
+	JournalCapture JournalKind = "capture"
+	JournalFee     JournalKind = "fee"
+	JournalRefund  JournalKind = "refund"
+
+	JournalDisputeOpened JournalKind = "dispute_opened"
+	JournalDisputeWon    JournalKind = "dispute_won"
+	JournalDisputeLost   JournalKind = "dispute_lost"
+)
+
+// JournalLine represents one side of a journal. Debits are positive and
+// credits are negative, so the lines of a journal sum to zero.
+// @name JournalLine
+type JournalLine struct {
+	Account LedgerAccount `json:"account" example:"customer_receivable" dynamodbav:"account"`
+	Amount  int           `json:"amount" example:"5187" dynamodbav:"amount"`
+}
+
+// Journal represents an immutable double-entry ledger record of money
+// moving for a payment
+// @name Journal
+type Journal struct {
+	JournalID   int           `json:"journal_id" example:"1" dynamodbav:"journal_id"`
+	PaymentID   int           `json:"payment_id" example:"1" dynamodbav:"payment_id"`
+	Kind        JournalKind   `json:"kind" example:"capture" dynamodbav:"kind"`
+	Description string        `json:"description" example:"Captured payment 1" dynamodbav:"description"`
+	Currency    string        `json:"currency" example:"USD" dynamodbav:"currency"`
+	Lines       []JournalLine `json:"lines" dynamodbav:"lines"`
+	PostedAt    time.Time     `json:"posted_at" dynamodbav:"posted_at"`
+}
+
+// AccountBalance represents the totals posted to one ledger account in one
+// currency
+// @name AccountBalance
+type AccountBalance struct {
+	Account  LedgerAccount `json:"account" example:"customer_receivable"`
+	Currency string        `json:"currency" example:"USD"`
+	Debits   int           `json:"debits" example:"5187"`
+	Credits  int           `json:"credits" example:"180"`
+	Balance  int           `json:"balance" example:"5007"`
+}
+
+// CurrencyTotals represents the debits and credits posted in one currency.
+// Amounts in different currencies are never added together.
+// @name CurrencyTotals
+type CurrencyTotals struct {
+	Currency     string `json:"currency" example:"USD"`
+	TotalDebits  int    `json:"total_debits" example:"5367"`
+	TotalCredits int    `json:"total_credits" example:"5367"`
+	Balanced     bool   `json:"balanced" example:"true"`
+}
+
+// TrialBalance represents the balance of every ledger account in every
+// currency posted. The ledger is balanced when total debits equal total
+// credits in each currency.
+// @name TrialBalance
+type TrialBalance struct {
+	Accounts   []AccountBalance `json:"accounts"`
+	Currencies []CurrencyTotals `json:"currencies"`
+	Balanced   bool             `json:"balanced" example:"true"`
+	AsOf       time.Time        `json:"as_of"`
+}
+
+// FailedPostingStatus is whether a failed posting is still being retried
+type FailedPostingStatus string
+
+const (
+	PostingPending      FailedPostingStatus = "pending"
+	PostingDeadLettered FailedPostingStatus = "dead_lettered"
+)
+
+// FailedPosting represents a journal that could not be stored and is
+// waiting to be retried. It is removed once the journal is posted.
+// @name FailedPosting
+type FailedPosting struct {
+	PostingID     int                 `json:"posting_id" example:"1" dynamodbav:"posting_id"`
+	Journal       Journal             `json:"journal" dynamodbav:"journal"`
+	Status        FailedPostingStatus `json:"status" example:"pending" dynamodbav:"status"`
+	Attempts      int                 `json:"attempts" example:"2" dynamodbav:"attempts"`
+	LastError     string              `json:"last_error" example:"ledger store unavailable" dynamodbav:"last_error"`
+	FailedAt      time.Time           `json:"failed_at" dynamodbav:"failed_at"`
+	LastAttemptAt time.Time           `json:"last_attempt_at" dynamodbav:"last_attempt_at"`
+}
+
+// LedgerCheck represents the result of checking that every journal sums to
+// zero and that no journal is still waiting to be posted
+// @name LedgerCheck
+type LedgerCheck struct {
+	JournalsChecked    int   `json:"journals_checked" example:"42"`
+	UnbalancedJournals []int `json:"unbalanced_journals"`
+	FailedPostings     []int `json:"failed_postings"`
+	Balanced           bool  `json:"balanced" example:"true"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// LotQuantity represents units of a product from one lot
+// @name LotQuantity
+type LotQuantity struct {
+	LotNumber string    `json:"lot_number" example:"L2024-118" dynamodbav:"lot_number"`
+	ExpiresAt time.Time `json:"expires_at" dynamodbav:"expires_at"`
+	Quantity  int       `json:"quantity" example:"6" dynamodbav:"quantity"`
+}
+
+// LotLevel represents the stock of one lot of a product in a warehouse.
+// Stock in expired lots is never available.
+// @name LotLevel
+type LotLevel struct {
+	LotNumber string    `json:"lot_number" example:"L2024-118" dynamodbav:"lot_number"`
+	ExpiresAt time.Time `json:"expires_at" dynamodbav:"expires_at"`
+	OnHand    int       `json:"on_hand" example:"12" dynamodbav:"on_hand"`
+	Reserved  int       `json:"reserved" example:"2" dynamodbav:"reserved"`
+	Available int       `json:"available" example:"10" dynamodbav:"-"`
+	InTransit int       `json:"in_transit" example:"0" dynamodbav:"in_transit"`
+	Expired   bool      `json:"expired" example:"false" dynamodbav:"-"`
+}
+
+// ExpiringLot represents on-hand stock of a lot that expires soon or has expired
+// @name ExpiringLot
+type ExpiringLot struct {
+	ProductID       int       `json:"product_id" example:"12345"`
+	WarehouseID     int       `json:"warehouse_id" example:"1"`
+	LotNumber       string    `json:"lot_number" example:"L2024-118"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	OnHand          int       `json:"on_hand" example:"12"`
+	Reserved        int       `json:"reserved" example:"2"`
+	DaysUntilExpiry int       `json:"days_until_expiry" example:"5"`
+	Expired         bool      `json:"expired" example:"false"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// MovementType classifies a stock movement. Stock leaves a warehouse's
+// ledger when its reservation is confirmed; pick movements record zero
+// units, marking when and by whom that stock was pulled from the shelves.
+type MovementType string
+
+const (
+	MovementReceipt            MovementType = "receipt"
+	MovementPick               MovementType = "pick"
+	MovementAdjustment         MovementType = "adjustment"
+	MovementTransferOut        MovementType = "transfer_out"
+	MovementTransferIn         MovementType = "transfer_in"
+	MovementReservationConfirm MovementType = "reservation_confirm"
+	MovementTransferShortage   MovementType = "transfer_shortage"
+)
+
+// StockState is the bucket of a warehouse's stock that a movement changes
+type StockState string
+
+const (
+	StockOnHand    StockState = "on_hand"
+	StockInTransit StockState = "in_transit"
+)
+
+// StockMovement represents an immutable stock ledger entry. Quantity is
+// signed: positive entries add to the warehouse's stock in State and
+// negative entries remove from it. In-transit stock belongs to the
+// receiving warehouse.
+// @name StockMovement
+type StockMovement struct {
+	MovementID  int          `json:"movement_id" example:"1" dynamodbav:"movement_id"`
+	ProductID   int          `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	WarehouseID int          `json:"warehouse_id" example:"1" dynamodbav:"warehouse_id"`
+	Type        MovementType `json:"type" example:"adjustment" dynamodbav:"type"`
+	State       StockState   `json:"state" example:"on_hand" dynamodbav:"state"`
+	LotNumber   string       `json:"lot_number,omitempty" example:"L2024-118" dynamodbav:"lot_number,omitempty"`
+	Quantity    int          `json:"quantity" example:"-2" dynamodbav:"quantity"`
+	ReasonCode  string       `json:"reason_code" example:"DAMAGED" dynamodbav:"reason_code"`
+	Actor       string       `json:"actor" example:"jdoe" dynamodbav:"actor"`
+	Reference   string       `json:"reference,omitempty" example:"PO-1001" dynamodbav:"reference,omitempty"`
+	CreatedAt   time.Time    `json:"created_at" dynamodbav:"created_at"`
+}
+
+// StockReconciliation compares recorded stock with the sum of the ledger
+// @name StockReconciliation
+type StockReconciliation struct {
+	ProductID       int  `json:"product_id" example:"12345"`
+	WarehouseID     int  `json:"warehouse_id" example:"1"`
+	OnHand          int  `json:"on_hand" example:"42"`
+	LedgerOnHand    int  `json:"ledger_on_hand" example:"42"`
+	InTransit       int  `json:"in_transit" example:"5"`
+	LedgerInTransit int  `json:"ledger_in_transit" example:"5"`
+	Discrepancy     int  `json:"discrepancy" example:"0"`
+	Balanced        bool `json:"balanced" example:"true"`
+}
@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+// OrderLine represents a priced line on a cart or order. Backordered is how
+// many units are still waiting for stock, and ExpectedShipDate is set when
+// the line cannot ship straight away.
+// @name OrderLine
+type OrderLine struct {
+	ProductID        int        `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	Quantity         int        `json:"quantity" example:"2" dynamodbav:"quantity"`
+	UnitPrice        int        `json:"unit_price" example:"1999" dynamodbav:"unit_price"`
+	NetAmount        int        `json:"net_amount" example:"3998" dynamodbav:"net_amount"`
+	TaxRate          float64    `json:"tax_rate" example:"0.0725" dynamodbav:"tax_rate"`
+	TaxAmount        int        `json:"tax_amount" example:"290" dynamodbav:"tax_amount"`
+	GrossAmount      int        `json:"gross_amount" example:"4288" dynamodbav:"gross_amount"`
+	Backordered      int        `json:"backordered,omitempty" example:"1" dynamodbav:"backordered,omitempty"`
+	ExpectedShipDate *time.Time `json:"expected_ship_date,omitempty" dynamodbav:"expected_ship_date,omitempty"`
+}
+
+// CartTotals represents the priced contents of a cart. Lines, shipping and
+// totals are in Currency, the currency the customer sees; Settlement holds
+// the same totals in the currency the store settles in.
+// @name CartTotals
+type CartTotals struct {
+	Lines          []OrderLine       `json:"lines"`
+	ShippingOption *ShippingOption   `json:"shipping_option,omitempty"`
+	Currency       string            `json:"currency" example:"EUR"`
+	Subtotal       int               `json:"subtotal" example:"3998"`
+	Tax            int               `json:"tax" example:"290"`
+	Shipping       int               `json:"shipping" example:"899"`
+	Total          int               `json:"total" example:"5187"`
+	Settlement     *SettlementTotals `json:"settlement,omitempty"`
+}
+
+// Fulfillment represents the items of an order shipped from one warehouse,
+// with the serial numbers picked for serialized products
+// @name Fulfillment
+type Fulfillment struct {
+	WarehouseID   int        `json:"warehouse_id" example:"1" dynamodbav:"warehouse_id"`
+	Items         []CartItem `json:"items" dynamodbav:"items"`
+	SerialNumbers []string   `json:"serial_numbers,omitempty" dynamodbav:"serial_numbers,omitempty"`
+}
+
+// ShipmentProgress summarizes how far the shipments of an order have got
+type ShipmentProgress string
+
+const (
+	OrderUnshipped        ShipmentProgress = "unshipped"
+	OrderPartiallyShipped ShipmentProgress = "partially_shipped"
+	OrderShipped          ShipmentProgress = "shipped"
+	OrderDelivered        ShipmentProgress = "delivered"
+)
+
+// OrderPayment represents one of the payments an order was paid with
+// @name OrderPayment
+type OrderPayment struct {
+	PaymentID  int               `json:"payment_id" example:"1" dynamodbav:"payment_id"`
+	Instrument PaymentInstrument `json:"instrument" example:"card" dynamodbav:"instrument"`
+	Amount     int               `json:"amount" example:"5187" dynamodbav:"amount"`
+	Status     PaymentStatus     `json:"status" example:"authorized" dynamodbav:"status"`
+}
+
+// Order represents an order created by checking out a cart. Lines and
+// totals are in Currency, the presentment currency the customer paid in;
+// Settlement holds the totals in the currency the store settles in, and
+// ExchangeRate and Rounding record how one was converted to the other.
+// Payments lists every payment the total was split across; PaymentID and
+// PaymentStatus describe the first of them.
+// @name Order
+type Order struct {
+	OrderID         int              `json:"order_id" example:"1000" dynamodbav:"order_id"`
+	CartID          int              `json:"cart_id" example:"1" dynamodbav:"cart_id"`
+	CustomerID      int              `json:"customer_id" example:"1" dynamodbav:"customer_id"`
+	ReservationID   int              `json:"reservation_id" example:"1" dynamodbav:"reservation_id"`
+	Lines           []OrderLine      `json:"lines" dynamodbav:"lines"`
+	Fulfillments    []Fulfillment    `json:"fulfillments" dynamodbav:"fulfillments"`
+	ShippingAddress Address          `json:"shipping_address" dynamodbav:"shipping_address"`
+	ShippingOption  ShippingOption   `json:"shipping_option" dynamodbav:"shipping_option"`
+	Currency        string           `json:"currency" example:"EUR" dynamodbav:"currency"`
+	Subtotal        int              `json:"subtotal" example:"3998" dynamodbav:"subtotal"`
+	Tax             int              `json:"tax" example:"290" dynamodbav:"tax"`
+	Shipping        int              `json:"shipping" example:"899" dynamodbav:"shipping"`
+	Total           int              `json:"total" example:"5187" dynamodbav:"total"`
+	Settlement      SettlementTotals `json:"settlement" dynamodbav:"settlement"`
+	ExchangeRate    string           `json:"exchange_rate" example:"0.9213" dynamodbav:"exchange_rate"`
+	Rounding        RoundingMode     `json:"rounding" example:"half_up" dynamodbav:"rounding"`
+	PaymentID       int              `json:"payment_id" example:"1" dynamodbav:"payment_id"`
+	PaymentStatus   PaymentStatus    `json:"payment_status" example:"authorized" dynamodbav:"payment_status"`
+	Payments        []OrderPayment   `json:"payments" dynamodbav:"payments"`
+	ShipmentStatus  ShipmentProgress `json:"shipment_status" example:"unshipped" dynamodbav:"shipment_status"`
+	Shipments       []OrderShipment  `json:"shipments" dynamodbav:"shipments"`
+	CreatedAt       time.Time        `json:"created_at" dynamodbav:"created_at"`
+}
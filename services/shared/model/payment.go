@@ -0,0 +1,191 @@
+package model
+
+import "time"
+
+// PaymentStatus is the lifecycle state of a card payment
+type PaymentStatus string
+
+const (
+	PaymentPendingReview     PaymentStatus = "pending_review"
+	PaymentAuthorized        PaymentStatus = "authorized"
+	PaymentCaptured          PaymentStatus = "captured"
+	PaymentPartiallyRefunded PaymentStatus = "partially_refunded"
+	PaymentRefunded          PaymentStatus = "refunded"
+	PaymentVoided            PaymentStatus = "voided"
+	PaymentFailed            PaymentStatus = "failed"
+)
+
+// CardBrand is the card network detected from a card number
+type CardBrand string
+
+const (
+	CardVisa       CardBrand = "visa"
+	CardMastercard CardBrand = "mastercard"
+	CardAmex       CardBrand = "amex"
+	CardDiscover   CardBrand = "discover"
+)
+
+// PaymentInstrument is what a payment is made with
+type PaymentInstrument string
+
+const (
+	InstrumentCard     PaymentInstrument = "card"
+	InstrumentGiftCard PaymentInstrument = "gift_card"
+)
+
+// PaymentCard represents the card a payment was made with. Only the last
+// four digits of the card number are kept.
+// @name PaymentCard
+type PaymentCard struct {
+	Brand      CardBrand `json:"brand" example:"visa" dynamodbav:"brand"`
+	Last4      string    `json:"last4" example:"4242" dynamodbav:"last4"`
+	ExpMonth   int       `json:"exp_month" example:"12" dynamodbav:"exp_month"`
+	ExpYear    int       `json:"exp_year" example:"2030" dynamodbav:"exp_year"`
+	HolderName string    `json:"holder_name,omitempty" example:"Jane Doe" dynamodbav:"holder_name,omitempty"`
+}
+
+// PaymentGiftCard represents the gift card a payment was made with. Only
+// the last four characters of the code are shown.
+// @name PaymentGiftCard
+type PaymentGiftCard struct {
+	GiftCardID int    `json:"gift_card_id" example:"1" dynamodbav:"gift_card_id"`
+	Last4      string `json:"last4" example:"7QKM" dynamodbav:"last4"`
+}
+
+// Refund represents money returned to the card from a captured payment
+// @name Refund
+type Refund struct {
+	RefundID  int       `json:"refund_id" example:"1" dynamodbav:"refund_id"`
+	Amount    int       `json:"amount" example:"1999" dynamodbav:"amount"`
+	Reason    string    `json:"reason,omitempty" example:"Item returned" dynamodbav:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at" dynamodbav:"created_at"`
+}
+
+// PaymentEvent represents a change of a payment's status. Amount is the
+// amount authorized, captured or refunded by the change.
+// @name PaymentEvent
+type PaymentEvent struct {
+	From       PaymentStatus `json:"from,omitempty" example:"authorized" dynamodbav:"from,omitempty"`
+	To         PaymentStatus `json:"to" example:"captured" dynamodbav:"to"`
+	Amount     int           `json:"amount" example:"5187" dynamodbav:"amount"`
+	OccurredAt time.Time     `json:"occurred_at" dynamodbav:"occurred_at"`
+}
+
+// Payment represents a card or gift card payment for an order. Amount is
+// the amount authorized; a capture may take less, releasing the rest of the
+// hold. A card payment held by fraud screening waits in pending_review,
+// without a hold on the card, until it is released or canceled. Gift card
+// payments hold the amount against the card's balance and have no Card.
+// DisputedAmount is withheld by open disputes or charged back by lost ones
+// and can be neither refunded nor disputed again.
+// @name Payment
+type Payment struct {
+	PaymentID      int               `json:"payment_id" example:"1" dynamodbav:"payment_id"`
+	OrderRef       string            `json:"order_ref" example:"1000" dynamodbav:"order_ref"`
+	Amount         int               `json:"amount" example:"5187" dynamodbav:"amount"`
+	Currency       string            `json:"currency" example:"USD" dynamodbav:"currency"`
+	Status         PaymentStatus     `json:"status" example:"authorized" dynamodbav:"status"`
+	CapturedAmount int               `json:"captured_amount" example:"0" dynamodbav:"captured_amount"`
+	RefundedAmount int               `json:"refunded_amount" example:"0" dynamodbav:"refunded_amount"`
+	DisputedAmount int               `json:"disputed_amount" example:"0" dynamodbav:"disputed_amount"`
+	Instrument     PaymentInstrument `json:"instrument" example:"card" dynamodbav:"instrument"`
+	Card           PaymentCard       `json:"card" dynamodbav:"card"`
+	GiftCard       *PaymentGiftCard  `json:"gift_card,omitempty" dynamodbav:"gift_card,omitempty"`
+	Gateway        string            `json:"gateway" example:"FAKE" dynamodbav:"gateway"`
+	GatewayRef     string            `json:"gateway_ref" example:"pay_5f2b8c1e9a7d4e3f6a0b1c2d" dynamodbav:"gateway_ref"`
+	FailureReason  string            `json:"failure_reason,omitempty" example:"card_declined" dynamodbav:"failure_reason,omitempty"`
+	Screening      *FraudScreening   `json:"screening,omitempty" dynamodbav:"screening,omitempty"`
+	CardToken      string            `json:"-" dynamodbav:"card_token"`
+	Refunds        []Refund          `json:"refunds" dynamodbav:"refunds"`
+	Events         []PaymentEvent    `json:"events" dynamodbav:"events"`
+	CreatedAt      time.Time         `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// Refundable returns how much of a captured payment is neither refunded
+// nor disputed
+func (p *Payment) Refundable() int {
+	return p.CapturedAmount - p.RefundedAmount - p.DisputedAmount
+}
+
+// CardDetails represents a card presented for tokenization. The security
+// code is checked for format only and is never stored.
+// @name CardDetails
+type CardDetails struct {
+	Number     string `json:"number" binding:"required,numeric,min=12,max=19" example:"4242424242424242"`
+	ExpMonth   int    `json:"exp_month" binding:"required,min=1,max=12" example:"12"`
+	ExpYear    int    `json:"exp_year" binding:"required,min=2000,max=2100" example:"2030"`
+	CVC        string `json:"cvc" binding:"required,numeric,min=3,max=4" example:"123"`
+	HolderName string `json:"holder_name,omitempty" binding:"max=100" example:"Jane Doe"`
+}
+
+// CardToken represents a card stored encrypted in the vault. The token is
+// all other APIs accept in place of the card.
+// @name CardToken
+type CardToken struct {
+	Token      string    `json:"token" example:"tok_9c1d4e7a2b5f8c3e6a0d1b4f" dynamodbav:"token"`
+	Brand      CardBrand `json:"brand" example:"visa" dynamodbav:"brand"`
+	Last4      string    `json:"last4" example:"4242" dynamodbav:"last4"`
+	ExpMonth   int       `json:"exp_month" example:"12" dynamodbav:"exp_month"`
+	ExpYear    int       `json:"exp_year" example:"2030" dynamodbav:"exp_year"`
+	HolderName string    `json:"holder_name,omitempty" example:"Jane Doe" dynamodbav:"holder_name,omitempty"`
+	CreatedAt  time.Time `json:"created_at" dynamodbav:"created_at"`
+}
+
+// AuthorizePaymentRequest represents a request to place a hold on a card,
+// given as a card token or as one of the customer's saved payment methods,
+// or on a gift card's balance. The countries are only used for fraud
+// screening, which gift cards skip.
+// @name AuthorizePaymentRequest
+type AuthorizePaymentRequest struct {
+	OrderRef        string `json:"order_ref" binding:"required,min=1,max=100" example:"1000"`
+	CustomerID      int    `json:"customer_id,omitempty" binding:"omitempty,min=1" example:"1"`
+	Amount          int    `json:"amount" binding:"required,min=1" example:"5187"`
+	Currency        string `json:"currency,omitempty" binding:"omitempty,len=3,uppercase" example:"USD"`
+	CardToken       string `json:"card_token,omitempty" binding:"max=100" example:"tok_9c1d4e7a2b5f8c3e6a0d1b4f"`
+	PaymentMethodID int    `json:"payment_method_id,omitempty" binding:"omitempty,min=1" example:"1"`
+	GiftCardCode    string `json:"gift_card_code,omitempty" binding:"max=30" example:"GC7H-2KQD-9XWB-7QKM"`
+	BillingCountry  string `json:"billing_country,omitempty" binding:"omitempty,len=2,uppercase" example:"US"`
+	ShippingCountry string `json:"shipping_country,omitempty" binding:"omitempty,len=2,uppercase" example:"US"`
+	IPCountry       string `json:"ip_country,omitempty" binding:"omitempty,len=2,uppercase" example:"US"`
+}
+
+// CapturePaymentRequest represents a request to capture an authorized
+// payment. Leaving out the amount captures everything authorized.
+// @name CapturePaymentRequest
+type CapturePaymentRequest struct {
+	Amount *int `json:"amount,omitempty" binding:"omitempty,min=1" example:"5187"`
+}
+
+// RefundPaymentRequest represents a request to refund part or all of a captured payment
+// @name RefundPaymentRequest
+type RefundPaymentRequest struct {
+	Amount int    `json:"amount" binding:"required,min=1" example:"1999"`
+	Reason string `json:"reason,omitempty" binding:"max=200" example:"Item returned"`
+}
+
+// PaymentMethod represents a card a customer has saved for later checkouts.
+// Only the brand and last four digits are shown; Expired is worked out
+// when the method is read.
+// @name PaymentMethod
+type PaymentMethod struct {
+	PaymentMethodID int       `json:"payment_method_id" example:"1" dynamodbav:"payment_method_id"`
+	CustomerID      int       `json:"customer_id" example:"1" dynamodbav:"customer_id"`
+	CardToken       string    `json:"-" dynamodbav:"card_token"`
+	Brand           CardBrand `json:"brand" example:"visa" dynamodbav:"brand"`
+	Last4           string    `json:"last4" example:"4242" dynamodbav:"last4"`
+	ExpMonth        int       `json:"exp_month" example:"12" dynamodbav:"exp_month"`
+	ExpYear         int       `json:"exp_year" example:"2030" dynamodbav:"exp_year"`
+	Display         string    `json:"display" example:"Visa ending in 4242" dynamodbav:"display"`
+	IsDefault       bool      `json:"is_default" example:"true" dynamodbav:"is_default"`
+	Expired         bool      `json:"expired" example:"false" dynamodbav:"-"`
+	CreatedAt       time.Time `json:"created_at" dynamodbav:"created_at"`
+}
+
+// CreatePaymentMethodRequest represents a request to save a tokenized card for a customer
+// @name CreatePaymentMethodRequest
+type CreatePaymentMethodRequest struct {
+	CustomerID  int    `json:"customer_id" binding:"required,min=1" example:"1"`
+	CardToken   string `json:"card_token" binding:"required,max=100" example:"tok_9c1d4e7a2b5f8c3e6a0d1b4f"`
+	MakeDefault bool   `json:"make_default,omitempty" example:"true"`
+}
@@ -0,0 +1,116 @@
+package model
+
+import "time"
+
+// PickListStatus is the lifecycle state of a pick list
+type PickListStatus string
+
+const (
+	PickListOpen    PickListStatus = "open"
+	PickListPicked  PickListStatus = "picked"
+	PickListPacked  PickListStatus = "packed"
+	PickListShipped PickListStatus = "shipped"
+)
+
+// PickLine represents units of a product to pull from one lot, or from
+// untracked stock when LotNumber is empty
+// @name PickLine
+type PickLine struct {
+	ProductID     int      `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	LotNumber     string   `json:"lot_number,omitempty" example:"LOT-2025-001" dynamodbav:"lot_number,omitempty"`
+	Quantity      int      `json:"quantity" example:"2" dynamodbav:"quantity"`
+	SerialNumbers []string `json:"serial_numbers,omitempty" dynamodbav:"serial_numbers,omitempty"`
+}
+
+// PackedItem represents units of a product in a carton
+// @name PackedItem
+type PackedItem struct {
+	ProductID     int      `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	Quantity      int      `json:"quantity" example:"2" dynamodbav:"quantity"`
+	SerialNumbers []string `json:"serial_numbers,omitempty" dynamodbav:"serial_numbers,omitempty"`
+}
+
+// Carton represents a packed box on a pick list. Dimensions are in
+// millimeters and weight is in grams.
+// @name Carton
+type Carton struct {
+	CartonID int          `json:"carton_id" example:"1" dynamodbav:"carton_id"`
+	Box      string       `json:"box" example:"medium" dynamodbav:"box"`
+	Length   int          `json:"length" example:"350" dynamodbav:"length"`
+	Width    int          `json:"width" example:"250" dynamodbav:"width"`
+	Height   int          `json:"height" example:"200" dynamodbav:"height"`
+	Weight   int          `json:"weight" example:"2500" dynamodbav:"weight"`
+	Items    []PackedItem `json:"items" dynamodbav:"items"`
+	PackedBy string       `json:"packed_by" example:"jane.doe" dynamodbav:"packed_by"`
+	PackedAt time.Time    `json:"packed_at" dynamodbav:"packed_at"`
+}
+
+// PickList represents the units of one order to pick in one warehouse and
+// the cartons they were packed into
+// @name PickList
+type PickList struct {
+	PickListID  int            `json:"pick_list_id" example:"1" dynamodbav:"pick_list_id"`
+	OrderRef    string         `json:"order_ref" example:"1000" dynamodbav:"order_ref"`
+	WarehouseID int            `json:"warehouse_id" example:"1" dynamodbav:"warehouse_id"`
+	BatchID     int            `json:"batch_id,omitempty" example:"1" dynamodbav:"batch_id,omitempty"`
+	Status      PickListStatus `json:"status" example:"open" dynamodbav:"status"`
+	ShipTo      *Address       `json:"ship_to,omitempty" dynamodbav:"ship_to,omitempty"`
+	Lines       []PickLine     `json:"lines" dynamodbav:"lines"`
+	Cartons     []Carton       `json:"cartons" dynamodbav:"cartons"`
+	PickedBy    string         `json:"picked_by,omitempty" example:"jane.doe" dynamodbav:"picked_by,omitempty"`
+	CreatedAt   time.Time      `json:"created_at" dynamodbav:"created_at"`
+	PickedAt    *time.Time     `json:"picked_at,omitempty" dynamodbav:"picked_at,omitempty"`
+}
+
+// PickBatch represents open pick lists in one warehouse picked in a single
+// walk. Lines totals the units across every list by product and lot.
+// @name PickBatch
+type PickBatch struct {
+	BatchID     int        `json:"batch_id" example:"1" dynamodbav:"batch_id"`
+	WarehouseID int        `json:"warehouse_id" example:"1" dynamodbav:"warehouse_id"`
+	PickListIDs []int      `json:"pick_list_ids" dynamodbav:"pick_list_ids"`
+	Lines       []PickLine `json:"lines" dynamodbav:"lines"`
+	CreatedAt   time.Time  `json:"created_at" dynamodbav:"created_at"`
+}
+
+// CreatePickListsRequest represents a request to generate pick lists for
+// everything allocated to an order that is not on a pick list yet
+// @name CreatePickListsRequest
+type CreatePickListsRequest struct {
+	OrderRef string `json:"order_ref" binding:"required,min=1,max=100" example:"1000"`
+}
+
+// CreatePickBatchRequest represents a request to batch open pick lists in a
+// warehouse. Without pick list IDs every open, unbatched list is included.
+// @name CreatePickBatchRequest
+type CreatePickBatchRequest struct {
+	WarehouseID int   `json:"warehouse_id" binding:"required,min=1" example:"1"`
+	PickListIDs []int `json:"pick_list_ids,omitempty" binding:"omitempty,dive,min=1"`
+}
+
+// ConfirmPickRequest represents a picker confirming every line was pulled
+// @name ConfirmPickRequest
+type ConfirmPickRequest struct {
+	Actor string `json:"actor" binding:"required,min=1,max=100" example:"jane.doe"`
+}
+
+// PackCartonRequest represents a request to record a packed carton
+// @name PackCartonRequest
+type PackCartonRequest struct {
+	Box    string       `json:"box" binding:"required,min=1,max=50" example:"medium"`
+	Length int          `json:"length" binding:"required,min=1" example:"350"`
+	Width  int          `json:"width" binding:"required,min=1" example:"250"`
+	Height int          `json:"height" binding:"required,min=1" example:"200"`
+	Weight int          `json:"weight" binding:"required,min=1" example:"2500"`
+	Items  []CartonItem `json:"items" binding:"required,min=1,dive"`
+	Actor  string       `json:"actor" binding:"required,min=1,max=100" example:"jane.doe"`
+}
+
+// CartonItem represents units of a product placed in a carton. Serialized
+// products list the serial number of every unit.
+// @name CartonItem
+type CartonItem struct {
+	ProductID     int      `json:"product_id" binding:"required,min=1" example:"12345"`
+	Quantity      int      `json:"quantity" binding:"required,min=1" example:"2"`
+	SerialNumbers []string `json:"serial_numbers,omitempty" binding:"omitempty,dive,min=1,max=100"`
+}
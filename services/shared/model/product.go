@@ -1,12 +1,83 @@
 package model
 
-// Product represents a product
+import (
+	"time"
+
+	"github.com/gocart-v2/shared/units"
+)
+
+// Product represents a product. Price is in the minor units of Currency;
+// products without a currency are priced in the store's settlement currency.
 // @name Product
 type Product struct {
-	ProductID    int    `json:"product_id" binding:"required,min=1" example:"12345" dynamodbav:"product_id"`
-	SKU          string `json:"sku" binding:"required,min=1,max=100" example:"ABC-123-XYZ" dynamodbav:"sku"`
-	Manufacturer string `json:"manufacturer" binding:"required,min=1,max=200" example:"Acme Corporation" dynamodbav:"manufacturer"`
-	CategoryID   int    `json:"category_id" binding:"required,min=1" example:"456" dynamodbav:"category_id"`
-	Weight       int    `json:"weight" binding:"required,min=0" example:"1250" dynamodbav:"weight"`
-	SomeOtherID  int    `json:"some_other_id" binding:"required,min=1" example:"789" dynamodbav:"some_other_id"`
+	ProductID     int              `json:"product_id" binding:"required,min=1" example:"12345" dynamodbav:"product_id"`
+	SKU           string           `json:"sku" binding:"required,min=1,max=100" example:"ABC-123-XYZ" dynamodbav:"sku"`
+	Manufacturer  string           `json:"manufacturer" binding:"required,min=1,max=200" example:"Acme Corporation" dynamodbav:"manufacturer"`
+	CategoryID    int              `json:"category_id" binding:"required,min=1" example:"456" dynamodbav:"category_id"`
+	Weight        float64          `json:"weight" binding:"required,min=0" example:"1250" dynamodbav:"weight"`
+	WeightUnit    units.WeightUnit `json:"weight_unit,omitempty" binding:"omitempty,oneof=g kg oz lb" example:"g" dynamodbav:"weight_unit,omitempty"`
+	Length        float64          `json:"length,omitempty" binding:"min=0" example:"300" dynamodbav:"length,omitempty"`
+	Width         float64          `json:"width,omitempty" binding:"min=0" example:"200" dynamodbav:"width,omitempty"`
+	Height        float64          `json:"height,omitempty" binding:"min=0" example:"100" dynamodbav:"height,omitempty"`
+	DimensionUnit units.LengthUnit `json:"dimension_unit,omitempty" binding:"omitempty,oneof=mm cm in" example:"mm" dynamodbav:"dimension_unit,omitempty"`
+	Price         int              `json:"price" binding:"min=0" example:"1999" dynamodbav:"price"`
+	Currency      string           `json:"currency,omitempty" binding:"omitempty,len=3,uppercase" example:"USD" dynamodbav:"currency,omitempty"`
+	StockPolicy   StockPolicy      `json:"stock_policy,omitempty" binding:"omitempty,oneof=deny backorder preorder" example:"backorder" dynamodbav:"stock_policy,omitempty"`
+	ReleaseDate   *time.Time       `json:"release_date,omitempty" example:"2025-03-01T00:00:00Z" dynamodbav:"release_date,omitempty"`
+	LeadTimeDays  int              `json:"lead_time_days,omitempty" binding:"min=0,max=365" example:"14" dynamodbav:"lead_time_days,omitempty"`
+	SomeOtherID   int              `json:"some_other_id" binding:"required,min=1" example:"789" dynamodbav:"some_other_id"`
+}
+
+// WeightIn returns the product weight converted to unit.
+// Products without a weight unit are treated as weighed in grams.
+func (p *Product) WeightIn(unit units.WeightUnit) (float64, error) {
+	from := p.WeightUnit
+	if from == "" {
+		from = units.Gram
+	}
+	return units.ConvertWeight(p.Weight, from, unit)
+}
+
+// DimensionsIn returns the product length, width and height converted to unit.
+// Products without a dimension unit are treated as measured in millimeters.
+func (p *Product) DimensionsIn(unit units.LengthUnit) (length, width, height float64, err error) {
+	from := p.DimensionUnit
+	if from == "" {
+		from = units.Millimeter
+	}
+	if length, err = units.ConvertLength(p.Length, from, unit); err != nil {
+		return 0, 0, 0, err
+	}
+	if width, err = units.ConvertLength(p.Width, from, unit); err != nil {
+		return 0, 0, 0, err
+	}
+	if height, err = units.ConvertLength(p.Height, from, unit); err != nil {
+		return 0, 0, 0, err
+	}
+	return length, width, height, nil
+}
+
+// BackorderTerms returns how checkout may backorder the product at now, or
+// nil if out-of-stock units must be refused. Pre-orders ship from their
+// release date; backorders are expected after the restock lead time.
+func (p *Product) BackorderTerms(now time.Time) *BackorderTerms {
+	leadDays := p.LeadTimeDays
+	if leadDays == 0 {
+		leadDays = DefaultBackorderLeadDays
+	}
+	restocked := now.AddDate(0, 0, leadDays)
+
+	switch p.StockPolicy {
+	case StockPolicyBackorder:
+		return &BackorderTerms{ExpectedShipDate: restocked}
+	case StockPolicyPreorder:
+		if p.ReleaseDate == nil || !p.ReleaseDate.After(now) {
+			// Released pre-orders restock like backorders
+			return &BackorderTerms{ExpectedShipDate: restocked}
+		}
+		release := *p.ReleaseDate
+		return &BackorderTerms{ExpectedShipDate: release, ReleaseDate: &release}
+	default:
+		return nil
+	}
 }
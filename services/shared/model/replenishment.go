@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// ReorderPolicy holds the replenishment thresholds for a product across all warehouses
+// @name ReorderPolicy
+type ReorderPolicy struct {
+	ProductID       int       `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	ReorderPoint    int       `json:"reorder_point" example:"20" dynamodbav:"reorder_point"`
+	ReorderQuantity int       `json:"reorder_quantity" example:"50" dynamodbav:"reorder_quantity"`
+	LeadTimeDays    int       `json:"lead_time_days" example:"7" dynamodbav:"lead_time_days"`
+	UpdatedAt       time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// SetReorderPolicyRequest represents a request to set a product's reorder thresholds
+// @name SetReorderPolicyRequest
+type SetReorderPolicyRequest struct {
+	ReorderPoint    int `json:"reorder_point" binding:"min=0" example:"20"`
+	ReorderQuantity int `json:"reorder_quantity" binding:"required,min=1" example:"50"`
+	LeadTimeDays    int `json:"lead_time_days" binding:"min=0,max=365" example:"7"`
+}
+
+// ReplenishmentSuggestion compares a product's stock with its reorder policy.
+// ProjectedAvailable is available plus in-transit stock less the sales
+// expected over the lead time. DaysOfCover is omitted when the product has
+// no recent sales.
+// @name ReplenishmentSuggestion
+type ReplenishmentSuggestion struct {
+	ProductID          int      `json:"product_id" example:"12345"`
+	Available          int      `json:"available" example:"40"`
+	InTransit          int      `json:"in_transit" example:"0"`
+	DailyVelocity      float64  `json:"daily_velocity" example:"3.5"`
+	ProjectedAvailable int      `json:"projected_available" example:"15"`
+	DaysOfCover        *float64 `json:"days_of_cover,omitempty" example:"11.4"`
+	ReorderPoint       int      `json:"reorder_point" example:"20"`
+	ReorderQuantity    int      `json:"reorder_quantity" example:"50"`
+	ReorderNeeded      bool     `json:"reorder_needed" example:"true"`
+	SuggestedQuantity  int      `json:"suggested_quantity" example:"50"`
+}
+
+// ReplenishmentReport lists replenishment suggestions for every product with a reorder policy
+// @name ReplenishmentReport
+type ReplenishmentReport struct {
+	GeneratedAt        time.Time                 `json:"generated_at"`
+	VelocityWindowDays int                       `json:"velocity_window_days" example:"30"`
+	Suggestions        []ReplenishmentSuggestion `json:"suggestions"`
+}
+
+// LowStockAlert is published when a product's projected stock falls to or below its reorder point
+// @name LowStockAlert
+type LowStockAlert struct {
+	ReplenishmentSuggestion
+	RaisedAt time.Time `json:"raised_at"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// ReservationStatus is the lifecycle state of an inventory reservation
+type ReservationStatus string
+
+const (
+	ReservationActive    ReservationStatus = "active"
+	ReservationConfirmed ReservationStatus = "confirmed"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+// ReservationLine represents units of a product held in one warehouse.
+// Lots lists the lot-tracked units held, first-expired first; any remainder
+// is untracked stock. SerialNumbers lists the units picked for serialized
+// products once the reservation is confirmed.
+// @name ReservationLine
+type ReservationLine struct {
+	ProductID     int           `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	WarehouseID   int           `json:"warehouse_id" example:"1" dynamodbav:"warehouse_id"`
+	Quantity      int           `json:"quantity" example:"2" dynamodbav:"quantity"`
+	Lots          []LotQuantity `json:"lots,omitempty" dynamodbav:"lots,omitempty"`
+	SerialNumbers []string      `json:"serial_numbers,omitempty" dynamodbav:"serial_numbers,omitempty"`
+}
+
+// Reservation represents an all-or-nothing hold on stock for an order.
+// Backorders lists what could not be held and will be owed to the order
+// once the reservation is confirmed. Destination is kept for shipping.
+// @name Reservation
+type Reservation struct {
+	ReservationID int               `json:"reservation_id" example:"1" dynamodbav:"reservation_id"`
+	OrderRef      string            `json:"order_ref" example:"1000" dynamodbav:"order_ref"`
+	CustomerID    int               `json:"customer_id,omitempty" example:"1" dynamodbav:"customer_id,omitempty"`
+	Status        ReservationStatus `json:"status" example:"active" dynamodbav:"status"`
+	Lines         []ReservationLine `json:"lines" dynamodbav:"lines"`
+	Backorders    []BackorderLine   `json:"backorders,omitempty" dynamodbav:"backorders,omitempty"`
+	Destination   *Address          `json:"destination,omitempty" dynamodbav:"destination,omitempty"`
+	CreatedAt     time.Time         `json:"created_at" dynamodbav:"created_at"`
+	ExpiresAt     time.Time         `json:"expires_at" dynamodbav:"expires_at"`
+}
+
+// ReserveLineRequest represents units of a product to hold, optionally in a
+// specific warehouse. Lines with backorder terms backorder any shortfall
+// instead of failing the reservation.
+// @name ReserveLineRequest
+type ReserveLineRequest struct {
+	ProductID   int             `json:"product_id" binding:"required,min=1" example:"12345"`
+	WarehouseID int             `json:"warehouse_id,omitempty" binding:"min=0" example:"1"`
+	Quantity    int             `json:"quantity" binding:"required,min=1" example:"2"`
+	Backorder   *BackorderTerms `json:"backorder,omitempty"`
+}
+
+// ReserveRequest represents a request to hold stock for every line of an order.
+// When a destination is given, lines without a warehouse are allocated
+// using the requested strategy.
+// @name ReserveRequest
+type ReserveRequest struct {
+	OrderRef    string               `json:"order_ref" binding:"required,min=1,max=100" example:"1000"`
+	CustomerID  int                  `json:"customer_id,omitempty" binding:"min=0" example:"1"`
+	TTLSeconds  int                  `json:"ttl_seconds,omitempty" binding:"min=0,max=86400" example:"900"`
+	Destination *Address             `json:"destination,omitempty"`
+	Strategy    AllocationStrategy   `json:"strategy,omitempty" binding:"omitempty,oneof=nearest fewest_shipments lowest_cost" example:"nearest"`
+	Lines       []ReserveLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// SerialStatus is where a serialized unit is in its lifecycle
+type SerialStatus string
+
+const (
+	SerialInStock   SerialStatus = "in_stock"
+	SerialInTransit SerialStatus = "in_transit"
+	SerialSold      SerialStatus = "sold"
+	SerialRemoved   SerialStatus = "removed"
+)
+
+// SerialNumber represents one serialized unit of a product. Once sold it
+// records the order and customer it went to.
+// @name SerialNumber
+type SerialNumber struct {
+	SerialNumber  string       `json:"serial_number" example:"SN-4F2A-0091" dynamodbav:"serial_number"`
+	ProductID     int          `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	WarehouseID   int          `json:"warehouse_id" example:"1" dynamodbav:"warehouse_id"`
+	Status        SerialStatus `json:"status" example:"in_stock" dynamodbav:"status"`
+	ReservationID int          `json:"reservation_id,omitempty" example:"7" dynamodbav:"reservation_id,omitempty"`
+	OrderRef      string       `json:"order_ref,omitempty" example:"1000" dynamodbav:"order_ref,omitempty"`
+	CustomerID    int          `json:"customer_id,omitempty" example:"1" dynamodbav:"customer_id,omitempty"`
+	ReceivedAt    time.Time    `json:"received_at" dynamodbav:"received_at"`
+	SoldAt        *time.Time   `json:"sold_at,omitempty" dynamodbav:"sold_at,omitempty"`
+}
+
+// SerializationSetting records whether a product's units are tracked by serial number
+// @name SerializationSetting
+type SerializationSetting struct {
+	ProductID  int       `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	Serialized bool      `json:"serialized" example:"true" dynamodbav:"serialized"`
+	UpdatedAt  time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// SetSerializationRequest represents a request to turn serial tracking on or off for a product
+// @name SetSerializationRequest
+type SetSerializationRequest struct {
+	Serialized *bool `json:"serialized" binding:"required" example:"true"`
+}
@@ -0,0 +1,86 @@
+package model
+
+import "time"
+
+// ReconciliationStatus is how a captured payment or settlement row
+// compares with the other side
+type ReconciliationStatus string
+
+const (
+	// ReconciliationMatched is a capture settled once for the captured amount
+	ReconciliationMatched ReconciliationStatus = "matched"
+	// ReconciliationMissing is a capture the processor has not settled
+	ReconciliationMissing ReconciliationStatus = "missing"
+	// ReconciliationDuplicate is a capture the processor settled more than once
+	ReconciliationDuplicate ReconciliationStatus = "duplicate"
+	// ReconciliationAmountMismatch is a capture settled for a different amount or currency
+	ReconciliationAmountMismatch ReconciliationStatus = "amount_mismatch"
+	// ReconciliationUnmatched is a settlement row for no known capture
+	ReconciliationUnmatched ReconciliationStatus = "unmatched"
+)
+
+// SettlementRow represents one line of a processor settlement file
+// @name SettlementRow
+type SettlementRow struct {
+	RowID     int    `json:"row_id" example:"1" dynamodbav:"row_id"`
+	ImportID  int    `json:"import_id" example:"1" dynamodbav:"import_id"`
+	Line      int    `json:"line" example:"2" dynamodbav:"line"`
+	Reference string `json:"reference" example:"pay_5f2b8c1e9a7d4e3f6a0b1c2d" dynamodbav:"reference"`
+	Amount    int    `json:"amount" example:"5187" dynamodbav:"amount"`
+	Currency  string `json:"currency" example:"USD" dynamodbav:"currency"`
+	SettledOn string `json:"settled_on" example:"2026-10-19" dynamodbav:"settled_on"`
+}
+
+// SettlementImport represents a settlement file that was imported, with
+// the amount settled in each currency. The same file cannot be imported
+// twice.
+// @name SettlementImport
+type SettlementImport struct {
+	ImportID   int             `json:"import_id" example:"1" dynamodbav:"import_id"`
+	FileName   string          `json:"file_name,omitempty" example:"settlement-2026-10-19.csv" dynamodbav:"file_name,omitempty"`
+	Checksum   string          `json:"checksum" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08" dynamodbav:"checksum"`
+	RowCount   int             `json:"row_count" example:"42" dynamodbav:"row_count"`
+	Totals     []Money         `json:"totals" dynamodbav:"totals"`
+	Rows       []SettlementRow `json:"rows,omitempty" dynamodbav:"-"`
+	ImportedAt time.Time       `json:"imported_at" dynamodbav:"imported_at"`
+}
+
+// ReconciliationItem represents one captured payment, or one settlement row
+// that matches no capture, and how the two sides compare
+// @name ReconciliationItem
+type ReconciliationItem struct {
+	Status         ReconciliationStatus `json:"status" example:"amount_mismatch"`
+	PaymentID      int                  `json:"payment_id,omitempty" example:"1"`
+	Reference      string               `json:"reference" example:"pay_5f2b8c1e9a7d4e3f6a0b1c2d"`
+	Currency       string               `json:"currency" example:"USD"`
+	CapturedAmount int                  `json:"captured_amount" example:"5187"`
+	SettledAmount  int                  `json:"settled_amount" example:"5087"`
+	RowIDs         []int                `json:"row_ids"`
+	Detail         string               `json:"detail,omitempty" example:"settled 5087 USD for a capture of 5187 USD"`
+}
+
+// ReconciliationTotals represents what was captured and settled in one
+// currency on the date of a reconciliation report
+// @name ReconciliationTotals
+type ReconciliationTotals struct {
+	Currency      string `json:"currency" example:"USD"`
+	CapturedTotal int    `json:"captured_total" example:"218530"`
+	SettledTotal  int    `json:"settled_total" example:"218430"`
+}
+
+// ReconciliationReport represents the payments captured on a date checked
+// against every imported settlement row, together with rows settled on that
+// date that match no capture. Totals are kept apart for each currency.
+// @name ReconciliationReport
+type ReconciliationReport struct {
+	Date             string                 `json:"date" example:"2026-10-19"`
+	Captures         int                    `json:"captures" example:"40"`
+	Matched          int                    `json:"matched" example:"37"`
+	Missing          int                    `json:"missing" example:"1"`
+	Duplicates       int                    `json:"duplicates" example:"1"`
+	AmountMismatches int                    `json:"amount_mismatches" example:"1"`
+	Unmatched        int                    `json:"unmatched" example:"0"`
+	Totals           []ReconciliationTotals `json:"totals"`
+	Items            []ReconciliationItem   `json:"items"`
+	GeneratedAt      time.Time              `json:"generated_at"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// ShipmentStatus is the carrier's latest view of a shipment
+type ShipmentStatus string
+
+const (
+	ShipmentLabelCreated   ShipmentStatus = "label_created"
+	ShipmentInTransit      ShipmentStatus = "in_transit"
+	ShipmentOutForDelivery ShipmentStatus = "out_for_delivery"
+	ShipmentDelivered      ShipmentStatus = "delivered"
+	ShipmentException      ShipmentStatus = "exception"
+)
+
+// ShipmentEvent represents a status change reported by the carrier
+// @name ShipmentEvent
+type ShipmentEvent struct {
+	Status     ShipmentStatus `json:"status" example:"in_transit" dynamodbav:"status"`
+	Detail     string         `json:"detail,omitempty" example:"Departed origin facility" dynamodbav:"detail,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at" dynamodbav:"occurred_at"`
+}
+
+// Shipment represents the packed cartons of a pick list handed to a carrier
+// @name Shipment
+type Shipment struct {
+	ShipmentID     int             `json:"shipment_id" example:"1" dynamodbav:"shipment_id"`
+	OrderRef       string          `json:"order_ref" example:"1000" dynamodbav:"order_ref"`
+	PickListID     int             `json:"pick_list_id" example:"1" dynamodbav:"pick_list_id"`
+	WarehouseID    int             `json:"warehouse_id" example:"1" dynamodbav:"warehouse_id"`
+	Carrier        string          `json:"carrier" example:"FAKE" dynamodbav:"carrier"`
+	ServiceLevel   string          `json:"service_level" example:"Ground" dynamodbav:"service_level"`
+	TrackingNumber string          `json:"tracking_number" example:"FAKE0000000001" dynamodbav:"tracking_number"`
+	Status         ShipmentStatus  `json:"status" example:"label_created" dynamodbav:"status"`
+	ShipTo         Address         `json:"ship_to" dynamodbav:"ship_to"`
+	Cartons        []Carton        `json:"cartons" dynamodbav:"cartons"`
+	Events         []ShipmentEvent `json:"events" dynamodbav:"events"`
+	CreatedBy      string          `json:"created_by" example:"jane.doe" dynamodbav:"created_by"`
+	CreatedAt      time.Time       `json:"created_at" dynamodbav:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" dynamodbav:"delivered_at,omitempty"`
+}
+
+// CreateShipmentRequest represents a request to ship a packed pick list.
+// ShipTo defaults to the destination the order was reserved for.
+// @name CreateShipmentRequest
+type CreateShipmentRequest struct {
+	PickListID   int      `json:"pick_list_id" binding:"required,min=1" example:"1"`
+	ServiceLevel string   `json:"service_level" binding:"required,min=1,max=50" example:"Ground"`
+	ShipTo       *Address `json:"ship_to,omitempty"`
+	Actor        string   `json:"actor" binding:"required,min=1,max=100" example:"jane.doe"`
+}
+
+// OrderShipment summarizes a shipment on the order it fulfills
+// @name OrderShipment
+type OrderShipment struct {
+	ShipmentID     int            `json:"shipment_id" example:"1" dynamodbav:"shipment_id"`
+	WarehouseID    int            `json:"warehouse_id" example:"1" dynamodbav:"warehouse_id"`
+	Carrier        string         `json:"carrier" example:"FAKE" dynamodbav:"carrier"`
+	TrackingNumber string         `json:"tracking_number" example:"FAKE0000000001" dynamodbav:"tracking_number"`
+	Status         ShipmentStatus `json:"status" example:"in_transit" dynamodbav:"status"`
+	ShippedAt      time.Time      `json:"shipped_at" dynamodbav:"shipped_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty" dynamodbav:"delivered_at,omitempty"`
+}
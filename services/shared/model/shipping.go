@@ -0,0 +1,20 @@
+package model
+
+// ShippingOption represents a quoted carrier service for a cart
+// @name ShippingOption
+type ShippingOption struct {
+	OptionID     string `json:"option_id" example:"ups-ground" dynamodbav:"option_id"`
+	Carrier      string `json:"carrier" example:"UPS" dynamodbav:"carrier"`
+	ServiceLevel string `json:"service_level" example:"Ground" dynamodbav:"service_level"`
+	Zone         string `json:"zone" example:"US-WEST" dynamodbav:"zone"`
+	Packages     int    `json:"packages" example:"1" dynamodbav:"packages"`
+	Weight       int    `json:"weight" example:"2500" dynamodbav:"weight"`
+	Price        int    `json:"price" example:"899" dynamodbav:"price"`
+	TransitDays  int    `json:"transit_days" example:"5" dynamodbav:"transit_days"`
+}
+
+// SelectShippingOptionRequest represents a request to choose a shipping option for a cart
+// @name SelectShippingOptionRequest
+type SelectShippingOptionRequest struct {
+	OptionID string `json:"option_id" binding:"required,min=1" example:"ups-ground"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// TransferStatus is the lifecycle state of a transfer order
+type TransferStatus string
+
+const (
+	TransferRequested         TransferStatus = "requested"
+	TransferInTransit         TransferStatus = "in_transit"
+	TransferPartiallyReceived TransferStatus = "partially_received"
+	TransferReceived          TransferStatus = "received"
+	TransferClosed            TransferStatus = "closed"
+	TransferCancelled         TransferStatus = "cancelled"
+)
+
+// TransferLine represents a product moving between warehouses.
+// Shortage is what was never received when the transfer was closed.
+// @name TransferLine
+type TransferLine struct {
+	ProductID int `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	Quantity  int `json:"quantity" example:"10" dynamodbav:"quantity"`
+	Received  int `json:"received" example:"8" dynamodbav:"received"`
+	Shortage  int `json:"shortage" example:"2" dynamodbav:"shortage"`
+}
+
+// TransferOrder represents a rebalancing move of stock between two warehouses
+// @name TransferOrder
+type TransferOrder struct {
+	TransferID      int            `json:"transfer_id" example:"1" dynamodbav:"transfer_id"`
+	FromWarehouseID int            `json:"from_warehouse_id" example:"1" dynamodbav:"from_warehouse_id"`
+	ToWarehouseID   int            `json:"to_warehouse_id" example:"2" dynamodbav:"to_warehouse_id"`
+	Status          TransferStatus `json:"status" example:"in_transit" dynamodbav:"status"`
+	Lines           []TransferLine `json:"lines" dynamodbav:"lines"`
+	Reference       string         `json:"reference,omitempty" example:"REBAL-3" dynamodbav:"reference,omitempty"`
+	CreatedBy       string         `json:"created_by" example:"jdoe" dynamodbav:"created_by"`
+	CreatedAt       time.Time      `json:"created_at" dynamodbav:"created_at"`
+	ShippedAt       *time.Time     `json:"shipped_at,omitempty" dynamodbav:"shipped_at,omitempty"`
+	ClosedAt        *time.Time     `json:"closed_at,omitempty" dynamodbav:"closed_at,omitempty"`
+}
+
+// CreateTransferRequest represents a request to move stock between warehouses
+// @name CreateTransferRequest
+type CreateTransferRequest struct {
+	FromWarehouseID int                   `json:"from_warehouse_id" binding:"required,min=1" example:"1"`
+	ToWarehouseID   int                   `json:"to_warehouse_id" binding:"required,min=1,nefield=FromWarehouseID" example:"2"`
+	Lines           []TransferLineRequest `json:"lines" binding:"required,min=1,dive"`
+	Actor           string                `json:"actor" binding:"required,min=1,max=100" example:"jdoe"`
+	Reference       string                `json:"reference,omitempty" binding:"max=100" example:"REBAL-3"`
+}
+
+// TransferLineRequest represents a quantity of a product on a transfer
+// @name TransferLineRequest
+type TransferLineRequest struct {
+	ProductID int `json:"product_id" binding:"required,min=1" example:"12345"`
+	Quantity  int `json:"quantity" binding:"required,min=1" example:"10"`
+}
+
+// TransferReceiptRequest represents stock arriving at the destination of a transfer
+// @name TransferReceiptRequest
+type TransferReceiptRequest struct {
+	Lines []TransferLineRequest `json:"lines" binding:"required,min=1,dive"`
+	Actor string                `json:"actor" binding:"required,min=1,max=100" example:"jdoe"`
+}
+
+// TransferActionRequest identifies who ships, closes or cancels a transfer
+// @name TransferActionRequest
+type TransferActionRequest struct {
+	Actor string `json:"actor" binding:"required,min=1,max=100" example:"jdoe"`
+}
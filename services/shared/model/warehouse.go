@@ -0,0 +1,79 @@
+package model
+
+import "time"
+
+// StockLevel represents the quantity of a product in one warehouse.
+// Available is on-hand minus units held by active reservations and units
+// in expired lots, and InTransit is stock shipped to this warehouse but not
+// yet received. Lots lists the lot-tracked part of the stock; the rest is
+// untracked.
+// @name StockLevel
+type StockLevel struct {
+	ProductID   int        `json:"product_id" example:"12345" dynamodbav:"product_id"`
+	WarehouseID int        `json:"warehouse_id" example:"1" dynamodbav:"warehouse_id"`
+	OnHand      int        `json:"on_hand" example:"42" dynamodbav:"on_hand"`
+	Reserved    int        `json:"reserved" example:"2" dynamodbav:"reserved"`
+	Available   int        `json:"available" example:"40" dynamodbav:"-"`
+	InTransit   int        `json:"in_transit" example:"5" dynamodbav:"in_transit"`
+	Lots        []LotLevel `json:"lots,omitempty" dynamodbav:"lots,omitempty"`
+}
+
+// ProductStock represents the stock of a product across all warehouses
+// @name ProductStock
+type ProductStock struct {
+	ProductID  int          `json:"product_id" example:"12345"`
+	OnHand     int          `json:"on_hand" example:"42"`
+	Reserved   int          `json:"reserved" example:"2"`
+	Available  int          `json:"available" example:"40"`
+	InTransit  int          `json:"in_transit" example:"5"`
+	Warehouses []StockLevel `json:"warehouses"`
+}
+
+// StockAdjustmentRequest represents a relative change to a product's stock in
+// a warehouse. Without a lot number only untracked stock is adjusted.
+// Serialized products list one serial number per unit adjusted.
+// @name StockAdjustmentRequest
+type StockAdjustmentRequest struct {
+	WarehouseID   int      `json:"warehouse_id" binding:"required,min=1" example:"1"`
+	LotNumber     string   `json:"lot_number,omitempty" binding:"max=50" example:"L2024-118"`
+	Quantity      int      `json:"quantity" binding:"required,ne=0" example:"-2"`
+	SerialNumbers []string `json:"serial_numbers,omitempty" binding:"omitempty,dive,min=1,max=100"`
+	ReasonCode    string   `json:"reason_code" binding:"required,oneof=DAMAGED LOST FOUND EXPIRED RETURN CORRECTION OTHER" example:"DAMAGED"`
+	Actor         string   `json:"actor" binding:"required,min=1,max=100" example:"jdoe"`
+	Reference     string   `json:"reference,omitempty" binding:"max=100" example:"INC-42"`
+}
+
+// SetStockRequest represents an absolute stock count for a product in a warehouse
+// @name SetStockRequest
+type SetStockRequest struct {
+	OnHand    int    `json:"on_hand" binding:"min=0" example:"40"`
+	Actor     string `json:"actor" binding:"required,min=1,max=100" example:"jdoe"`
+	Reference string `json:"reference,omitempty" binding:"max=100" example:"COUNT-7"`
+}
+
+// StockReceiptRequest represents goods received into a warehouse. Perishable
+// goods carry a lot number, and the expiry date is required for a new lot.
+// Serialized products list one serial number per unit received.
+// @name StockReceiptRequest
+type StockReceiptRequest struct {
+	WarehouseID   int        `json:"warehouse_id" binding:"required,min=1" example:"1"`
+	Quantity      int        `json:"quantity" binding:"required,min=1" example:"24"`
+	LotNumber     string     `json:"lot_number,omitempty" binding:"max=50" example:"L2024-118"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty" example:"2025-03-01T00:00:00Z"`
+	SerialNumbers []string   `json:"serial_numbers,omitempty" binding:"omitempty,dive,min=1,max=100"`
+	ReasonCode    string     `json:"reason_code,omitempty" binding:"omitempty,oneof=PURCHASE_ORDER RETURN OTHER" example:"PURCHASE_ORDER"`
+	Actor         string     `json:"actor" binding:"required,min=1,max=100" example:"jdoe"`
+	Reference     string     `json:"reference,omitempty" binding:"max=100" example:"PO-1001"`
+}
+
+// StockTransferRequest represents an immediate move of stock between
+// warehouses. Serialized products list one serial number per unit moved.
+// @name StockTransferRequest
+type StockTransferRequest struct {
+	FromWarehouseID int      `json:"from_warehouse_id" binding:"required,min=1" example:"1"`
+	ToWarehouseID   int      `json:"to_warehouse_id" binding:"required,min=1,nefield=FromWarehouseID" example:"2"`
+	Quantity        int      `json:"quantity" binding:"required,min=1" example:"5"`
+	SerialNumbers   []string `json:"serial_numbers,omitempty" binding:"omitempty,dive,min=1,max=100"`
+	Actor           string   `json:"actor" binding:"required,min=1,max=100" example:"jdoe"`
+	Reference       string   `json:"reference,omitempty" binding:"max=100" example:"REBAL-3"`
+}
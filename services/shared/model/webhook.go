@@ -0,0 +1,70 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook event types published by the services
+const (
+	EventPaymentCaptured = "payment.captured"
+	EventPaymentRefunded = "payment.refunded"
+	EventOrderPlaced     = "order.placed"
+	EventDisputeOpened   = "dispute.opened"
+	EventDisputeDue      = "dispute.response_due"
+	EventDisputeClosed   = "dispute.closed"
+)
+
+// WebhookDeliveryStatus is where a webhook delivery is in its retries
+type WebhookDeliveryStatus string
+
+const (
+	WebhookPending      WebhookDeliveryStatus = "pending"
+	WebhookDelivered    WebhookDeliveryStatus = "delivered"
+	WebhookDeadLettered WebhookDeliveryStatus = "dead_lettered"
+)
+
+// WebhookSubscription represents an endpoint that receives events of the
+// listed types. The secret signs every delivery and is only shown when
+// the subscription is created.
+// @name WebhookSubscription
+type WebhookSubscription struct {
+	SubscriptionID int       `json:"subscription_id" example:"1" dynamodbav:"subscription_id"`
+	URL            string    `json:"url" example:"https://erp.example.com/hooks/gocart" dynamodbav:"url"`
+	EventTypes     []string  `json:"event_types" example:"payment.captured" dynamodbav:"event_types"`
+	Secret         string    `json:"secret,omitempty" example:"whsec_3f9a1c7e5b2d8f4a6c0e1b3d5f7a9c2e4b6d8f0a1c3e5b7d" dynamodbav:"secret"`
+	CreatedAt      time.Time `json:"created_at" dynamodbav:"created_at"`
+}
+
+// WebhookEvent represents the body sent to a subscriber
+// @name WebhookEvent
+type WebhookEvent struct {
+	EventID   string          `json:"event_id" example:"evt_8b1e4c7a2d5f9b3e6c0a1d4f" dynamodbav:"event_id"`
+	Type      string          `json:"type" example:"payment.captured" dynamodbav:"type"`
+	CreatedAt time.Time       `json:"created_at" dynamodbav:"created_at"`
+	Data      json.RawMessage `json:"data" swaggertype:"object" dynamodbav:"data"`
+}
+
+// WebhookDelivery represents sending one event to one subscription,
+// including every attempt made so far
+// @name WebhookDelivery
+type WebhookDelivery struct {
+	DeliveryID     int                   `json:"delivery_id" example:"1" dynamodbav:"delivery_id"`
+	SubscriptionID int                   `json:"subscription_id" example:"1" dynamodbav:"subscription_id"`
+	URL            string                `json:"url" example:"https://erp.example.com/hooks/gocart" dynamodbav:"url"`
+	Event          WebhookEvent          `json:"event" dynamodbav:"event"`
+	Status         WebhookDeliveryStatus `json:"status" example:"pending" dynamodbav:"status"`
+	Attempts       int                   `json:"attempts" example:"2" dynamodbav:"attempts"`
+	LastStatusCode int                   `json:"last_status_code,omitempty" example:"503" dynamodbav:"last_status_code,omitempty"`
+	LastError      string                `json:"last_error,omitempty" example:"subscriber returned status 503" dynamodbav:"last_error,omitempty"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" dynamodbav:"next_attempt_at,omitempty"`
+	CreatedAt      time.Time             `json:"created_at" dynamodbav:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" dynamodbav:"delivered_at,omitempty"`
+}
+
+// CreateWebhookSubscriptionRequest represents a request to register a webhook endpoint
+// @name CreateWebhookSubscriptionRequest
+type CreateWebhookSubscriptionRequest struct {
+	URL        string   `json:"url" binding:"required,url,max=2000" example:"https://erp.example.com/hooks/gocart"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,min=1,max=100" example:"payment.captured"`
+}
@@ -0,0 +1,81 @@
+package units
+
+import (
+	"errors"
+)
+
+var (
+	ErrUnknownUnit = errors.New("unknown unit")
+)
+
+// WeightUnit is a unit of mass
+type WeightUnit string
+
+const (
+	Gram     WeightUnit = "g"
+	Kilogram WeightUnit = "kg"
+	Ounce    WeightUnit = "oz"
+	Pound    WeightUnit = "lb"
+)
+
+// LengthUnit is a unit of length
+type LengthUnit string
+
+const (
+	Millimeter LengthUnit = "mm"
+	Centimeter LengthUnit = "cm"
+	Inch       LengthUnit = "in"
+)
+
+var gramsPer = map[WeightUnit]float64{
+	Gram:     1,
+	Kilogram: 1000,
+	Ounce:    28.349523125,
+	Pound:    453.59237,
+}
+
+var millimetersPer = map[LengthUnit]float64{
+	Millimeter: 1,
+	Centimeter: 10,
+	Inch:       25.4,
+}
+
+// Valid reports whether u is a known weight unit
+func (u WeightUnit) Valid() bool {
+	_, ok := gramsPer[u]
+	return ok
+}
+
+// Valid reports whether u is a known length unit
+func (u LengthUnit) Valid() bool {
+	_, ok := millimetersPer[u]
+	return ok
+}
+
+// ConvertWeight converts value from one weight unit to another
+func ConvertWeight(value float64, from, to WeightUnit) (float64, error) {
+	fromFactor, ok := gramsPer[from]
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+	toFactor, ok := gramsPer[to]
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+
+	return value * fromFactor / toFactor, nil
+}
+
+// ConvertLength converts value from one length unit to another
+func ConvertLength(value float64, from, to LengthUnit) (float64, error) {
+	fromFactor, ok := millimetersPer[from]
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+	toFactor, ok := millimetersPer[to]
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+
+	return value * fromFactor / toFactor, nil
+}
@@ -0,0 +1,420 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrInvalidSubscription  = errors.New("invalid webhook subscription")
+	ErrUnknownEventType     = errors.New("unknown webhook event type")
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrDeliveryInProgress   = errors.New("webhook delivery is still being retried")
+)
+
+// RetryPolicy controls how often a failed delivery is retried. The wait
+// doubles after every failed attempt, up to MaxBackoff, and the delivery is
+// dead-lettered once MaxAttempts have failed.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy retries for roughly four hours before giving up
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 8, InitialBackoff: 30 * time.Second, MaxBackoff: time.Hour}
+
+// Backoff returns how long to wait after the given number of failed attempts
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempts && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return backoff
+}
+
+// Dispatcher keeps webhook subscriptions and sends published events to
+// them, signed with each subscription's secret. Each service creates one
+// with the event types it publishes.
+type Dispatcher struct {
+	eventTypes         map[string]bool
+	policy             RetryPolicy
+	client             *http.Client
+	subscriptions      map[int]*model.WebhookSubscription
+	deliveries         map[int]*model.WebhookDelivery
+	sending            map[int]bool
+	nextSubscriptionID int
+	nextDeliveryID     int
+	mu                 sync.Mutex
+}
+
+func NewDispatcher(eventTypes []string, policy RetryPolicy) *Dispatcher {
+	known := make(map[string]bool, len(eventTypes))
+	for _, eventType := range eventTypes {
+		known[eventType] = true
+	}
+
+	return &Dispatcher{
+		eventTypes:         known,
+		policy:             policy,
+		client:             &http.Client{Timeout: 10 * time.Second},
+		subscriptions:      make(map[int]*model.WebhookSubscription),
+		deliveries:         make(map[int]*model.WebhookDelivery),
+		sending:            make(map[int]bool),
+		nextSubscriptionID: 1,
+		nextDeliveryID:     1,
+	}
+}
+
+// Subscribe registers an endpoint for the given event types and generates
+// the secret its deliveries are signed with. The secret is only returned here.
+func (d *Dispatcher) Subscribe(req *model.CreateWebhookSubscriptionRequest) (*model.WebhookSubscription, error) {
+	endpoint, err := url.Parse(req.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, ErrInvalidSubscription
+	}
+	if len(req.EventTypes) == 0 {
+		return nil, ErrInvalidSubscription
+	}
+
+	eventTypes := []string{}
+	seen := make(map[string]bool)
+	for _, eventType := range req.EventTypes {
+		if !d.eventTypes[eventType] {
+			return nil, ErrUnknownEventType
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	subscription := &model.WebhookSubscription{
+		SubscriptionID: d.nextSubscriptionID,
+		URL:            req.URL,
+		EventTypes:     eventTypes,
+		Secret:         secret,
+		CreatedAt:      time.Now().UTC(),
+	}
+	d.subscriptions[subscription.SubscriptionID] = subscription
+	d.nextSubscriptionID++
+
+	subscriptionCopy := copySubscription(subscription)
+	subscriptionCopy.Secret = secret
+	return subscriptionCopy, nil
+}
+
+// ListSubscriptions returns every subscription without its secret
+func (d *Dispatcher) ListSubscriptions() []*model.WebhookSubscription {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	subscriptions := []*model.WebhookSubscription{}
+	for _, subscription := range d.subscriptions {
+		subscriptions = append(subscriptions, copySubscription(subscription))
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].SubscriptionID < subscriptions[j].SubscriptionID
+	})
+	return subscriptions
+}
+
+// DeleteSubscription removes a subscription. Deliveries still pending for
+// it are dead-lettered on their next attempt.
+func (d *Dispatcher) DeleteSubscription(subscriptionID int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, exists := d.subscriptions[subscriptionID]; !exists {
+		return ErrSubscriptionNotFound
+	}
+	delete(d.subscriptions, subscriptionID)
+	return nil
+}
+
+// Publish queues an event for every subscription to its type and starts
+// sending it in the background. Failed deliveries are retried by Run.
+func (d *Dispatcher) Publish(eventType string, data any) error {
+	if !d.eventTypes[eventType] {
+		return ErrUnknownEventType
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	eventID, err := newEventID()
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	now := time.Now().UTC()
+	event := model.WebhookEvent{EventID: eventID, Type: eventType, CreatedAt: now, Data: payload}
+	deliveryIDs := []int{}
+	for _, subscription := range d.subscriptions {
+		if !subscribedTo(subscription, eventType) {
+			continue
+		}
+		nextAttemptAt := now
+		d.deliveries[d.nextDeliveryID] = &model.WebhookDelivery{
+			DeliveryID:     d.nextDeliveryID,
+			SubscriptionID: subscription.SubscriptionID,
+			URL:            subscription.URL,
+			Event:          event,
+			Status:         model.WebhookPending,
+			NextAttemptAt:  &nextAttemptAt,
+			CreatedAt:      now,
+		}
+		deliveryIDs = append(deliveryIDs, d.nextDeliveryID)
+		d.nextDeliveryID++
+	}
+	d.mu.Unlock()
+
+	if len(deliveryIDs) > 0 {
+		go func() {
+			for _, deliveryID := range deliveryIDs {
+				d.attempt(deliveryID)
+			}
+		}()
+	}
+	return nil
+}
+
+// ListDeliveries returns deliveries oldest first, optionally only those in
+// one status
+func (d *Dispatcher) ListDeliveries(status model.WebhookDeliveryStatus) []*model.WebhookDelivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	deliveries := []*model.WebhookDelivery{}
+	for _, delivery := range d.deliveries {
+		if status == "" || delivery.Status == status {
+			deliveries = append(deliveries, copyDelivery(delivery))
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].DeliveryID < deliveries[j].DeliveryID
+	})
+	return deliveries
+}
+
+// Redeliver sends a delivered or dead-lettered delivery again now, with a
+// fresh set of retries if it fails
+func (d *Dispatcher) Redeliver(deliveryID int) (*model.WebhookDelivery, error) {
+	d.mu.Lock()
+	delivery, exists := d.deliveries[deliveryID]
+	if !exists {
+		d.mu.Unlock()
+		return nil, ErrDeliveryNotFound
+	}
+	if delivery.Status == model.WebhookPending || d.sending[deliveryID] {
+		d.mu.Unlock()
+		return nil, ErrDeliveryInProgress
+	}
+	if _, exists := d.subscriptions[delivery.SubscriptionID]; !exists {
+		d.mu.Unlock()
+		return nil, ErrSubscriptionNotFound
+	}
+	now := time.Now().UTC()
+	delivery.Status = model.WebhookPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	delivery.DeliveredAt = nil
+	d.mu.Unlock()
+
+	return d.attempt(deliveryID), nil
+}
+
+// DeliverDue attempts every pending delivery whose retry is due and
+// returns how many were delivered
+func (d *Dispatcher) DeliverDue() int {
+	d.mu.Lock()
+	now := time.Now()
+	due := []int{}
+	for deliveryID, delivery := range d.deliveries {
+		if delivery.Status == model.WebhookPending && !d.sending[deliveryID] && !delivery.NextAttemptAt.After(now) {
+			due = append(due, deliveryID)
+		}
+	}
+	d.mu.Unlock()
+
+	sort.Ints(due)
+	delivered := 0
+	for _, deliveryID := range due {
+		if delivery := d.attempt(deliveryID); delivery != nil && delivery.Status == model.WebhookDelivered {
+			delivered++
+		}
+	}
+	return delivered
+}
+
+// Run retries due deliveries every interval. It never returns.
+func (d *Dispatcher) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if count := d.DeliverDue(); count > 0 {
+			log.Printf("Delivered %d webhook retries", count)
+		}
+	}
+}
+
+// attempt sends a pending delivery once and records the outcome. It
+// returns nil if the delivery is already being sent or is no longer pending.
+func (d *Dispatcher) attempt(deliveryID int) *model.WebhookDelivery {
+	d.mu.Lock()
+	delivery, exists := d.deliveries[deliveryID]
+	if !exists || delivery.Status != model.WebhookPending || d.sending[deliveryID] {
+		d.mu.Unlock()
+		return nil
+	}
+	subscription, subscribed := d.subscriptions[delivery.SubscriptionID]
+	if !subscribed {
+		delivery.Status = model.WebhookDeadLettered
+		delivery.LastError = "subscription was deleted"
+		delivery.NextAttemptAt = nil
+		deliveryCopy := copyDelivery(delivery)
+		d.mu.Unlock()
+		return deliveryCopy
+	}
+	d.sending[deliveryID] = true
+	secret := subscription.Secret
+	event := delivery.Event
+	endpoint := delivery.URL
+	d.mu.Unlock()
+
+	statusCode, err := d.send(endpoint, secret, deliveryID, event)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.sending, deliveryID)
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	if err == nil {
+		delivery.Status = model.WebhookDelivered
+		delivery.LastError = ""
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+	} else {
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= d.policy.MaxAttempts {
+			delivery.Status = model.WebhookDeadLettered
+			delivery.NextAttemptAt = nil
+			log.Printf("Dead-lettered webhook delivery %d after %d attempts: %v", deliveryID, delivery.Attempts, err)
+		} else {
+			nextAttemptAt := now.Add(d.policy.Backoff(delivery.Attempts))
+			delivery.NextAttemptAt = &nextAttemptAt
+		}
+	}
+	return copyDelivery(delivery)
+}
+
+// send posts a signed event to a subscriber. Any 2xx response counts as
+// delivered.
+func (d *Dispatcher) send(endpoint, secret string, deliveryID int, event model.WebhookEvent) (int, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event.Type)
+	req.Header.Set(DeliveryHeader, fmt.Sprint(deliveryID))
+	req.Header.Set(SignatureHeader, Sign(secret, time.Now(), payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("subscriber returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// subscribedTo reports whether a subscription wants events of a type
+func subscribedTo(subscription *model.WebhookSubscription, eventType string) bool {
+	for _, subscribed := range subscription.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// copySubscription returns a copy of a subscription without its secret
+func copySubscription(subscription *model.WebhookSubscription) *model.WebhookSubscription {
+	subscriptionCopy := *subscription
+	subscriptionCopy.EventTypes = append([]string(nil), subscription.EventTypes...)
+	subscriptionCopy.Secret = ""
+	return &subscriptionCopy
+}
+
+// copyDelivery returns a copy of a delivery that shares no pointers with the original
+func copyDelivery(delivery *model.WebhookDelivery) *model.WebhookDelivery {
+	deliveryCopy := *delivery
+	deliveryCopy.Event.Data = append([]byte(nil), delivery.Event.Data...)
+	if delivery.NextAttemptAt != nil {
+		nextAttemptAt := *delivery.NextAttemptAt
+		deliveryCopy.NextAttemptAt = &nextAttemptAt
+	}
+	if delivery.DeliveredAt != nil {
+		deliveredAt := *delivery.DeliveredAt
+		deliveryCopy.DeliveredAt = &deliveredAt
+	}
+	return &deliveryCopy
+}
+
+// newSecret generates a random signing secret
+func newSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// newEventID generates a random event ID that receivers can deduplicate on
+func newEventID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "evt_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+// Handler serves a Dispatcher's subscriptions and deliveries over HTTP.
+// Services call it from their own handlers, which carry the API
+// documentation for the event types they publish.
+type Handler struct {
+	dispatcher *Dispatcher
+}
+
+func NewHandler(dispatcher *Dispatcher) *Handler {
+	return &Handler{dispatcher: dispatcher}
+}
+
+// CreateSubscription handles POST /webhook/subscriptions
+func (h *Handler) CreateSubscription(c *gin.Context) {
+	var req model.CreateWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	subscription, err := h.dispatcher.Subscribe(&req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// ListSubscriptions handles GET /webhook/subscriptions, listing every
+// subscription without secrets
+func (h *Handler) ListSubscriptions(c *gin.Context) {
+	c.JSON(http.StatusOK, h.dispatcher.ListSubscriptions())
+}
+
+// DeleteSubscription handles DELETE /webhook/subscriptions/{subscriptionId}.
+// Deliveries still waiting to be retried are dead-lettered.
+func (h *Handler) DeleteSubscription(c *gin.Context) {
+	subscriptionID, err := strconv.Atoi(c.Param("subscriptionId"))
+	if err != nil || subscriptionID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid subscription ID",
+			Details: "Subscription ID must be a positive integer",
+		})
+		return
+	}
+
+	if err := h.dispatcher.DeleteSubscription(subscriptionID); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /webhook/deliveries, oldest first and
+// optionally only those in one status
+func (h *Handler) ListDeliveries(c *gin.Context) {
+	status := model.WebhookDeliveryStatus(c.Query("status"))
+	switch status {
+	case "", model.WebhookPending, model.WebhookDelivered, model.WebhookDeadLettered:
+	default:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid delivery status",
+			Details: "status must be one of pending, delivered or dead_lettered",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.dispatcher.ListDeliveries(status))
+}
+
+// ListDeadLetters handles GET /webhook/dead-letters, listing deliveries
+// that failed every retry
+func (h *Handler) ListDeadLetters(c *gin.Context) {
+	c.JSON(http.StatusOK, h.dispatcher.ListDeliveries(model.WebhookDeadLettered))
+}
+
+// RedeliverDelivery handles POST /webhook/deliveries/{deliveryId}/redeliver,
+// sending a delivered or dead-lettered event again now
+func (h *Handler) RedeliverDelivery(c *gin.Context) {
+	deliveryID, err := strconv.Atoi(c.Param("deliveryId"))
+	if err != nil || deliveryID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid delivery ID",
+			Details: "Delivery ID must be a positive integer",
+		})
+		return
+	}
+
+	delivery, err := h.dispatcher.Redeliver(deliveryID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// writeError maps webhook errors to responses
+func writeError(c *gin.Context, err error) {
+	switch err {
+	case ErrInvalidSubscription, ErrUnknownEventType:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	case ErrSubscriptionNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Subscription not found",
+			Details: "No webhook subscription exists with the specified ID",
+		})
+	case ErrDeliveryNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Delivery not found",
+			Details: "No webhook delivery exists with the specified ID",
+		})
+	case ErrDeliveryInProgress:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Delivery is still being retried",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers sent with every delivery
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// DefaultTolerance is how old a signature's timestamp may be before
+// receivers should reject it as a replay
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	ErrInvalidSignatureHeader = errors.New("invalid signature header")
+	ErrSignatureMismatch      = errors.New("signature does not match payload")
+	ErrSignatureExpired       = errors.New("signature timestamp outside tolerance")
+)
+
+// Sign returns the signature header value for a payload sent at timestamp:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<payload>">".
+// Signing the timestamp with the payload stops old deliveries being replayed.
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(computeMAC(secret, t, payload))
+}
+
+// Verify checks a signature header against the payload it came with. It is
+// what a receiver runs before trusting a delivery.
+func Verify(secret, header string, payload []byte, tolerance time.Duration, now time.Time) error {
+	var t string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			return ErrInvalidSignatureHeader
+		}
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			signature, err := hex.DecodeString(value)
+			if err != nil {
+				return ErrInvalidSignatureHeader
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignatureHeader
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	expected := computeMAC(secret, t, payload)
+	for _, signature := range signatures {
+		if hmac.Equal(signature, expected) {
+			return nil
+		}
+	}
+	return ErrSignatureMismatch
+}
+
+// computeMAC signs "<timestamp>.<payload>" with the secret
+func computeMAC(secret, timestamp string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
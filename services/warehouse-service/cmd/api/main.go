@@ -0,0 +1,109 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+
+	"github.com/gocart-v2/shared/model"
+	_ "github.com/gocart-v2/warehouse-service/docs"
+	"github.com/gocart-v2/warehouse-service/internal/allocation"
+	"github.com/gocart-v2/warehouse-service/internal/carrier"
+	"github.com/gocart-v2/warehouse-service/internal/handler"
+	"github.com/gocart-v2/warehouse-service/internal/notify"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+	"github.com/gocart-v2/warehouse-service/internal/router"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+// @title E-commerce API
+// @version 1.0.0
+// @description API for managing products, shopping carts, warehouse operations, and credit card processing
+// @contact.name API Support
+// @contact.email support@example.com
+// @license.name MIT
+// @license.url https://opensource.org/licenses/MIT
+// @BasePath /v1
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @securityDefinitions.bearer BearerAuth
+// @tag.name Warehouse
+// @tag.description Warehouse stock operations
+func main() {
+
+	rh := handler.NewRootHandler()
+
+	sr := repository.NewStockRepository()
+	mr := repository.NewMovementRepository()
+	snr := repository.NewSerialRepository()
+	br := repository.NewBackorderRepository()
+	bs := service.NewBackorderService(br, sr, mr, snr)
+	bh := handler.NewBackorderHandler(bs)
+	ss := service.NewStockService(sr, mr, snr, bs)
+	sh := handler.NewStockHandler(ss)
+	sns := service.NewSerialService(snr)
+	snh := handler.NewSerialHandler(sns)
+	ms := service.NewMovementService(mr, sr)
+	mh := handler.NewMovementHandler(ms)
+
+	wr := repository.NewWarehouseRepository()
+	ws := service.NewWarehouseService(wr)
+	wh := handler.NewWarehouseHandler(ws)
+
+	ae := allocation.NewEngine(allocation.DefaultCostModel, allocation.DefaultCentroids, model.AllocateNearest)
+	as := service.NewAllocationService(ae, sr, wr)
+	ah := handler.NewAllocationHandler(as)
+
+	rr := repository.NewReservationRepository()
+	rs := service.NewReservationService(rr, sr, mr, snr, as, bs)
+	rvh := handler.NewReservationHandler(rs)
+	go rs.RunExpiry(30 * time.Second)
+
+	tr := repository.NewTransferRepository()
+	ts := service.NewTransferService(tr, sr, mr, snr, bs)
+	th := handler.NewTransferHandler(ts)
+
+	pr := repository.NewReorderPolicyRepository()
+	ps := service.NewReplenishmentService(pr, sr, mr, notify.NewLogNotifier(), service.DefaultVelocityWindow)
+	ph := handler.NewReplenishmentHandler(ps)
+	go ps.RunEvaluator(5 * time.Minute)
+
+	plr := repository.NewPickListRepository()
+	pks := service.NewPickService(plr, rr, br, mr)
+	pkh := handler.NewPickHandler(pks)
+
+	shr := repository.NewShipmentRepository()
+	shs := service.NewShipmentService(shr, plr, carrier.NewFakeCarrier(true))
+	shh := handler.NewShipmentHandler(shs)
+	go shs.RunTracker(time.Minute)
+
+	ccr := repository.NewCycleCountRepository()
+	ccs := service.NewCycleCountService(ccr, sr, mr, snr, bs, service.DefaultCountApprovalThreshold)
+	cch := handler.NewCycleCountHandler(ccs)
+
+	e := gin.Default()
+	router.SetupRoutes(e, &router.AllHandlers{
+		RootHandler:          rh,
+		StockHandler:         sh,
+		ReservationHandler:   rvh,
+		MovementHandler:      mh,
+		WarehouseHandler:     wh,
+		AllocationHandler:    ah,
+		TransferHandler:      th,
+		ReplenishmentHandler: ph,
+		SerialHandler:        snh,
+		BackorderHandler:     bh,
+		PickHandler:          pkh,
+		ShipmentHandler:      shh,
+		CycleCountHandler:    cch,
+		SwaggerHandler:       swaggerFiles.Handler,
+	})
+
+	log.Println("Starting server on :8082")
+	if err := e.Run(":8082"); err != nil {
+		log.Fatal("Failed to start server:", err)
+	}
+}
@@ -0,0 +1,320 @@
+package allocation
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrInvalidRequest    = errors.New("invalid allocation request")
+	ErrUnknownStrategy   = errors.New("unknown allocation strategy")
+	ErrInsufficientStock = errors.New("insufficient stock across all warehouses")
+)
+
+// maxExhaustiveSites bounds the subset search used by the lowest cost strategy
+const maxExhaustiveSites = 12
+
+// CostModel estimates what a shipment from a warehouse costs, in minor units
+type CostModel struct {
+	PerShipment       int
+	PerKilometer      float64
+	UnknownDistanceKm float64
+}
+
+// DefaultCostModel is the built-in shipment cost estimate
+var DefaultCostModel = CostModel{
+	PerShipment:       500,
+	PerKilometer:      0.2,
+	UnknownDistanceKm: 2000,
+}
+
+// Site is a warehouse that may fulfill part of an order
+type Site struct {
+	WarehouseID  int
+	Address      *model.Address
+	HandlingCost int
+	Available    map[int]int
+}
+
+// Line is a quantity of a product to allocate
+type Line struct {
+	ProductID int
+	Quantity  int
+}
+
+// Request is the input to an allocation
+type Request struct {
+	Strategy    model.AllocationStrategy
+	Destination *model.Address
+	Lines       []Line
+	Sites       []Site
+}
+
+// Engine decides which warehouses fulfill an order
+type Engine struct {
+	costModel       CostModel
+	centroids       map[string]Coordinates
+	defaultStrategy model.AllocationStrategy
+}
+
+func NewEngine(costModel CostModel, centroids map[string]Coordinates, defaultStrategy model.AllocationStrategy) *Engine {
+	return &Engine{
+		costModel:       costModel,
+		centroids:       centroids,
+		defaultStrategy: defaultStrategy,
+	}
+}
+
+// rankedSite is a Site with its distance and cost to the destination
+type rankedSite struct {
+	Site
+	distance float64
+	located  bool
+	cost     int
+}
+
+// Allocate splits the requested lines across sites using the requested
+// strategy, or the engine default when none is given
+func (e *Engine) Allocate(req *Request) (*model.Allocation, error) {
+	if req == nil || len(req.Lines) == 0 {
+		return nil, ErrInvalidRequest
+	}
+
+	strategy := req.Strategy
+	if strategy == "" {
+		strategy = e.defaultStrategy
+	}
+
+	// Merge repeated products so each is allocated once
+	needed := make(map[int]int)
+	order := []int{}
+	for _, line := range req.Lines {
+		if line.ProductID < 1 || line.Quantity < 1 {
+			return nil, ErrInvalidRequest
+		}
+		if _, seen := needed[line.ProductID]; !seen {
+			order = append(order, line.ProductID)
+		}
+		needed[line.ProductID] += line.Quantity
+	}
+
+	sites := e.rank(req.Sites, req.Destination, needed)
+
+	var assigned map[int]map[int]int
+	var ok bool
+	switch strategy {
+	case model.AllocateNearest:
+		assigned, ok = fill(needed, sites)
+	case model.AllocateFewestShipments:
+		assigned, ok = fewestShipments(needed, sites)
+	case model.AllocateLowestCost:
+		assigned, ok = lowestCost(needed, sites)
+	default:
+		return nil, ErrUnknownStrategy
+	}
+	if !ok {
+		return nil, ErrInsufficientStock
+	}
+
+	allocation := &model.Allocation{
+		Strategy:  strategy,
+		Shipments: []model.AllocatedShipment{},
+	}
+	for _, site := range sites {
+		quantities, used := assigned[site.WarehouseID]
+		if !used {
+			continue
+		}
+
+		shipment := model.AllocatedShipment{
+			WarehouseID:   site.WarehouseID,
+			EstimatedCost: site.cost,
+			Items:         []model.CartItem{},
+		}
+		if site.located {
+			shipment.DistanceKm = math.Round(site.distance*10) / 10
+		}
+		for _, productID := range order {
+			if quantity := quantities[productID]; quantity > 0 {
+				shipment.Items = append(shipment.Items, model.CartItem{ProductID: productID, Quantity: quantity})
+			}
+		}
+
+		allocation.Shipments = append(allocation.Shipments, shipment)
+		allocation.EstimatedCost += site.cost
+	}
+	allocation.Split = len(allocation.Shipments) > 1
+
+	return allocation, nil
+}
+
+// rank orders the sites holding any needed product by distance to the
+// destination, with sites that cannot be located last
+func (e *Engine) rank(sites []Site, destination *model.Address, needed map[int]int) []rankedSite {
+	target, targetKnown := locate(destination, e.centroids)
+
+	ranked := []rankedSite{}
+	for _, site := range sites {
+		holdsNeeded := false
+		for productID := range needed {
+			if site.Available[productID] > 0 {
+				holdsNeeded = true
+				break
+			}
+		}
+		if !holdsNeeded {
+			continue
+		}
+
+		r := rankedSite{Site: site, distance: e.costModel.UnknownDistanceKm}
+		if position, ok := locate(site.Address, e.centroids); ok && targetKnown {
+			r.distance = distanceKm(position, target)
+			r.located = true
+		}
+		r.cost = e.costModel.PerShipment + site.HandlingCost + int(math.Round(r.distance*e.costModel.PerKilometer))
+		ranked = append(ranked, r)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].located != ranked[j].located {
+			return ranked[i].located
+		}
+		if ranked[i].distance != ranked[j].distance {
+			return ranked[i].distance < ranked[j].distance
+		}
+		return ranked[i].WarehouseID < ranked[j].WarehouseID
+	})
+	return ranked
+}
+
+// fill takes each product from the sites in the order given, splitting
+// across sites when one runs short
+func fill(needed map[int]int, sites []rankedSite) (map[int]map[int]int, bool) {
+	assigned := make(map[int]map[int]int)
+	for productID, quantity := range needed {
+		remaining := quantity
+		for _, site := range sites {
+			take := min(site.Available[productID], remaining)
+			if take <= 0 {
+				continue
+			}
+			if assigned[site.WarehouseID] == nil {
+				assigned[site.WarehouseID] = make(map[int]int)
+			}
+			assigned[site.WarehouseID][productID] += take
+			remaining -= take
+			if remaining == 0 {
+				break
+			}
+		}
+		if remaining > 0 {
+			return nil, false
+		}
+	}
+	return assigned, true
+}
+
+// fewestShipments ships from the nearest site that has everything, or
+// otherwise keeps adding the site that covers the most outstanding units
+func fewestShipments(needed map[int]int, sites []rankedSite) (map[int]map[int]int, bool) {
+	for _, site := range sites {
+		if covers(needed, site) {
+			return fill(needed, []rankedSite{site})
+		}
+	}
+
+	remaining := make(map[int]int, len(needed))
+	for productID, quantity := range needed {
+		remaining[productID] = quantity
+	}
+
+	chosen := []rankedSite{}
+	used := make(map[int]bool)
+	for outstanding(remaining) > 0 {
+		best := -1
+		bestUnits := 0
+		for i, site := range sites {
+			if used[site.WarehouseID] {
+				continue
+			}
+			units := 0
+			for productID, quantity := range remaining {
+				units += min(site.Available[productID], quantity)
+			}
+			if units > bestUnits {
+				best, bestUnits = i, units
+			}
+		}
+		if best < 0 {
+			return nil, false
+		}
+
+		site := sites[best]
+		used[site.WarehouseID] = true
+		chosen = append(chosen, site)
+		for productID, quantity := range remaining {
+			remaining[productID] = quantity - min(site.Available[productID], quantity)
+		}
+	}
+
+	return fill(needed, chosen)
+}
+
+// lowestCost searches every combination of sites for the cheapest one
+// that can fulfill the order, falling back to fewestShipments when there
+// are too many sites to search
+func lowestCost(needed map[int]int, sites []rankedSite) (map[int]map[int]int, bool) {
+	if len(sites) > maxExhaustiveSites {
+		return fewestShipments(needed, sites)
+	}
+
+	var best map[int]map[int]int
+	bestCost := math.MaxInt
+	bestShipments := math.MaxInt
+	for mask := 1; mask < 1<<len(sites); mask++ {
+		subset := []rankedSite{}
+		for i, site := range sites {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, site)
+			}
+		}
+
+		assigned, ok := fill(needed, subset)
+		if !ok {
+			continue
+		}
+
+		cost := 0
+		for _, site := range subset {
+			if _, used := assigned[site.WarehouseID]; used {
+				cost += site.cost
+			}
+		}
+		if cost < bestCost || (cost == bestCost && len(assigned) < bestShipments) {
+			best, bestCost, bestShipments = assigned, cost, len(assigned)
+		}
+	}
+
+	return best, best != nil
+}
+
+// covers reports whether a site alone has every needed product
+func covers(needed map[int]int, site rankedSite) bool {
+	for productID, quantity := range needed {
+		if site.Available[productID] < quantity {
+			return false
+		}
+	}
+	return true
+}
+
+func outstanding(remaining map[int]int) int {
+	total := 0
+	for _, quantity := range remaining {
+		total += quantity
+	}
+	return total
+}
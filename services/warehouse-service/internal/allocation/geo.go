@@ -0,0 +1,68 @@
+package allocation
+
+import (
+	"math"
+	"strings"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+// Coordinates is a latitude and longitude in degrees
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// DefaultCentroids approximates the position of addresses that carry no
+// coordinates, keyed by country or country and region
+var DefaultCentroids = map[string]Coordinates{
+	"US":    {Latitude: 39.83, Longitude: -98.58},
+	"US-CA": {Latitude: 36.78, Longitude: -119.42},
+	"US-OR": {Latitude: 43.80, Longitude: -120.55},
+	"US-WA": {Latitude: 47.75, Longitude: -120.74},
+	"US-NV": {Latitude: 38.80, Longitude: -116.42},
+	"US-AZ": {Latitude: 34.05, Longitude: -111.09},
+	"US-TX": {Latitude: 31.97, Longitude: -99.90},
+	"US-IL": {Latitude: 40.63, Longitude: -89.40},
+	"US-NY": {Latitude: 43.30, Longitude: -74.22},
+	"US-NJ": {Latitude: 40.06, Longitude: -74.41},
+	"US-FL": {Latitude: 27.66, Longitude: -81.52},
+	"US-GA": {Latitude: 32.17, Longitude: -82.90},
+	"CA":    {Latitude: 56.13, Longitude: -106.35},
+	"MX":    {Latitude: 23.63, Longitude: -102.55},
+	"GB":    {Latitude: 55.38, Longitude: -3.44},
+	"DE":    {Latitude: 51.17, Longitude: 10.45},
+	"FR":    {Latitude: 46.23, Longitude: 2.21},
+}
+
+// locate finds the position of an address, falling back to its region or country centroid
+func locate(address *model.Address, centroids map[string]Coordinates) (Coordinates, bool) {
+	if address == nil {
+		return Coordinates{}, false
+	}
+	if address.HasCoordinates() {
+		return Coordinates{Latitude: address.Latitude, Longitude: address.Longitude}, true
+	}
+
+	country := strings.ToUpper(address.Country)
+	if address.Region != "" {
+		if c, ok := centroids[country+"-"+strings.ToUpper(address.Region)]; ok {
+			return c, true
+		}
+	}
+	c, ok := centroids[country]
+	return c, ok
+}
+
+// distanceKm is the great-circle distance between two points
+func distanceKm(a, b Coordinates) float64 {
+	const earthRadiusKm = 6371.0
+
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
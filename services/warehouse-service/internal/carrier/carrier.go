@@ -0,0 +1,55 @@
+package carrier
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrAddressRejected   = errors.New("carrier rejected the destination address")
+	ErrUnknownShipment   = errors.New("carrier has no shipment with this tracking number")
+	ErrServiceNotOffered = errors.New("carrier does not offer this service level")
+)
+
+// Parcel is one carton handed to the carrier. Dimensions are in
+// millimeters and weight is in grams.
+type Parcel struct {
+	Length int
+	Width  int
+	Height int
+	Weight int
+}
+
+// LabelRequest describes a shipment to book with a carrier
+type LabelRequest struct {
+	Reference    string
+	ServiceLevel string
+	ShipTo       model.Address
+	Parcels      []Parcel
+}
+
+// Label is a booked shipment
+type Label struct {
+	TrackingNumber string
+	CreatedAt      time.Time
+}
+
+// TrackingEvent is one status update reported by the carrier
+type TrackingEvent struct {
+	Status     model.ShipmentStatus
+	Detail     string
+	OccurredAt time.Time
+}
+
+// Carrier books shipments and reports their progress. Implementations must
+// be safe for concurrent use.
+type Carrier interface {
+	// Name identifies the carrier on shipment records
+	Name() string
+	// CreateLabel books a shipment and returns its tracking number
+	CreateLabel(req *LabelRequest) (*Label, error)
+	// Track returns every event for a shipment, oldest first
+	Track(trackingNumber string) ([]TrackingEvent, error)
+}
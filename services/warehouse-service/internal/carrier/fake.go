@@ -0,0 +1,134 @@
+package carrier
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+// RejectedPostalCode is a destination the fake carrier always refuses
+const RejectedPostalCode = "00000"
+
+// fakeProgression is the order the fake carrier moves shipments through
+var fakeProgression = []TrackingEvent{
+	{Status: model.ShipmentLabelCreated, Detail: "Label created"},
+	{Status: model.ShipmentInTransit, Detail: "Departed origin facility"},
+	{Status: model.ShipmentOutForDelivery, Detail: "Out for delivery"},
+	{Status: model.ShipmentDelivered, Detail: "Delivered"},
+}
+
+// FakeCarrier is an in-memory carrier for development and tests. Tracking
+// numbers are sequential, and each call to Track moves a shipment one step
+// closer to delivery unless Advance or Fail has taken control of it.
+type FakeCarrier struct {
+	mu          sync.Mutex
+	nextNumber  int
+	shipments   map[string]*fakeShipment
+	autoAdvance bool
+}
+
+type fakeShipment struct {
+	events []TrackingEvent
+	manual bool
+}
+
+// NewFakeCarrier returns a fake carrier. With autoAdvance, every Track
+// call moves a shipment along; otherwise only Advance and Fail do.
+func NewFakeCarrier(autoAdvance bool) *FakeCarrier {
+	return &FakeCarrier{
+		nextNumber:  1,
+		shipments:   make(map[string]*fakeShipment),
+		autoAdvance: autoAdvance,
+	}
+}
+
+// Name identifies the fake carrier
+func (c *FakeCarrier) Name() string {
+	return "FAKE"
+}
+
+// CreateLabel books a shipment, refusing RejectedPostalCode and shipments
+// without parcels
+func (c *FakeCarrier) CreateLabel(req *LabelRequest) (*Label, error) {
+	if req.ShipTo.PostalCode == RejectedPostalCode || len(req.Parcels) == 0 {
+		return nil, ErrAddressRejected
+	}
+	if req.ServiceLevel == "" {
+		return nil, ErrServiceNotOffered
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now().UTC()
+	trackingNumber := fmt.Sprintf("FAKE%010d", c.nextNumber)
+	c.nextNumber++
+	c.shipments[trackingNumber] = &fakeShipment{
+		events: []TrackingEvent{{Status: fakeProgression[0].Status, Detail: fakeProgression[0].Detail, OccurredAt: now}},
+	}
+
+	return &Label{TrackingNumber: trackingNumber, CreatedAt: now}, nil
+}
+
+// Track returns a shipment's events, first advancing it when auto-advance is on
+func (c *FakeCarrier) Track(trackingNumber string) ([]TrackingEvent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	shipment, exists := c.shipments[trackingNumber]
+	if !exists {
+		return nil, ErrUnknownShipment
+	}
+	if c.autoAdvance && !shipment.manual {
+		shipment.advance()
+	}
+
+	return append([]TrackingEvent(nil), shipment.events...), nil
+}
+
+// Advance moves a shipment one step closer to delivery
+func (c *FakeCarrier) Advance(trackingNumber string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	shipment, exists := c.shipments[trackingNumber]
+	if !exists {
+		return ErrUnknownShipment
+	}
+	shipment.manual = true
+	shipment.advance()
+	return nil
+}
+
+// Fail reports a delivery exception on a shipment
+func (c *FakeCarrier) Fail(trackingNumber, detail string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	shipment, exists := c.shipments[trackingNumber]
+	if !exists {
+		return ErrUnknownShipment
+	}
+	shipment.manual = true
+	shipment.events = append(shipment.events, TrackingEvent{
+		Status:     model.ShipmentException,
+		Detail:     detail,
+		OccurredAt: time.Now().UTC(),
+	})
+	return nil
+}
+
+// advance appends the next status in the progression, if any
+func (s *fakeShipment) advance() {
+	current := s.events[len(s.events)-1].Status
+	for i, step := range fakeProgression[:len(fakeProgression)-1] {
+		if step.Status == current {
+			next := fakeProgression[i+1]
+			next.OccurredAt = time.Now().UTC()
+			s.events = append(s.events, next)
+			return
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+type AllocationHandler struct {
+	service *service.AllocationService
+}
+
+func NewAllocationHandler(service *service.AllocationService) *AllocationHandler {
+	return &AllocationHandler{service: service}
+}
+
+// PlanAllocation handles POST /warehouse/allocations
+// @Summary Plan order allocation
+// @Description Decide which warehouses would fulfill a set of items for a destination, splitting into several shipments when no single warehouse has everything. No stock is held.
+// @ID planAllocation
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param request body model.AllocationRequest true "Items, destination and strategy"
+// @Success 200 {object} model.Allocation
+// @Failure 400 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/allocations [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *AllocationHandler) PlanAllocation(c *gin.Context) {
+	var req model.AllocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	plan, err := h.service.PlanAllocation(&req)
+	if err == service.ErrInsufficientStock {
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INSUFFICIENT_STOCK",
+			Message: "Insufficient stock",
+			Details: "The items cannot be fulfilled from the available stock of all warehouses",
+		})
+		return
+	} else if err == service.ErrInvalidAllocation {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+type BackorderHandler struct {
+	service *service.BackorderService
+}
+
+func NewBackorderHandler(service *service.BackorderService) *BackorderHandler {
+	return &BackorderHandler{service: service}
+}
+
+// ListBackorders handles GET /warehouse/backorders
+// @Summary List backorders
+// @Description Retrieve backorders and pre-orders, optionally filtered by order, product or status
+// @ID listBackorders
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param order_ref query string false "Only include backorders for this order"
+// @Param product_id query int false "Only include backorders for this product" minimum(1)
+// @Param status query string false "Only include backorders in this status" Enums(open, partially_allocated, allocated, cancelled)
+// @Success 200 {array} model.Backorder
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/backorders [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *BackorderHandler) ListBackorders(c *gin.Context) {
+	productID := 0
+	if productIDStr := c.Query("product_id"); productIDStr != "" {
+		var err error
+		productID, err = strconv.Atoi(productIDStr)
+		if err != nil || productID < 1 {
+			c.JSON(http.StatusBadRequest, model.Error{
+				Error:   "INVALID_INPUT",
+				Message: "Invalid product ID",
+				Details: "product_id must be a positive integer",
+			})
+			return
+		}
+	}
+
+	backorders, err := h.service.ListBackorders(c.Query("order_ref"), productID, model.BackorderStatus(c.Query("status")))
+	if err != nil {
+		writeBackorderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, backorders)
+}
+
+// GetBackorder handles GET /warehouse/backorders/{backorderId}
+// @Summary Get backorder by ID
+// @Description Retrieve a backorder with the stock allocated to it so far
+// @ID getBackorder
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param backorderId path int true "Unique identifier for the backorder" minimum(1)
+// @Success 200 {object} model.Backorder
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/backorders/{backorderId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *BackorderHandler) GetBackorder(c *gin.Context) {
+	backorderID, ok := parseBackorderID(c)
+	if !ok {
+		return
+	}
+
+	backorder, err := h.service.GetBackorder(backorderID)
+	if err != nil {
+		writeBackorderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, backorder)
+}
+
+// CancelBackorder handles POST /warehouse/backorders/{backorderId}/cancel
+// @Summary Cancel backorder
+// @Description Stop allocating incoming stock to a backorder. Units already allocated stay with the order.
+// @ID cancelBackorder
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param backorderId path int true "Unique identifier for the backorder" minimum(1)
+// @Success 200 {object} model.Backorder
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/backorders/{backorderId}/cancel [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *BackorderHandler) CancelBackorder(c *gin.Context) {
+	backorderID, ok := parseBackorderID(c)
+	if !ok {
+		return
+	}
+
+	backorder, err := h.service.CancelBackorder(backorderID)
+	if err != nil {
+		writeBackorderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, backorder)
+}
+
+// parseBackorderID reads the backorderId path parameter, writing a 400 if it is invalid
+func parseBackorderID(c *gin.Context) (int, bool) {
+	backorderID, err := strconv.Atoi(c.Param("backorderId"))
+	if err != nil || backorderID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid backorder ID",
+			Details: "Backorder ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return backorderID, true
+}
+
+// writeBackorderError maps backorder errors to responses
+func writeBackorderError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrBackorderNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Backorder not found",
+			Details: "No backorder exists with the specified ID",
+		})
+	case service.ErrBackorderClosed:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Backorder cannot be cancelled",
+			Details: err.Error(),
+		})
+	case service.ErrInvalidBackorder:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
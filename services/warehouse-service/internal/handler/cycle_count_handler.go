@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+type CycleCountHandler struct {
+	service *service.CycleCountService
+}
+
+func NewCycleCountHandler(service *service.CycleCountService) *CycleCountHandler {
+	return &CycleCountHandler{service: service}
+}
+
+// CreateCycleCount handles POST /warehouse/cycle-counts
+// @Summary Start cycle count
+// @Description Snapshot the expected on-hand quantity of each lot of the selected products in a warehouse and open the count
+// @ID createCycleCount
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param request body model.CreateCycleCountRequest true "Warehouse and products to count"
+// @Success 201 {object} model.CycleCount
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/cycle-counts [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *CycleCountHandler) CreateCycleCount(c *gin.Context) {
+	var req model.CreateCycleCountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	cycleCount, err := h.service.CreateCycleCount(&req)
+	if err != nil {
+		writeCycleCountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, cycleCount)
+}
+
+// ListCycleCounts handles GET /warehouse/cycle-counts
+// @Summary List cycle counts
+// @Description Retrieve cycle counts, optionally filtered by warehouse or status
+// @ID listCycleCounts
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param warehouse_id query int false "Only include cycle counts for this warehouse" minimum(1)
+// @Param status query string false "Only include cycle counts in this status" Enums(counting, pending_approval, posted, cancelled)
+// @Success 200 {array} model.CycleCount
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/cycle-counts [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *CycleCountHandler) ListCycleCounts(c *gin.Context) {
+	warehouseID := 0
+	if warehouseIDStr := c.Query("warehouse_id"); warehouseIDStr != "" {
+		var err error
+		warehouseID, err = strconv.Atoi(warehouseIDStr)
+		if err != nil || warehouseID < 1 {
+			c.JSON(http.StatusBadRequest, model.Error{
+				Error:   "INVALID_INPUT",
+				Message: "Invalid warehouse ID",
+				Details: "warehouse_id must be a positive integer",
+			})
+			return
+		}
+	}
+
+	cycleCounts, err := h.service.ListCycleCounts(warehouseID, model.CycleCountStatus(c.Query("status")))
+	if err != nil {
+		writeCycleCountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cycleCounts)
+}
+
+// GetCycleCount handles GET /warehouse/cycle-counts/{cycleCountId}
+// @Summary Get cycle count by ID
+// @Description Retrieve a cycle count with its expected and counted quantities and variances
+// @ID getCycleCount
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param cycleCountId path int true "Unique identifier for the cycle count" minimum(1)
+// @Success 200 {object} model.CycleCount
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/cycle-counts/{cycleCountId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *CycleCountHandler) GetCycleCount(c *gin.Context) {
+	cycleCountID, ok := parseCycleCountID(c)
+	if !ok {
+		return
+	}
+
+	cycleCount, err := h.service.GetCycleCount(cycleCountID)
+	if err != nil {
+		writeCycleCountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cycleCount)
+}
+
+// RecordCounts handles POST /warehouse/cycle-counts/{cycleCountId}/counts
+// @Summary Record counts
+// @Description Record counted quantities for lines of an open cycle count. Serialized products are counted by serial number. Recounting a line replaces its earlier count.
+// @ID recordCounts
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param cycleCountId path int true "Unique identifier for the cycle count" minimum(1)
+// @Param request body model.RecordCountsRequest true "Counted quantities"
+// @Success 200 {object} model.CycleCount
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/cycle-counts/{cycleCountId}/counts [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *CycleCountHandler) RecordCounts(c *gin.Context) {
+	cycleCountID, ok := parseCycleCountID(c)
+	if !ok {
+		return
+	}
+
+	var req model.RecordCountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	cycleCount, err := h.service.RecordCounts(cycleCountID, &req)
+	if err != nil {
+		writeCycleCountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cycleCount)
+}
+
+// SubmitCycleCount handles POST /warehouse/cycle-counts/{cycleCountId}/submit
+// @Summary Submit cycle count
+// @Description Close counting and compute variances. Counts whose variances are all within the approval threshold are posted to the ledger straight away; the rest wait for approval.
+// @ID submitCycleCount
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param cycleCountId path int true "Unique identifier for the cycle count" minimum(1)
+// @Param request body model.CycleCountActionRequest true "Who is submitting the count"
+// @Success 200 {object} model.CycleCount
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/cycle-counts/{cycleCountId}/submit [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *CycleCountHandler) SubmitCycleCount(c *gin.Context) {
+	h.act(c, h.service.SubmitCycleCount)
+}
+
+// ApproveCycleCount handles POST /warehouse/cycle-counts/{cycleCountId}/approve
+// @Summary Approve cycle count
+// @Description Approve a cycle count with variances above the threshold and post its adjustments to the ledger. The approver must not be who submitted the count.
+// @ID approveCycleCount
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param cycleCountId path int true "Unique identifier for the cycle count" minimum(1)
+// @Param request body model.CycleCountActionRequest true "Who is approving the count"
+// @Success 200 {object} model.CycleCount
+// @Failure 400 {object} model.Error
+// @Failure 403 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/cycle-counts/{cycleCountId}/approve [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *CycleCountHandler) ApproveCycleCount(c *gin.Context) {
+	h.act(c, h.service.ApproveCycleCount)
+}
+
+// CancelCycleCount handles POST /warehouse/cycle-counts/{cycleCountId}/cancel
+// @Summary Cancel cycle count
+// @Description Abandon a cycle count that has not been posted. No adjustments are made.
+// @ID cancelCycleCount
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param cycleCountId path int true "Unique identifier for the cycle count" minimum(1)
+// @Param request body model.CycleCountActionRequest true "Who is cancelling the count"
+// @Success 200 {object} model.CycleCount
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/cycle-counts/{cycleCountId}/cancel [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *CycleCountHandler) CancelCycleCount(c *gin.Context) {
+	h.act(c, h.service.CancelCycleCount)
+}
+
+// act runs a status change that only needs the cycle count ID and an actor
+func (h *CycleCountHandler) act(c *gin.Context, action func(cycleCountID int, actor string) (*model.CycleCount, error)) {
+	cycleCountID, ok := parseCycleCountID(c)
+	if !ok {
+		return
+	}
+
+	var req model.CycleCountActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	cycleCount, err := action(cycleCountID, req.Actor)
+	if err != nil {
+		writeCycleCountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cycleCount)
+}
+
+// parseCycleCountID reads the cycleCountId path parameter, writing a 400 if it is invalid
+func parseCycleCountID(c *gin.Context) (int, bool) {
+	cycleCountID, err := strconv.Atoi(c.Param("cycleCountId"))
+	if err != nil || cycleCountID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid cycle count ID",
+			Details: "Cycle count ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return cycleCountID, true
+}
+
+// writeCycleCountError maps cycle count errors to responses
+func writeCycleCountError(c *gin.Context, err error) {
+	if writeSerialStockError(c, err) {
+		return
+	}
+
+	switch err {
+	case service.ErrCycleCountNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Cycle count not found",
+			Details: "No cycle count exists with the specified ID",
+		})
+	case service.ErrCycleCountState, service.ErrCountIncomplete:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Cycle count cannot be changed in its current status",
+			Details: err.Error(),
+		})
+	case service.ErrSelfApproval:
+		c.JSON(http.StatusForbidden, model.Error{
+			Error:   "SELF_APPROVAL",
+			Message: "Cycle count needs a different approver",
+			Details: err.Error(),
+		})
+	case service.ErrCountLineNotFound:
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "COUNT_MISMATCH",
+			Message: "Count does not match the cycle count",
+			Details: err.Error(),
+		})
+	case service.ErrInsufficientStock:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INSUFFICIENT_STOCK",
+			Message: "Insufficient stock",
+			Details: "The counted quantity is below the quantity reserved for orders",
+		})
+	case service.ErrInvalidCycleCount:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
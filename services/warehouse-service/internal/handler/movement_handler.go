@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+type MovementHandler struct {
+	service *service.MovementService
+}
+
+func NewMovementHandler(service *service.MovementService) *MovementHandler {
+	return &MovementHandler{service: service}
+}
+
+// ListMovements handles GET /warehouse/movements
+// @Summary List stock movements
+// @Description Retrieve the stock ledger entries for a product, oldest first
+// @ID listMovements
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param product_id query int true "Unique identifier for the product" minimum(1)
+// @Param warehouse_id query int false "Only include movements in this warehouse" minimum(1)
+// @Param type query string false "Only include movements of this type" Enums(receipt, pick, adjustment, transfer_out, transfer_in, transfer_shortage, reservation_confirm)
+// @Success 200 {array} model.StockMovement
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/movements [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *MovementHandler) ListMovements(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Query("product_id"))
+	if err != nil || productID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid product ID",
+			Details: "product_id must be a positive integer",
+		})
+		return
+	}
+
+	warehouseID := 0
+	if warehouseIDStr := c.Query("warehouse_id"); warehouseIDStr != "" {
+		warehouseID, err = strconv.Atoi(warehouseIDStr)
+		if err != nil || warehouseID < 1 {
+			c.JSON(http.StatusBadRequest, model.Error{
+				Error:   "INVALID_INPUT",
+				Message: "Invalid warehouse ID",
+				Details: "warehouse_id must be a positive integer",
+			})
+			return
+		}
+	}
+
+	movements, err := h.service.ListMovements(productID, warehouseID, model.MovementType(c.Query("type")))
+	if err == service.ErrInvalidMovementQuery {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, movements)
+}
+
+// ReconcileStock handles GET /warehouse/stock/{productId}/reconciliation
+// @Summary Reconcile stock against the ledger
+// @Description Compare a product's recorded on-hand in each warehouse with the sum of its stock movements
+// @ID reconcileStock
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param productId path int true "Unique identifier for the product" minimum(1)
+// @Success 200 {array} model.StockReconciliation
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/stock/{productId}/reconciliation [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *MovementHandler) ReconcileStock(c *gin.Context) {
+	// Parse productId from URL parameter
+	productIDStr := c.Param("productId")
+	productID, err := strconv.Atoi(productIDStr)
+	if err != nil || productID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid product ID",
+			Details: "Product ID must be a positive integer",
+		})
+		return
+	}
+
+	report, err := h.service.Reconcile(productID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,392 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+type PickHandler struct {
+	service *service.PickService
+}
+
+func NewPickHandler(service *service.PickService) *PickHandler {
+	return &PickHandler{service: service}
+}
+
+// CreatePickLists handles POST /warehouse/pick-lists
+// @Summary Generate pick lists for an order
+// @Description Create one pick list per warehouse for the stock committed to an order that is not on a pick list yet, including backordered units allocated since
+// @ID createPickLists
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param request body model.CreatePickListsRequest true "Order to pick"
+// @Success 201 {array} model.PickList
+// @Failure 400 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/pick-lists [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PickHandler) CreatePickLists(c *gin.Context) {
+	var req model.CreatePickListsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	pickLists, err := h.service.CreatePickLists(req.OrderRef)
+	if err != nil {
+		writePickError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, pickLists)
+}
+
+// ListPickLists handles GET /warehouse/pick-lists
+// @Summary List pick lists
+// @Description Retrieve pick lists, optionally filtered by order, warehouse or status
+// @ID listPickLists
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param order_ref query string false "Only include pick lists for this order"
+// @Param warehouse_id query int false "Only include pick lists for this warehouse" minimum(1)
+// @Param status query string false "Only include pick lists in this status" Enums(open, picked, packed, shipped)
+// @Success 200 {array} model.PickList
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/pick-lists [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PickHandler) ListPickLists(c *gin.Context) {
+	warehouseID := 0
+	if warehouseIDStr := c.Query("warehouse_id"); warehouseIDStr != "" {
+		var err error
+		warehouseID, err = strconv.Atoi(warehouseIDStr)
+		if err != nil || warehouseID < 1 {
+			c.JSON(http.StatusBadRequest, model.Error{
+				Error:   "INVALID_INPUT",
+				Message: "Invalid warehouse ID",
+				Details: "warehouse_id must be a positive integer",
+			})
+			return
+		}
+	}
+
+	pickLists, err := h.service.ListPickLists(c.Query("order_ref"), warehouseID, model.PickListStatus(c.Query("status")))
+	if err != nil {
+		writePickError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pickLists)
+}
+
+// GetPickList handles GET /warehouse/pick-lists/{pickListId}
+// @Summary Get pick list by ID
+// @Description Retrieve a pick list with the cartons packed from it
+// @ID getPickList
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param pickListId path int true "Unique identifier for the pick list" minimum(1)
+// @Success 200 {object} model.PickList
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/pick-lists/{pickListId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PickHandler) GetPickList(c *gin.Context) {
+	pickListID, ok := parsePickListID(c)
+	if !ok {
+		return
+	}
+
+	pickList, err := h.service.GetPickList(pickListID)
+	if err != nil {
+		writePickError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pickList)
+}
+
+// ConfirmPick handles POST /warehouse/pick-lists/{pickListId}/confirm
+// @Summary Confirm pick list
+// @Description Record that every line of an open pick list was pulled from the shelves. Each line is written to the stock ledger as a pick movement of zero units, since the stock was already taken off hand when its reservation was confirmed.
+// @ID confirmPick
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param pickListId path int true "Unique identifier for the pick list" minimum(1)
+// @Param request body model.ConfirmPickRequest true "Who picked the list"
+// @Success 200 {object} model.PickList
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/pick-lists/{pickListId}/confirm [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PickHandler) ConfirmPick(c *gin.Context) {
+	pickListID, ok := parsePickListID(c)
+	if !ok {
+		return
+	}
+
+	var req model.ConfirmPickRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	pickList, err := h.service.ConfirmPick(pickListID, req.Actor)
+	if err != nil {
+		writePickError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pickList)
+}
+
+// PackCarton handles POST /warehouse/pick-lists/{pickListId}/cartons
+// @Summary Pack carton
+// @Description Record a carton packed from a picked pick list. Serialized units are identified by serial number. The list becomes packed once every unit is in a carton.
+// @ID packCarton
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param pickListId path int true "Unique identifier for the pick list" minimum(1)
+// @Param request body model.PackCartonRequest true "Carton details and contents"
+// @Success 201 {object} model.PickList
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/pick-lists/{pickListId}/cartons [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PickHandler) PackCarton(c *gin.Context) {
+	pickListID, ok := parsePickListID(c)
+	if !ok {
+		return
+	}
+
+	var req model.PackCartonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	pickList, err := h.service.PackCarton(pickListID, &req)
+	if err != nil {
+		writePickError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, pickList)
+}
+
+// CreatePickBatch handles POST /warehouse/pick-batches
+// @Summary Create pick batch
+// @Description Group open pick lists in a warehouse into one batch with totals by product and lot. Without pick list IDs every open, unbatched list in the warehouse is included.
+// @ID createPickBatch
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param request body model.CreatePickBatchRequest true "Warehouse and pick lists to batch"
+// @Success 201 {object} model.PickBatch
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/pick-batches [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PickHandler) CreatePickBatch(c *gin.Context) {
+	var req model.CreatePickBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	batch, err := h.service.CreatePickBatch(&req)
+	if err != nil {
+		writePickError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, batch)
+}
+
+// GetPickBatch handles GET /warehouse/pick-batches/{batchId}
+// @Summary Get pick batch by ID
+// @Description Retrieve a pick batch with its totals by product and lot
+// @ID getPickBatch
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param batchId path int true "Unique identifier for the pick batch" minimum(1)
+// @Success 200 {object} model.PickBatch
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/pick-batches/{batchId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PickHandler) GetPickBatch(c *gin.Context) {
+	batchID, ok := parsePickBatchID(c)
+	if !ok {
+		return
+	}
+
+	batch, err := h.service.GetPickBatch(batchID)
+	if err != nil {
+		writePickError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
+
+// ConfirmBatchPick handles POST /warehouse/pick-batches/{batchId}/confirm
+// @Summary Confirm pick batch
+// @Description Record that every open pick list in a batch was pulled from the shelves
+// @ID confirmBatchPick
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param batchId path int true "Unique identifier for the pick batch" minimum(1)
+// @Param request body model.ConfirmPickRequest true "Who picked the batch"
+// @Success 200 {array} model.PickList
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/pick-batches/{batchId}/confirm [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *PickHandler) ConfirmBatchPick(c *gin.Context) {
+	batchID, ok := parsePickBatchID(c)
+	if !ok {
+		return
+	}
+
+	var req model.ConfirmPickRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	pickLists, err := h.service.ConfirmBatchPick(batchID, req.Actor)
+	if err != nil {
+		writePickError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pickLists)
+}
+
+// parsePickListID reads the pickListId path parameter, writing a 400 if it is invalid
+func parsePickListID(c *gin.Context) (int, bool) {
+	pickListID, err := strconv.Atoi(c.Param("pickListId"))
+	if err != nil || pickListID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid pick list ID",
+			Details: "Pick list ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return pickListID, true
+}
+
+// parsePickBatchID reads the batchId path parameter, writing a 400 if it is invalid
+func parsePickBatchID(c *gin.Context) (int, bool) {
+	batchID, err := strconv.Atoi(c.Param("batchId"))
+	if err != nil || batchID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid batch ID",
+			Details: "Batch ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return batchID, true
+}
+
+// writePickError maps picking and packing errors to responses
+func writePickError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrPickListNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Pick list not found",
+			Details: "No pick list exists with the specified ID",
+		})
+	case service.ErrPickBatchNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Pick batch not found",
+			Details: "No pick batch exists with the specified ID",
+		})
+	case service.ErrPickListState:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Pick list cannot be changed in its current status",
+			Details: err.Error(),
+		})
+	case service.ErrNothingToPick:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "NOTHING_TO_PICK",
+			Message: "Nothing to pick",
+			Details: err.Error(),
+		})
+	case service.ErrCartonMismatch:
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "CARTON_MISMATCH",
+			Message: "Carton does not match the pick list",
+			Details: err.Error(),
+		})
+	case service.ErrInvalidPick:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+type ReplenishmentHandler struct {
+	service *service.ReplenishmentService
+}
+
+func NewReplenishmentHandler(service *service.ReplenishmentService) *ReplenishmentHandler {
+	return &ReplenishmentHandler{service: service}
+}
+
+// SetReorderPolicy handles PUT /warehouse/stock/{productId}/reorder-policy
+// @Summary Set reorder policy
+// @Description Create or replace the reorder point, reorder quantity and supplier lead time of a product
+// @ID setReorderPolicy
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param productId path int true "Unique identifier for the product" minimum(1)
+// @Param request body model.SetReorderPolicyRequest true "Reorder thresholds"
+// @Success 200 {object} model.ReorderPolicy
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/stock/{productId}/reorder-policy [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *ReplenishmentHandler) SetReorderPolicy(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var req model.SetReorderPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	policy, err := h.service.SetReorderPolicy(productID, &req)
+	if err != nil {
+		writeReplenishmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// GetReorderPolicy handles GET /warehouse/stock/{productId}/reorder-policy
+// @Summary Get reorder policy
+// @Description Retrieve the reorder thresholds of a product
+// @ID getReorderPolicy
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param productId path int true "Unique identifier for the product" minimum(1)
+// @Success 200 {object} model.ReorderPolicy
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/stock/{productId}/reorder-policy [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *ReplenishmentHandler) GetReorderPolicy(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	policy, err := h.service.GetReorderPolicy(productID)
+	if err != nil {
+		writeReplenishmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeleteReorderPolicy handles DELETE /warehouse/stock/{productId}/reorder-policy
+// @Summary Delete reorder policy
+// @Description Stop replenishment tracking and low-stock alerts for a product
+// @ID deleteReorderPolicy
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param productId path int true "Unique identifier for the product" minimum(1)
+// @Success 204 "Reorder policy deleted"
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/stock/{productId}/reorder-policy [delete]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *ReplenishmentHandler) DeleteReorderPolicy(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteReorderPolicy(productID); err != nil {
+		writeReplenishmentError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetReplenishmentReport handles GET /warehouse/replenishment
+// @Summary Get replenishment report
+// @Description Compare stock with reorder policies and recent sales velocity, suggesting how much to order for each product
+// @ID getReplenishmentReport
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.ReplenishmentReport
+// @Failure 500 {object} model.Error
+// @Router /warehouse/replenishment [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *ReplenishmentHandler) GetReplenishmentReport(c *gin.Context) {
+	report, err := h.service.GetReplenishmentReport()
+	if err != nil {
+		writeReplenishmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// parseProductID reads the productId path parameter, writing a 400 if it is invalid
+func parseProductID(c *gin.Context) (int, bool) {
+	productID, err := strconv.Atoi(c.Param("productId"))
+	if err != nil || productID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid product ID",
+			Details: "Product ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return productID, true
+}
+
+// writeReplenishmentError maps replenishment errors to responses
+func writeReplenishmentError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrReorderPolicyNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Reorder policy not found",
+			Details: "No reorder policy exists for the specified product",
+		})
+	case service.ErrInvalidReorderPolicy:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
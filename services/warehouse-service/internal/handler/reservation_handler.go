@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+type ReservationHandler struct {
+	service *service.ReservationService
+}
+
+func NewReservationHandler(service *service.ReservationService) *ReservationHandler {
+	return &ReservationHandler{service: service}
+}
+
+// CreateReservation handles POST /warehouse/reservations
+// @Summary Reserve stock for an order
+// @Description Hold stock for every line of an order, or none of them if any line is short
+// @ID createReservation
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param request body model.ReserveRequest true "Reservation details"
+// @Success 201 {object} model.Reservation
+// @Failure 400 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/reservations [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *ReservationHandler) CreateReservation(c *gin.Context) {
+	var req model.ReserveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	reservation, err := h.service.Reserve(&req)
+	if err == service.ErrInsufficientStock {
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INSUFFICIENT_STOCK",
+			Message: "Insufficient stock",
+			Details: "Not enough available stock to reserve every line",
+		})
+		return
+	} else if err == service.ErrReservationExists {
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "CONFLICT",
+			Message: "Reservation already exists",
+			Details: err.Error(),
+		})
+		return
+	} else if err == service.ErrInvalidReservation || err == service.ErrInvalidAllocation {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, reservation)
+}
+
+// GetReservation handles GET /warehouse/reservations/{reservationId}
+// @Summary Get reservation by ID
+// @Description Retrieve a stock reservation and its status
+// @ID getReservation
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param reservationId path int true "Unique identifier for the reservation" minimum(1)
+// @Success 200 {object} model.Reservation
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/reservations/{reservationId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *ReservationHandler) GetReservation(c *gin.Context) {
+	reservationID, ok := parseReservationID(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.service.GetReservation(reservationID)
+	if err != nil {
+		writeReservationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// ConfirmReservation handles POST /warehouse/reservations/{reservationId}/confirm
+// @Summary Confirm reservation
+// @Description Commit held stock to the order, removing it from on-hand
+// @ID confirmReservation
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param reservationId path int true "Unique identifier for the reservation" minimum(1)
+// @Success 200 {object} model.Reservation
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 410 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/reservations/{reservationId}/confirm [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *ReservationHandler) ConfirmReservation(c *gin.Context) {
+	reservationID, ok := parseReservationID(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.service.ConfirmReservation(reservationID)
+	if err != nil {
+		writeReservationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// ReleaseReservation handles POST /warehouse/reservations/{reservationId}/release
+// @Summary Release reservation
+// @Description Return held stock to the available pool
+// @ID releaseReservation
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param reservationId path int true "Unique identifier for the reservation" minimum(1)
+// @Success 200 {object} model.Reservation
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/reservations/{reservationId}/release [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *ReservationHandler) ReleaseReservation(c *gin.Context) {
+	reservationID, ok := parseReservationID(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.service.ReleaseReservation(reservationID)
+	if err != nil {
+		writeReservationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// parseReservationID reads the reservationId path parameter, writing a 400 if it is invalid
+func parseReservationID(c *gin.Context) (int, bool) {
+	reservationID, err := strconv.Atoi(c.Param("reservationId"))
+	if err != nil || reservationID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid reservation ID",
+			Details: "Reservation ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return reservationID, true
+}
+
+// writeReservationError maps reservation errors to responses
+func writeReservationError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrReservationNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Reservation not found",
+			Details: "No reservation exists with the specified ID",
+		})
+	case service.ErrReservationClosed:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Reservation is not active",
+			Details: err.Error(),
+		})
+	case service.ErrReservationExpired:
+		c.JSON(http.StatusGone, model.Error{
+			Error:   "EXPIRED",
+			Message: "Reservation has expired",
+			Details: "The held stock has been returned to the available pool",
+		})
+	case service.ErrSerialShortage:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "SERIAL_CONFLICT",
+			Message: "Serial number conflict",
+			Details: err.Error(),
+		})
+	case service.ErrInvalidReservation:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+type SerialHandler struct {
+	service *service.SerialService
+}
+
+func NewSerialHandler(service *service.SerialService) *SerialHandler {
+	return &SerialHandler{service: service}
+}
+
+// SetSerialization handles PUT /warehouse/stock/{productId}/serialization
+// @Summary Set product serialization
+// @Description Turn serial number tracking on or off for a product. Receipts and adjustments of serialized products must list one serial number per unit.
+// @ID setSerialization
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param productId path int true "Unique identifier for the product" minimum(1)
+// @Param request body model.SetSerializationRequest true "Serialization flag"
+// @Success 200 {object} model.SerializationSetting
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/stock/{productId}/serialization [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *SerialHandler) SetSerialization(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var req model.SetSerializationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	setting, err := h.service.SetSerialization(productID, *req.Serialized)
+	if err != nil {
+		writeSerialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+// GetSerialization handles GET /warehouse/stock/{productId}/serialization
+// @Summary Get product serialization
+// @Description Report whether a product is tracked by serial number
+// @ID getSerialization
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param productId path int true "Unique identifier for the product" minimum(1)
+// @Success 200 {object} model.SerializationSetting
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/stock/{productId}/serialization [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *SerialHandler) GetSerialization(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	setting, err := h.service.GetSerialization(productID)
+	if err != nil {
+		writeSerialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+// ListSerials handles GET /warehouse/stock/{productId}/serials
+// @Summary List serial numbers
+// @Description Retrieve the serialized units of a product
+// @ID listSerials
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param productId path int true "Unique identifier for the product" minimum(1)
+// @Param warehouse_id query int false "Only include units in this warehouse" minimum(1)
+// @Param status query string false "Only include units in this status" Enums(in_stock, in_transit, sold, removed)
+// @Success 200 {array} model.SerialNumber
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/stock/{productId}/serials [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *SerialHandler) ListSerials(c *gin.Context) {
+	productID, ok := parseProductID(c)
+	if !ok {
+		return
+	}
+
+	warehouseID := 0
+	if warehouseIDStr := c.Query("warehouse_id"); warehouseIDStr != "" {
+		var err error
+		warehouseID, err = strconv.Atoi(warehouseIDStr)
+		if err != nil || warehouseID < 1 {
+			c.JSON(http.StatusBadRequest, model.Error{
+				Error:   "INVALID_INPUT",
+				Message: "Invalid warehouse ID",
+				Details: "warehouse_id must be a positive integer",
+			})
+			return
+		}
+	}
+
+	serials, err := h.service.ListSerials(productID, warehouseID, model.SerialStatus(c.Query("status")))
+	if err != nil {
+		writeSerialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, serials)
+}
+
+// GetSerial handles GET /warehouse/serials/{serialNumber}
+// @Summary Look up serial number
+// @Description Find a serialized unit, and the order and customer it was sold to, for warranty and returns
+// @ID getSerial
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param serialNumber path string true "Serial number of the unit"
+// @Success 200 {object} model.SerialNumber
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/serials/{serialNumber} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *SerialHandler) GetSerial(c *gin.Context) {
+	serial, err := h.service.GetSerial(c.Param("serialNumber"))
+	if err != nil {
+		writeSerialError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, serial)
+}
+
+// writeSerialError maps serial number errors to responses
+func writeSerialError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrSerialNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Serial number not found",
+			Details: "No unit has been received with the specified serial number",
+		})
+	case service.ErrInvalidSerial:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+type ShipmentHandler struct {
+	service *service.ShipmentService
+}
+
+func NewShipmentHandler(service *service.ShipmentService) *ShipmentHandler {
+	return &ShipmentHandler{service: service}
+}
+
+// CreateShipment handles POST /warehouse/shipments
+// @Summary Create shipment
+// @Description Book a packed pick list with the carrier and record its tracking number
+// @ID createShipment
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param request body model.CreateShipmentRequest true "Pick list to ship"
+// @Success 201 {object} model.Shipment
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/shipments [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *ShipmentHandler) CreateShipment(c *gin.Context) {
+	var req model.CreateShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	shipment, err := h.service.CreateShipment(&req)
+	if err != nil {
+		writeShipmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, shipment)
+}
+
+// ListShipments handles GET /warehouse/shipments
+// @Summary List shipments
+// @Description Retrieve shipments, optionally filtered by order or status
+// @ID listShipments
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param order_ref query string false "Only include shipments for this order"
+// @Param status query string false "Only include shipments in this status" Enums(label_created, in_transit, out_for_delivery, delivered, exception)
+// @Success 200 {array} model.Shipment
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/shipments [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *ShipmentHandler) ListShipments(c *gin.Context) {
+	shipments, err := h.service.ListShipments(c.Query("order_ref"), model.ShipmentStatus(c.Query("status")))
+	if err != nil {
+		writeShipmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shipments)
+}
+
+// GetShipment handles GET /warehouse/shipments/{shipmentId}
+// @Summary Get shipment by ID
+// @Description Retrieve a shipment with its cartons and tracking history
+// @ID getShipment
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param shipmentId path int true "Unique identifier for the shipment" minimum(1)
+// @Success 200 {object} model.Shipment
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/shipments/{shipmentId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *ShipmentHandler) GetShipment(c *gin.Context) {
+	shipmentID, ok := parseShipmentID(c)
+	if !ok {
+		return
+	}
+
+	shipment, err := h.service.GetShipment(shipmentID)
+	if err != nil {
+		writeShipmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
+
+// RefreshShipment handles POST /warehouse/shipments/{shipmentId}/tracking
+// @Summary Refresh shipment tracking
+// @Description Pull the latest tracking events for a shipment from the carrier
+// @ID refreshShipment
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param shipmentId path int true "Unique identifier for the shipment" minimum(1)
+// @Success 200 {object} model.Shipment
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/shipments/{shipmentId}/tracking [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *ShipmentHandler) RefreshShipment(c *gin.Context) {
+	shipmentID, ok := parseShipmentID(c)
+	if !ok {
+		return
+	}
+
+	shipment, err := h.service.RefreshShipment(shipmentID)
+	if err != nil {
+		writeShipmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shipment)
+}
+
+// parseShipmentID reads the shipmentId path parameter, writing a 400 if it is invalid
+func parseShipmentID(c *gin.Context) (int, bool) {
+	shipmentID, err := strconv.Atoi(c.Param("shipmentId"))
+	if err != nil || shipmentID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid shipment ID",
+			Details: "Shipment ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return shipmentID, true
+}
+
+// writeShipmentError maps shipment errors to responses
+func writeShipmentError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrShipmentNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Shipment not found",
+			Details: "No shipment exists with the specified ID",
+		})
+	case service.ErrPickListNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Pick list not found",
+			Details: "No pick list exists with the specified ID",
+		})
+	case service.ErrPickListState:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Pick list is not ready to ship",
+			Details: "Only fully packed pick lists can be shipped, and only once",
+		})
+	case service.ErrShipmentRejected:
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "CARRIER_REJECTED",
+			Message: "Carrier rejected the shipment",
+			Details: err.Error(),
+		})
+	case service.ErrInvalidShipment:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
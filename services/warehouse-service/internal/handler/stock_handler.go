@@ -0,0 +1,427 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+type StockHandler struct {
+	service *service.StockService
+}
+
+func NewStockHandler(service *service.StockService) *StockHandler {
+	return &StockHandler{service: service}
+}
+
+// GetStock handles GET /warehouse/stock/{productId}
+// @Summary Get product stock
+// @Description Retrieve a product's on-hand quantity in each warehouse
+// @ID getStock
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param productId path int true "Unique identifier for the product" minimum(1)
+// @Success 200 {object} model.ProductStock
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/stock/{productId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *StockHandler) GetStock(c *gin.Context) {
+	// Parse productId from URL parameter
+	productIDStr := c.Param("productId")
+	productID, err := strconv.Atoi(productIDStr)
+	if err != nil || productID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid product ID",
+			Details: "Product ID must be a positive integer",
+		})
+		return
+	}
+
+	stock, err := h.service.GetStock(productID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, stock)
+}
+
+// AdjustStock handles POST /warehouse/stock/{productId}/adjustments
+// @Summary Adjust product stock
+// @Description Add or remove on-hand quantity of a product in a warehouse
+// @ID adjustStock
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param productId path int true "Unique identifier for the product" minimum(1)
+// @Param request body model.StockAdjustmentRequest true "Adjustment details"
+// @Success 200 {object} model.StockLevel
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/stock/{productId}/adjustments [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *StockHandler) AdjustStock(c *gin.Context) {
+	// Parse productId from URL parameter
+	productIDStr := c.Param("productId")
+	productID, err := strconv.Atoi(productIDStr)
+	if err != nil || productID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid product ID",
+			Details: "Product ID must be a positive integer",
+		})
+		return
+	}
+
+	// Parse request body
+	var req model.StockAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	level, err := h.service.AdjustStock(productID, &req)
+	if writeSerialStockError(c, err) {
+		return
+	}
+	if err == service.ErrInsufficientStock {
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INSUFFICIENT_STOCK",
+			Message: "Insufficient stock",
+			Details: "Adjustment would leave less stock than is reserved",
+		})
+		return
+	} else if err == service.ErrLotNotFound {
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Lot not found",
+			Details: "The product has no lot with the specified number in this warehouse",
+		})
+		return
+	} else if err == service.ErrInvalidStock {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, level)
+}
+
+// SetStock handles PUT /warehouse/stock/{productId}/warehouses/{warehouseId}
+// @Summary Set product stock
+// @Description Replace the on-hand quantity of a product in a warehouse with a counted value
+// @ID setStock
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param productId path int true "Unique identifier for the product" minimum(1)
+// @Param warehouseId path int true "Unique identifier for the warehouse" minimum(1)
+// @Param request body model.SetStockRequest true "Counted quantity"
+// @Success 200 {object} model.StockLevel
+// @Failure 400 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/stock/{productId}/warehouses/{warehouseId} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *StockHandler) SetStock(c *gin.Context) {
+	// Parse productId and warehouseId from URL parameters
+	productID, err := strconv.Atoi(c.Param("productId"))
+	if err != nil || productID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid product ID",
+			Details: "Product ID must be a positive integer",
+		})
+		return
+	}
+	warehouseID, err := strconv.Atoi(c.Param("warehouseId"))
+	if err != nil || warehouseID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid warehouse ID",
+			Details: "Warehouse ID must be a positive integer",
+		})
+		return
+	}
+
+	// Parse request body
+	var req model.SetStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	level, err := h.service.SetStock(productID, warehouseID, &req)
+	if writeSerialStockError(c, err) {
+		return
+	}
+	if err == service.ErrInsufficientStock {
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INSUFFICIENT_STOCK",
+			Message: "Insufficient stock",
+			Details: "Counted quantity is below the quantity held by reservations",
+		})
+		return
+	} else if err == service.ErrInvalidStock {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, level)
+}
+
+// ReceiveStock handles POST /warehouse/stock/{productId}/receipts
+// @Summary Receive stock
+// @Description Record goods received into a warehouse, optionally into a lot with an expiry date
+// @ID receiveStock
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param productId path int true "Unique identifier for the product" minimum(1)
+// @Param request body model.StockReceiptRequest true "Receipt details"
+// @Success 200 {object} model.StockLevel
+// @Failure 400 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/stock/{productId}/receipts [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *StockHandler) ReceiveStock(c *gin.Context) {
+	// Parse productId from URL parameter
+	productIDStr := c.Param("productId")
+	productID, err := strconv.Atoi(productIDStr)
+	if err != nil || productID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid product ID",
+			Details: "Product ID must be a positive integer",
+		})
+		return
+	}
+
+	// Parse request body
+	var req model.StockReceiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	level, err := h.service.ReceiveStock(productID, &req)
+	if writeSerialStockError(c, err) {
+		return
+	}
+	if err == service.ErrLotNotFound {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Missing expiry date",
+			Details: "An expiry date is required when receiving a new lot",
+		})
+		return
+	} else if err == service.ErrLotMismatch {
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "CONFLICT",
+			Message: "Lot expiry mismatch",
+			Details: err.Error(),
+		})
+		return
+	} else if err == service.ErrInvalidStock {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, level)
+}
+
+// TransferStock handles POST /warehouse/stock/{productId}/transfers
+// @Summary Transfer stock
+// @Description Immediately move on-hand quantity of a product between two warehouses. Serialized products must list the serial number of every unit moved, each in stock at the source warehouse.
+// @ID transferStock
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param productId path int true "Unique identifier for the product" minimum(1)
+// @Param request body model.StockTransferRequest true "Transfer details"
+// @Success 200 {object} model.ProductStock
+// @Failure 400 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/stock/{productId}/transfers [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *StockHandler) TransferStock(c *gin.Context) {
+	// Parse productId from URL parameter
+	productIDStr := c.Param("productId")
+	productID, err := strconv.Atoi(productIDStr)
+	if err != nil || productID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid product ID",
+			Details: "Product ID must be a positive integer",
+		})
+		return
+	}
+
+	// Parse request body
+	var req model.StockTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	stock, err := h.service.TransferStock(productID, &req)
+	if writeSerialStockError(c, err) {
+		return
+	}
+	if err == service.ErrInsufficientStock {
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INSUFFICIENT_STOCK",
+			Message: "Insufficient stock",
+			Details: "Not enough unreserved stock in the source warehouse",
+		})
+		return
+	} else if err == service.ErrInvalidStock {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, stock)
+}
+
+// ListExpiringLots handles GET /warehouse/lots/expiring
+// @Summary List expiring lots
+// @Description Retrieve lots with stock on hand that expire within the given number of days, soonest first. Already expired lots are included.
+// @ID listExpiringLots
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param days query int false "Expiry horizon in days (default 30)" minimum(0) maximum(3650)
+// @Success 200 {array} model.ExpiringLot
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/lots/expiring [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *StockHandler) ListExpiringLots(c *gin.Context) {
+	days := 30
+	if daysStr := c.Query("days"); daysStr != "" {
+		var err error
+		days, err = strconv.Atoi(daysStr)
+		if err != nil || days < 0 || days > 3650 {
+			c.JSON(http.StatusBadRequest, model.Error{
+				Error:   "INVALID_INPUT",
+				Message: "Invalid expiry horizon",
+				Details: "days must be an integer between 0 and 3650",
+			})
+			return
+		}
+	}
+
+	lots, err := h.service.ListExpiringLots(days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, lots)
+}
+
+// writeSerialStockError writes the response for serial number errors from
+// stock changes, reporting whether err was one of them
+func writeSerialStockError(c *gin.Context, err error) bool {
+	switch err {
+	case service.ErrSerialsRequired:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "SERIALS_REQUIRED",
+			Message: "Serial numbers required",
+			Details: err.Error(),
+		})
+	case service.ErrSerialExists, service.ErrSerialUnavailable:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "SERIAL_CONFLICT",
+			Message: "Serial number conflict",
+			Details: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+type TransferHandler struct {
+	service *service.TransferService
+}
+
+func NewTransferHandler(service *service.TransferService) *TransferHandler {
+	return &TransferHandler{service: service}
+}
+
+// CreateTransfer handles POST /warehouse/transfers
+// @Summary Request a stock transfer
+// @Description Open a transfer order to move stock between two warehouses. No stock moves until it ships.
+// @ID createTransfer
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param request body model.CreateTransferRequest true "Transfer details"
+// @Success 201 {object} model.TransferOrder
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/transfers [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *TransferHandler) CreateTransfer(c *gin.Context) {
+	var req model.CreateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	transfer, err := h.service.CreateTransfer(&req)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+// ListTransfers handles GET /warehouse/transfers
+// @Summary List stock transfers
+// @Description Retrieve transfer orders, optionally filtered by status
+// @ID listTransfers
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param status query string false "Only include transfers in this status" Enums(requested, in_transit, partially_received, received, closed, cancelled)
+// @Success 200 {array} model.TransferOrder
+// @Failure 400 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/transfers [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *TransferHandler) ListTransfers(c *gin.Context) {
+	transfers, err := h.service.ListTransfers(model.TransferStatus(c.Query("status")))
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+// GetTransfer handles GET /warehouse/transfers/{transferId}
+// @Summary Get transfer by ID
+// @Description Retrieve a transfer order with its received quantities and shortages
+// @ID getTransfer
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param transferId path int true "Unique identifier for the transfer" minimum(1)
+// @Success 200 {object} model.TransferOrder
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/transfers/{transferId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *TransferHandler) GetTransfer(c *gin.Context) {
+	transferID, ok := parseTransferID(c)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.service.GetTransfer(transferID)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// ShipTransfer handles POST /warehouse/transfers/{transferId}/ship
+// @Summary Ship transfer
+// @Description Remove the stock from the source warehouse and hold it as in-transit at the destination
+// @ID shipTransfer
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param transferId path int true "Unique identifier for the transfer" minimum(1)
+// @Param request body model.TransferActionRequest true "Who is shipping the transfer"
+// @Success 200 {object} model.TransferOrder
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/transfers/{transferId}/ship [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *TransferHandler) ShipTransfer(c *gin.Context) {
+	transferID, ok := parseTransferID(c)
+	if !ok {
+		return
+	}
+
+	var req model.TransferActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	transfer, err := h.service.ShipTransfer(transferID, req.Actor)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// ReceiveTransfer handles POST /warehouse/transfers/{transferId}/receipts
+// @Summary Receive transfer
+// @Description Record stock arriving at the destination, moving it from in-transit to on-hand. May be called once per partial delivery.
+// @ID receiveTransfer
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param transferId path int true "Unique identifier for the transfer" minimum(1)
+// @Param request body model.TransferReceiptRequest true "Quantities received"
+// @Success 200 {object} model.TransferOrder
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 422 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/transfers/{transferId}/receipts [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *TransferHandler) ReceiveTransfer(c *gin.Context) {
+	transferID, ok := parseTransferID(c)
+	if !ok {
+		return
+	}
+
+	var req model.TransferReceiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	transfer, err := h.service.ReceiveTransfer(transferID, &req)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// CloseTransfer handles POST /warehouse/transfers/{transferId}/close
+// @Summary Close transfer
+// @Description Finalize a shipped transfer, writing off anything not received as a shortage
+// @ID closeTransfer
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param transferId path int true "Unique identifier for the transfer" minimum(1)
+// @Param request body model.TransferActionRequest true "Who is closing the transfer"
+// @Success 200 {object} model.TransferOrder
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/transfers/{transferId}/close [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *TransferHandler) CloseTransfer(c *gin.Context) {
+	transferID, ok := parseTransferID(c)
+	if !ok {
+		return
+	}
+
+	var req model.TransferActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	transfer, err := h.service.CloseTransfer(transferID, req.Actor)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// CancelTransfer handles POST /warehouse/transfers/{transferId}/cancel
+// @Summary Cancel transfer
+// @Description Abandon a transfer that has not shipped
+// @ID cancelTransfer
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param transferId path int true "Unique identifier for the transfer" minimum(1)
+// @Success 200 {object} model.TransferOrder
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/transfers/{transferId}/cancel [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *TransferHandler) CancelTransfer(c *gin.Context) {
+	transferID, ok := parseTransferID(c)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.service.CancelTransfer(transferID)
+	if err != nil {
+		writeTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// parseTransferID reads the transferId path parameter, writing a 400 if it is invalid
+func parseTransferID(c *gin.Context) (int, bool) {
+	transferID, err := strconv.Atoi(c.Param("transferId"))
+	if err != nil || transferID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid transfer ID",
+			Details: "Transfer ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return transferID, true
+}
+
+// writeTransferError maps transfer errors to responses
+func writeTransferError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrTransferNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Transfer not found",
+			Details: "No transfer exists with the specified ID",
+		})
+	case service.ErrTransferState:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INVALID_STATE",
+			Message: "Transfer cannot be changed in its current status",
+			Details: err.Error(),
+		})
+	case service.ErrInsufficientStock:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "INSUFFICIENT_STOCK",
+			Message: "Insufficient stock",
+			Details: "The source warehouse does not have enough unreserved stock to ship",
+		})
+	case service.ErrTransferReceipt:
+		c.JSON(http.StatusUnprocessableEntity, model.Error{
+			Error:   "RECEIPT_MISMATCH",
+			Message: "Receipt does not match the transfer",
+			Details: err.Error(),
+		})
+	case service.ErrInvalidTransfer:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
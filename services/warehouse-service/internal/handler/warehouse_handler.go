@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/service"
+)
+
+type WarehouseHandler struct {
+	service *service.WarehouseService
+}
+
+func NewWarehouseHandler(service *service.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{service: service}
+}
+
+// CreateWarehouse handles POST /warehouse/sites
+// @Summary Register a warehouse
+// @Description Register a fulfillment site with its location and handling cost
+// @ID createWarehouse
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param request body model.Warehouse true "Warehouse details"
+// @Success 201 {object} model.Warehouse
+// @Failure 400 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/sites [post]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *WarehouseHandler) CreateWarehouse(c *gin.Context) {
+	var req model.Warehouse
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	warehouse, err := h.service.CreateWarehouse(&req)
+	if err != nil {
+		writeWarehouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, warehouse)
+}
+
+// ListWarehouses handles GET /warehouse/sites
+// @Summary List warehouses
+// @Description Retrieve every registered fulfillment site
+// @ID listWarehouses
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Success 200 {array} model.Warehouse
+// @Failure 500 {object} model.Error
+// @Router /warehouse/sites [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *WarehouseHandler) ListWarehouses(c *gin.Context) {
+	warehouses, err := h.service.ListWarehouses()
+	if err != nil {
+		writeWarehouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, warehouses)
+}
+
+// GetWarehouse handles GET /warehouse/sites/{warehouseId}
+// @Summary Get warehouse by ID
+// @Description Retrieve a fulfillment site
+// @ID getWarehouse
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param warehouseId path int true "Unique identifier for the warehouse" minimum(1)
+// @Success 200 {object} model.Warehouse
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/sites/{warehouseId} [get]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *WarehouseHandler) GetWarehouse(c *gin.Context) {
+	warehouseID, ok := parseWarehouseID(c)
+	if !ok {
+		return
+	}
+
+	warehouse, err := h.service.GetWarehouse(warehouseID)
+	if err != nil {
+		writeWarehouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, warehouse)
+}
+
+// UpdateWarehouse handles PUT /warehouse/sites/{warehouseId}
+// @Summary Update warehouse
+// @Description Replace a fulfillment site's details
+// @ID updateWarehouse
+// @Tags Warehouse
+// @Accept json
+// @Produce json
+// @Param warehouseId path int true "Unique identifier for the warehouse" minimum(1)
+// @Param request body model.Warehouse true "Warehouse details"
+// @Success 204 "Warehouse updated successfully"
+// @Failure 400 {object} model.Error
+// @Failure 404 {object} model.Error
+// @Failure 409 {object} model.Error
+// @Failure 500 {object} model.Error
+// @Router /warehouse/sites/{warehouseId} [put]
+// @Security ApiKeyAuth
+// @Security BearerAuth
+func (h *WarehouseHandler) UpdateWarehouse(c *gin.Context) {
+	warehouseID, ok := parseWarehouseID(c)
+	if !ok {
+		return
+	}
+
+	var req model.Warehouse
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.service.UpdateWarehouse(warehouseID, &req); err != nil {
+		writeWarehouseError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// parseWarehouseID reads the warehouseId path parameter, writing a 400 if it is invalid
+func parseWarehouseID(c *gin.Context) (int, bool) {
+	warehouseID, err := strconv.Atoi(c.Param("warehouseId"))
+	if err != nil || warehouseID < 1 {
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid warehouse ID",
+			Details: "Warehouse ID must be a positive integer",
+		})
+		return 0, false
+	}
+	return warehouseID, true
+}
+
+// writeWarehouseError maps warehouse registry errors to responses
+func writeWarehouseError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrWarehouseNotFound:
+		c.JSON(http.StatusNotFound, model.Error{
+			Error:   "NOT_FOUND",
+			Message: "Warehouse not found",
+			Details: "No warehouse exists with the specified ID",
+		})
+	case service.ErrWarehouseExists:
+		c.JSON(http.StatusConflict, model.Error{
+			Error:   "CONFLICT",
+			Message: "Warehouse already exists",
+			Details: err.Error(),
+		})
+	case service.ErrInvalidWarehouse:
+		c.JSON(http.StatusBadRequest, model.Error{
+			Error:   "INVALID_INPUT",
+			Message: "Invalid input data",
+			Details: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.Error{
+			Error:   "INTERNAL_ERROR",
+			Message: "Internal server error",
+			Details: err.Error(),
+		})
+	}
+}
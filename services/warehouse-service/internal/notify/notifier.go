@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"log"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+// Notifier publishes low-stock alerts to buyers. Implementations must be
+// safe for concurrent use.
+type Notifier interface {
+	NotifyLowStock(alert *model.LowStockAlert) error
+}
+
+// LogNotifier writes alerts to the service log
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// NotifyLowStock logs the alert
+func (n *LogNotifier) NotifyLowStock(alert *model.LowStockAlert) error {
+	log.Printf("Low stock: product %d has %d available and %d in transit (reorder point %d), suggest ordering %d",
+		alert.ProductID, alert.Available, alert.InTransit, alert.ReorderPoint, alert.SuggestedQuantity)
+	return nil
+}
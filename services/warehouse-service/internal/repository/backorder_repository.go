@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrBackorderNotFound = errors.New("backorder not found")
+	ErrBackorderClosed   = errors.New("backorder is no longer outstanding")
+)
+
+type BackorderRepository struct {
+	backorders      map[int]*model.Backorder
+	mu              sync.RWMutex
+	nextBackorderID int
+}
+
+func NewBackorderRepository() *BackorderRepository {
+	return &BackorderRepository{
+		backorders:      make(map[int]*model.Backorder),
+		nextBackorderID: 1,
+	}
+}
+
+// Create stores a new backorder and assigns its ID
+func (r *BackorderRepository) Create(backorder *model.Backorder) (*model.Backorder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyBackorder(backorder)
+	stored.BackorderID = r.nextBackorderID
+	r.backorders[stored.BackorderID] = stored
+	r.nextBackorderID++
+
+	return copyBackorder(stored), nil
+}
+
+// GetByID retrieves a backorder by its ID
+func (r *BackorderRepository) GetByID(backorderID int) (*model.Backorder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	backorder, exists := r.backorders[backorderID]
+	if !exists {
+		return nil, ErrBackorderNotFound
+	}
+
+	return copyBackorder(backorder), nil
+}
+
+// List returns backorders in ID order, which is the order they are
+// allocated in. Zero values of orderRef, productID and status match everything.
+func (r *BackorderRepository) List(orderRef string, productID int, status model.BackorderStatus) ([]*model.Backorder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	backorders := []*model.Backorder{}
+	for _, backorder := range r.backorders {
+		if orderRef != "" && backorder.OrderRef != orderRef {
+			continue
+		}
+		if productID > 0 && backorder.ProductID != productID {
+			continue
+		}
+		if status != "" && backorder.Status != status {
+			continue
+		}
+		backorders = append(backorders, copyBackorder(backorder))
+	}
+
+	sort.Slice(backorders, func(i, j int) bool { return backorders[i].BackorderID < backorders[j].BackorderID })
+	return backorders, nil
+}
+
+// ListOutstanding returns a product's backorders still waiting for stock, oldest first
+func (r *BackorderRepository) ListOutstanding(productID int) ([]*model.Backorder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	backorders := []*model.Backorder{}
+	for _, backorder := range r.backorders {
+		if backorder.ProductID == productID && outstanding(backorder) {
+			backorders = append(backorders, copyBackorder(backorder))
+		}
+	}
+
+	sort.Slice(backorders, func(i, j int) bool { return backorders[i].BackorderID < backorders[j].BackorderID })
+	return backorders, nil
+}
+
+// RecordAllocation adds stock committed to a backorder. Once fully
+// allocated, it is expected to ship now, or on its release date if later.
+func (r *BackorderRepository) RecordAllocation(backorderID int, lines []model.ReservationLine) (*model.Backorder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	backorder, exists := r.backorders[backorderID]
+	if !exists {
+		return nil, ErrBackorderNotFound
+	}
+	if !outstanding(backorder) {
+		return nil, ErrBackorderClosed
+	}
+
+	for _, line := range lines {
+		backorder.Allocated += line.Quantity
+		backorder.Allocations = append(backorder.Allocations, line)
+	}
+
+	backorder.Status = model.BackorderPartiallyAllocated
+	if backorder.Allocated >= backorder.Quantity {
+		now := time.Now().UTC()
+		backorder.Status = model.BackorderAllocated
+		backorder.AllocatedAt = &now
+		backorder.ExpectedShipDate = now
+		if backorder.ReleaseDate != nil && backorder.ReleaseDate.After(now) {
+			backorder.ExpectedShipDate = *backorder.ReleaseDate
+		}
+	}
+	return copyBackorder(backorder), nil
+}
+
+// Cancel stops a backorder from receiving further stock
+func (r *BackorderRepository) Cancel(backorderID int) (*model.Backorder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	backorder, exists := r.backorders[backorderID]
+	if !exists {
+		return nil, ErrBackorderNotFound
+	}
+	if !outstanding(backorder) {
+		return nil, ErrBackorderClosed
+	}
+
+	backorder.Status = model.BackorderCancelled
+	return copyBackorder(backorder), nil
+}
+
+// outstanding reports whether a backorder is still waiting for stock
+func outstanding(backorder *model.Backorder) bool {
+	return backorder.Status == model.BackorderOpen || backorder.Status == model.BackorderPartiallyAllocated
+}
+
+// copyBackorder returns a copy of a backorder that shares no slices with the original
+func copyBackorder(backorder *model.Backorder) *model.Backorder {
+	backorderCopy := *backorder
+	backorderCopy.Allocations = make([]model.ReservationLine, len(backorder.Allocations))
+	for i, line := range backorder.Allocations {
+		line.Lots = append([]model.LotQuantity(nil), line.Lots...)
+		line.SerialNumbers = append([]string(nil), line.SerialNumbers...)
+		backorderCopy.Allocations[i] = line
+	}
+	return &backorderCopy
+}
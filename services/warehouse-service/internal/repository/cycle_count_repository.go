@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrCycleCountNotFound = errors.New("cycle count not found")
+	ErrCycleCountConflict = errors.New("cycle count is not in the expected status")
+	ErrCountLineNotFound  = errors.New("product or lot is not on the cycle count")
+	ErrCountIncomplete    = errors.New("cycle count has lines that were not counted")
+)
+
+type CycleCountRepository struct {
+	cycleCounts      map[int]*model.CycleCount
+	mu               sync.RWMutex
+	nextCycleCountID int
+}
+
+func NewCycleCountRepository() *CycleCountRepository {
+	return &CycleCountRepository{
+		cycleCounts:      make(map[int]*model.CycleCount),
+		nextCycleCountID: 1,
+	}
+}
+
+// Create stores a new cycle count and assigns its ID
+func (r *CycleCountRepository) Create(cycleCount *model.CycleCount) (*model.CycleCount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyCycleCount(cycleCount)
+	stored.CycleCountID = r.nextCycleCountID
+	r.cycleCounts[stored.CycleCountID] = stored
+	r.nextCycleCountID++
+
+	return copyCycleCount(stored), nil
+}
+
+// GetByID retrieves a cycle count by its ID
+func (r *CycleCountRepository) GetByID(cycleCountID int) (*model.CycleCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cycleCount, exists := r.cycleCounts[cycleCountID]
+	if !exists {
+		return nil, ErrCycleCountNotFound
+	}
+
+	return copyCycleCount(cycleCount), nil
+}
+
+// List returns cycle counts in ID order. Empty filters match every cycle count.
+func (r *CycleCountRepository) List(warehouseID int, status model.CycleCountStatus) ([]*model.CycleCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cycleCounts := []*model.CycleCount{}
+	for _, cycleCount := range r.cycleCounts {
+		if (warehouseID == 0 || cycleCount.WarehouseID == warehouseID) && (status == "" || cycleCount.Status == status) {
+			cycleCounts = append(cycleCounts, copyCycleCount(cycleCount))
+		}
+	}
+
+	sort.Slice(cycleCounts, func(i, j int) bool { return cycleCounts[i].CycleCountID < cycleCounts[j].CycleCountID })
+	return cycleCounts, nil
+}
+
+// RecordCounts stores counted quantities on a cycle count that is still
+// counting. It fails without changing anything if any line is not on the count.
+func (r *CycleCountRepository) RecordCounts(cycleCountID int, counts []model.CycleCountLine, actor string) (*model.CycleCount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cycleCount, exists := r.cycleCounts[cycleCountID]
+	if !exists {
+		return nil, ErrCycleCountNotFound
+	}
+	if cycleCount.Status != model.CycleCountCounting {
+		return nil, ErrCycleCountConflict
+	}
+
+	indexes := make([]int, len(counts))
+	for i, count := range counts {
+		indexes[i] = -1
+		for j, line := range cycleCount.Lines {
+			if line.ProductID == count.ProductID && line.LotNumber == count.LotNumber {
+				indexes[i] = j
+				break
+			}
+		}
+		if indexes[i] < 0 {
+			return nil, ErrCountLineNotFound
+		}
+	}
+
+	for i, count := range counts {
+		line := &cycleCount.Lines[indexes[i]]
+		counted := *count.Counted
+		line.Counted = &counted
+		line.CountedSerials = append([]string(nil), count.CountedSerials...)
+		line.CountedBy = actor
+	}
+
+	return copyCycleCount(cycleCount), nil
+}
+
+// Submit closes counting, working out each line's variance from the
+// snapshot. Lines whose variance exceeds threshold units need approval.
+func (r *CycleCountRepository) Submit(cycleCountID int, threshold int, actor string, at time.Time) (*model.CycleCount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cycleCount, exists := r.cycleCounts[cycleCountID]
+	if !exists {
+		return nil, ErrCycleCountNotFound
+	}
+	if cycleCount.Status != model.CycleCountCounting {
+		return nil, ErrCycleCountConflict
+	}
+	for _, line := range cycleCount.Lines {
+		if line.Counted == nil {
+			return nil, ErrCountIncomplete
+		}
+	}
+
+	for i := range cycleCount.Lines {
+		line := &cycleCount.Lines[i]
+		line.Variance = *line.Counted - line.Expected
+		line.RequiresApproval = line.Variance > threshold || -line.Variance > threshold
+	}
+	cycleCount.Status = model.CycleCountPendingApproval
+	cycleCount.SubmittedBy = actor
+	cycleCount.SubmittedAt = &at
+
+	return copyCycleCount(cycleCount), nil
+}
+
+// Close moves a cycle count from one status to posted or cancelled,
+// failing if it is no longer in the expected status
+func (r *CycleCountRepository) Close(cycleCountID int, from, to model.CycleCountStatus, approvedBy string, at time.Time) (*model.CycleCount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cycleCount, exists := r.cycleCounts[cycleCountID]
+	if !exists {
+		return nil, ErrCycleCountNotFound
+	}
+	if cycleCount.Status != from {
+		return nil, ErrCycleCountConflict
+	}
+
+	cycleCount.Status = to
+	cycleCount.ApprovedBy = approvedBy
+	cycleCount.ClosedAt = &at
+	return copyCycleCount(cycleCount), nil
+}
+
+// copyCycleCount returns a copy of a cycle count that shares no slices with the original
+func copyCycleCount(cycleCount *model.CycleCount) *model.CycleCount {
+	cycleCountCopy := *cycleCount
+	cycleCountCopy.Lines = make([]model.CycleCountLine, len(cycleCount.Lines))
+	for i, line := range cycleCount.Lines {
+		if line.Counted != nil {
+			counted := *line.Counted
+			line.Counted = &counted
+		}
+		line.ExpectedSerials = append([]string(nil), line.ExpectedSerials...)
+		line.CountedSerials = append([]string(nil), line.CountedSerials...)
+		cycleCountCopy.Lines[i] = line
+	}
+	return &cycleCountCopy
+}
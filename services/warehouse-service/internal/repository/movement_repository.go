@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+// MovementRepository is an append-only stock ledger. Entries are never
+// updated or removed once written.
+type MovementRepository struct {
+	movements      []model.StockMovement
+	mu             sync.RWMutex
+	nextMovementID int
+}
+
+func NewMovementRepository() *MovementRepository {
+	return &MovementRepository{
+		movements:      []model.StockMovement{},
+		nextMovementID: 1,
+	}
+}
+
+// Append writes movements to the ledger in order and assigns their IDs.
+// Movements without a state change on-hand stock.
+func (r *MovementRepository) Append(movements ...model.StockMovement) ([]model.StockMovement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	appended := make([]model.StockMovement, 0, len(movements))
+	for _, movement := range movements {
+		movement.MovementID = r.nextMovementID
+		if movement.State == "" {
+			movement.State = model.StockOnHand
+		}
+		r.nextMovementID++
+		r.movements = append(r.movements, movement)
+		appended = append(appended, movement)
+	}
+
+	return appended, nil
+}
+
+// ListByProduct returns a product's movements in the order they were recorded.
+// A warehouseID of zero matches every warehouse.
+func (r *MovementRepository) ListByProduct(productID, warehouseID int) ([]model.StockMovement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	movements := []model.StockMovement{}
+	for _, movement := range r.movements {
+		if movement.ProductID != productID {
+			continue
+		}
+		if warehouseID > 0 && movement.WarehouseID != warehouseID {
+			continue
+		}
+		movements = append(movements, movement)
+	}
+
+	return movements, nil
+}
+
+// Balances sums a product's movements in one stock state per warehouse
+func (r *MovementRepository) Balances(productID int, state model.StockState) (map[int]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	balances := make(map[int]int)
+	for _, movement := range r.movements {
+		if movement.ProductID == productID && movement.State == state {
+			balances[movement.WarehouseID] += movement.Quantity
+		}
+	}
+
+	return balances, nil
+}
+
+// OutflowSince sums, per product, the units removed by movements of one
+// type recorded at or after since
+func (r *MovementRepository) OutflowSince(movementType model.MovementType, since time.Time) (map[int]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	outflow := make(map[int]int)
+	for _, movement := range r.movements {
+		if movement.Type == movementType && movement.Quantity < 0 && !movement.CreatedAt.Before(since) {
+			outflow[movement.ProductID] -= movement.Quantity
+		}
+	}
+
+	return outflow, nil
+}
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrPickListNotFound  = errors.New("pick list not found")
+	ErrPickBatchNotFound = errors.New("pick batch not found")
+	ErrPickListConflict  = errors.New("pick list is not in the expected status")
+	ErrCartonMismatch    = errors.New("carton contents do not match the units left to pack")
+)
+
+type PickListRepository struct {
+	pickLists      map[int]*model.PickList
+	batches        map[int]*model.PickBatch
+	mu             sync.RWMutex
+	nextPickListID int
+	nextBatchID    int
+	nextCartonID   int
+}
+
+func NewPickListRepository() *PickListRepository {
+	return &PickListRepository{
+		pickLists:      make(map[int]*model.PickList),
+		batches:        make(map[int]*model.PickBatch),
+		nextPickListID: 1,
+		nextBatchID:    1,
+		nextCartonID:   1,
+	}
+}
+
+// Create stores a new pick list and assigns its ID
+func (r *PickListRepository) Create(pickList *model.PickList) (*model.PickList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyPickList(pickList)
+	stored.PickListID = r.nextPickListID
+	r.pickLists[stored.PickListID] = stored
+	r.nextPickListID++
+
+	return copyPickList(stored), nil
+}
+
+// GetByID retrieves a pick list by its ID
+func (r *PickListRepository) GetByID(pickListID int) (*model.PickList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pickList, exists := r.pickLists[pickListID]
+	if !exists {
+		return nil, ErrPickListNotFound
+	}
+
+	return copyPickList(pickList), nil
+}
+
+// List returns pick lists in ID order. Empty filters match every pick list.
+func (r *PickListRepository) List(orderRef string, warehouseID int, status model.PickListStatus) ([]*model.PickList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pickLists := []*model.PickList{}
+	for _, pickList := range r.pickLists {
+		if (orderRef == "" || pickList.OrderRef == orderRef) &&
+			(warehouseID == 0 || pickList.WarehouseID == warehouseID) &&
+			(status == "" || pickList.Status == status) {
+			pickLists = append(pickLists, copyPickList(pickList))
+		}
+	}
+
+	sort.Slice(pickLists, func(i, j int) bool { return pickLists[i].PickListID < pickLists[j].PickListID })
+	return pickLists, nil
+}
+
+// ConfirmPick marks an open pick list as picked
+func (r *PickListRepository) ConfirmPick(pickListID int, actor string, at time.Time) (*model.PickList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pickList, exists := r.pickLists[pickListID]
+	if !exists {
+		return nil, ErrPickListNotFound
+	}
+	if pickList.Status != model.PickListOpen {
+		return nil, ErrPickListConflict
+	}
+
+	confirmPick(pickList, actor, at)
+	return copyPickList(pickList), nil
+}
+
+// UpdateStatus moves a pick list from one status to another, failing if it
+// is no longer in the expected status
+func (r *PickListRepository) UpdateStatus(pickListID int, from, to model.PickListStatus) (*model.PickList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pickList, exists := r.pickLists[pickListID]
+	if !exists {
+		return nil, ErrPickListNotFound
+	}
+	if pickList.Status != from {
+		return nil, ErrPickListConflict
+	}
+
+	pickList.Status = to
+	return copyPickList(pickList), nil
+}
+
+// AddCarton records a packed carton on a picked pick list. Every unit must
+// be on the list and not already packed; serialized units are matched by
+// serial number. The list becomes packed once nothing is left to pack.
+func (r *PickListRepository) AddCarton(pickListID int, carton *model.Carton) (*model.PickList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pickList, exists := r.pickLists[pickListID]
+	if !exists {
+		return nil, ErrPickListNotFound
+	}
+	if pickList.Status != model.PickListPicked {
+		return nil, ErrPickListConflict
+	}
+
+	// Work out what is still waiting to be packed
+	remaining := make(map[int]int)
+	unpackedSerials := make(map[string]int)
+	for _, line := range pickList.Lines {
+		remaining[line.ProductID] += line.Quantity
+		for _, serialNumber := range line.SerialNumbers {
+			unpackedSerials[serialNumber] = line.ProductID
+		}
+	}
+	for _, packed := range pickList.Cartons {
+		for _, item := range packed.Items {
+			remaining[item.ProductID] -= item.Quantity
+			for _, serialNumber := range item.SerialNumbers {
+				delete(unpackedSerials, serialNumber)
+			}
+		}
+	}
+	serialized := make(map[int]bool)
+	for _, line := range pickList.Lines {
+		if len(line.SerialNumbers) > 0 {
+			serialized[line.ProductID] = true
+		}
+	}
+
+	for _, item := range carton.Items {
+		if item.Quantity < 1 || item.Quantity > remaining[item.ProductID] {
+			return nil, ErrCartonMismatch
+		}
+		remaining[item.ProductID] -= item.Quantity
+
+		if !serialized[item.ProductID] {
+			if len(item.SerialNumbers) > 0 {
+				return nil, ErrCartonMismatch
+			}
+			continue
+		}
+		if len(item.SerialNumbers) != item.Quantity {
+			return nil, ErrCartonMismatch
+		}
+		for _, serialNumber := range item.SerialNumbers {
+			if productID, unpacked := unpackedSerials[serialNumber]; !unpacked || productID != item.ProductID {
+				return nil, ErrCartonMismatch
+			}
+			delete(unpackedSerials, serialNumber)
+		}
+	}
+
+	stored := copyCarton(*carton)
+	stored.CartonID = r.nextCartonID
+	r.nextCartonID++
+	pickList.Cartons = append(pickList.Cartons, stored)
+
+	packed := true
+	for _, quantity := range remaining {
+		if quantity > 0 {
+			packed = false
+		}
+	}
+	if packed {
+		pickList.Status = model.PickListPacked
+	}
+
+	return copyPickList(pickList), nil
+}
+
+// CreateBatch groups open, unbatched pick lists from one warehouse into a
+// batch. It fails without changing anything if any list does not qualify.
+func (r *PickListRepository) CreateBatch(warehouseID int, pickListIDs []int, at time.Time) (*model.PickBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, pickListID := range pickListIDs {
+		pickList, exists := r.pickLists[pickListID]
+		if !exists {
+			return nil, ErrPickListNotFound
+		}
+		if pickList.Status != model.PickListOpen || pickList.BatchID != 0 || pickList.WarehouseID != warehouseID {
+			return nil, ErrPickListConflict
+		}
+	}
+
+	batch := &model.PickBatch{
+		BatchID:     r.nextBatchID,
+		WarehouseID: warehouseID,
+		PickListIDs: append([]int(nil), pickListIDs...),
+		CreatedAt:   at,
+	}
+	r.nextBatchID++
+	sort.Ints(batch.PickListIDs)
+
+	// Total the units by product and lot so the batch is picked in one walk
+	type lotKey struct {
+		productID int
+		lotNumber string
+	}
+	index := make(map[lotKey]int)
+	for _, pickListID := range batch.PickListIDs {
+		pickList := r.pickLists[pickListID]
+		pickList.BatchID = batch.BatchID
+		for _, line := range pickList.Lines {
+			key := lotKey{productID: line.ProductID, lotNumber: line.LotNumber}
+			i, exists := index[key]
+			if !exists {
+				i = len(batch.Lines)
+				index[key] = i
+				batch.Lines = append(batch.Lines, model.PickLine{ProductID: line.ProductID, LotNumber: line.LotNumber})
+			}
+			batch.Lines[i].Quantity += line.Quantity
+			batch.Lines[i].SerialNumbers = append(batch.Lines[i].SerialNumbers, line.SerialNumbers...)
+		}
+	}
+	sort.Slice(batch.Lines, func(i, j int) bool {
+		if batch.Lines[i].ProductID != batch.Lines[j].ProductID {
+			return batch.Lines[i].ProductID < batch.Lines[j].ProductID
+		}
+		return batch.Lines[i].LotNumber < batch.Lines[j].LotNumber
+	})
+
+	r.batches[batch.BatchID] = batch
+	return copyPickBatch(batch), nil
+}
+
+// GetBatch retrieves a pick batch by its ID
+func (r *PickListRepository) GetBatch(batchID int) (*model.PickBatch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	batch, exists := r.batches[batchID]
+	if !exists {
+		return nil, ErrPickBatchNotFound
+	}
+
+	return copyPickBatch(batch), nil
+}
+
+// ConfirmBatchPick marks every open pick list in a batch as picked and
+// returns the lists in the batch
+func (r *PickListRepository) ConfirmBatchPick(batchID int, actor string, at time.Time) ([]*model.PickList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	batch, exists := r.batches[batchID]
+	if !exists {
+		return nil, ErrPickBatchNotFound
+	}
+
+	pickLists := make([]*model.PickList, 0, len(batch.PickListIDs))
+	for _, pickListID := range batch.PickListIDs {
+		pickList := r.pickLists[pickListID]
+		if pickList.Status == model.PickListOpen {
+			confirmPick(pickList, actor, at)
+		}
+		pickLists = append(pickLists, copyPickList(pickList))
+	}
+
+	return pickLists, nil
+}
+
+// confirmPick stamps a pick list as picked
+func confirmPick(pickList *model.PickList, actor string, at time.Time) {
+	pickList.Status = model.PickListPicked
+	pickList.PickedBy = actor
+	pickList.PickedAt = &at
+}
+
+// copyPickList returns a copy of a pick list that shares no slices with the original
+func copyPickList(pickList *model.PickList) *model.PickList {
+	pickListCopy := *pickList
+	pickListCopy.Lines = copyPickLines(pickList.Lines)
+	pickListCopy.Cartons = make([]model.Carton, len(pickList.Cartons))
+	for i, carton := range pickList.Cartons {
+		pickListCopy.Cartons[i] = copyCarton(carton)
+	}
+	if pickList.ShipTo != nil {
+		shipTo := *pickList.ShipTo
+		pickListCopy.ShipTo = &shipTo
+	}
+	return &pickListCopy
+}
+
+// copyPickBatch returns a copy of a pick batch that shares no slices with the original
+func copyPickBatch(batch *model.PickBatch) *model.PickBatch {
+	batchCopy := *batch
+	batchCopy.PickListIDs = append([]int(nil), batch.PickListIDs...)
+	batchCopy.Lines = copyPickLines(batch.Lines)
+	return &batchCopy
+}
+
+func copyPickLines(lines []model.PickLine) []model.PickLine {
+	linesCopy := make([]model.PickLine, len(lines))
+	for i, line := range lines {
+		line.SerialNumbers = append([]string(nil), line.SerialNumbers...)
+		linesCopy[i] = line
+	}
+	return linesCopy
+}
+
+func copyCarton(carton model.Carton) model.Carton {
+	items := make([]model.PackedItem, len(carton.Items))
+	for i, item := range carton.Items {
+		item.SerialNumbers = append([]string(nil), item.SerialNumbers...)
+		items[i] = item
+	}
+	carton.Items = items
+	return carton
+}
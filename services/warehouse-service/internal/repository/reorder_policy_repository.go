@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrReorderPolicyNotFound = errors.New("reorder policy not found")
+)
+
+type ReorderPolicyRepository struct {
+	policies map[int]*model.ReorderPolicy
+	mu       sync.RWMutex
+}
+
+func NewReorderPolicyRepository() *ReorderPolicyRepository {
+	return &ReorderPolicyRepository{
+		policies: make(map[int]*model.ReorderPolicy),
+	}
+}
+
+// Put creates or replaces the reorder policy of a product
+func (r *ReorderPolicyRepository) Put(policy *model.ReorderPolicy) (*model.ReorderPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	policyCopy := *policy
+	r.policies[policy.ProductID] = &policyCopy
+
+	result := policyCopy
+	return &result, nil
+}
+
+// GetByProductID retrieves the reorder policy of a product
+func (r *ReorderPolicyRepository) GetByProductID(productID int) (*model.ReorderPolicy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	policy, exists := r.policies[productID]
+	if !exists {
+		return nil, ErrReorderPolicyNotFound
+	}
+
+	policyCopy := *policy
+	return &policyCopy, nil
+}
+
+// List returns every reorder policy in product ID order
+func (r *ReorderPolicyRepository) List() ([]model.ReorderPolicy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	policies := make([]model.ReorderPolicy, 0, len(r.policies))
+	for _, policy := range r.policies {
+		policies = append(policies, *policy)
+	}
+
+	sort.Slice(policies, func(i, j int) bool { return policies[i].ProductID < policies[j].ProductID })
+	return policies, nil
+}
+
+// Delete removes the reorder policy of a product
+func (r *ReorderPolicyRepository) Delete(productID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.policies[productID]; !exists {
+		return ErrReorderPolicyNotFound
+	}
+
+	delete(r.policies, productID)
+	return nil
+}
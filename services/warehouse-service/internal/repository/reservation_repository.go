@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationExists   = errors.New("an active reservation already exists for this order")
+	ErrStatusConflict      = errors.New("reservation is not in the expected status")
+)
+
+type ReservationRepository struct {
+	reservations      map[int]*model.Reservation
+	mu                sync.RWMutex
+	nextReservationID int
+}
+
+func NewReservationRepository() *ReservationRepository {
+	return &ReservationRepository{
+		reservations:      make(map[int]*model.Reservation),
+		nextReservationID: 1,
+	}
+}
+
+// Create stores a new reservation and assigns its ID
+func (r *ReservationRepository) Create(reservation *model.Reservation) (*model.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.reservations {
+		if existing.OrderRef == reservation.OrderRef && existing.Status == model.ReservationActive {
+			return nil, ErrReservationExists
+		}
+	}
+
+	stored := copyReservation(reservation)
+	stored.ReservationID = r.nextReservationID
+	r.reservations[stored.ReservationID] = stored
+	r.nextReservationID++
+
+	return copyReservation(stored), nil
+}
+
+// GetByID retrieves a reservation by its ID
+func (r *ReservationRepository) GetByID(reservationID int) (*model.Reservation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reservation, exists := r.reservations[reservationID]
+	if !exists {
+		return nil, ErrReservationNotFound
+	}
+
+	return copyReservation(reservation), nil
+}
+
+// ListByOrderRef returns an order's reservations in a status, oldest first
+func (r *ReservationRepository) ListByOrderRef(orderRef string, status model.ReservationStatus) ([]*model.Reservation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reservations := []*model.Reservation{}
+	for _, reservation := range r.reservations {
+		if reservation.OrderRef == orderRef && reservation.Status == status {
+			reservations = append(reservations, copyReservation(reservation))
+		}
+	}
+
+	sort.Slice(reservations, func(i, j int) bool { return reservations[i].ReservationID < reservations[j].ReservationID })
+	return reservations, nil
+}
+
+// UpdateStatus moves a reservation from one status to another, failing if
+// it is no longer in the expected status
+func (r *ReservationRepository) UpdateStatus(reservationID int, from, to model.ReservationStatus) (*model.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, exists := r.reservations[reservationID]
+	if !exists {
+		return nil, ErrReservationNotFound
+	}
+	if reservation.Status != from {
+		return nil, ErrStatusConflict
+	}
+
+	reservation.Status = to
+	return copyReservation(reservation), nil
+}
+
+// SetSerialNumbers records the units picked for each line of a reservation,
+// indexed like its lines
+func (r *ReservationRepository) SetSerialNumbers(reservationID int, serialNumbers [][]string) (*model.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, exists := r.reservations[reservationID]
+	if !exists {
+		return nil, ErrReservationNotFound
+	}
+
+	for i := range reservation.Lines {
+		if i < len(serialNumbers) && len(serialNumbers[i]) > 0 {
+			reservation.Lines[i].SerialNumbers = append([]string(nil), serialNumbers[i]...)
+		}
+	}
+	return copyReservation(reservation), nil
+}
+
+// SetBackorders records the backorders created for a confirmed reservation
+func (r *ReservationRepository) SetBackorders(reservationID int, backorders []model.BackorderLine) (*model.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, exists := r.reservations[reservationID]
+	if !exists {
+		return nil, ErrReservationNotFound
+	}
+
+	reservation.Backorders = append([]model.BackorderLine(nil), backorders...)
+	return copyReservation(reservation), nil
+}
+
+// ListExpired returns active reservations whose expiry is at or before now
+func (r *ReservationRepository) ListExpired(now time.Time) ([]*model.Reservation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	expired := []*model.Reservation{}
+	for _, reservation := range r.reservations {
+		if reservation.Status == model.ReservationActive && !reservation.ExpiresAt.After(now) {
+			expired = append(expired, copyReservation(reservation))
+		}
+	}
+
+	return expired, nil
+}
+
+// copyReservation returns a copy of a reservation that shares no slices with the original
+func copyReservation(reservation *model.Reservation) *model.Reservation {
+	reservationCopy := *reservation
+	reservationCopy.Lines = make([]model.ReservationLine, len(reservation.Lines))
+	for i, line := range reservation.Lines {
+		line.Lots = append([]model.LotQuantity(nil), line.Lots...)
+		line.SerialNumbers = append([]string(nil), line.SerialNumbers...)
+		reservationCopy.Lines[i] = line
+	}
+	reservationCopy.Backorders = append([]model.BackorderLine(nil), reservation.Backorders...)
+	if reservation.Destination != nil {
+		destination := *reservation.Destination
+		reservationCopy.Destination = &destination
+	}
+	return &reservationCopy
+}
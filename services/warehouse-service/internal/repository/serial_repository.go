@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrSerialNotFound   = errors.New("serial number not found")
+	ErrSerialExists     = errors.New("serial number is already in stock")
+	ErrSerialWrongState = errors.New("serial number is not in stock at this warehouse")
+	ErrSerialShortage   = errors.New("not enough serialized units in stock")
+)
+
+type SerialRepository struct {
+	serialized map[int]*model.SerializationSetting
+	serials    map[string]*model.SerialNumber
+	mu         sync.RWMutex
+}
+
+func NewSerialRepository() *SerialRepository {
+	return &SerialRepository{
+		serialized: make(map[int]*model.SerializationSetting),
+		serials:    make(map[string]*model.SerialNumber),
+	}
+}
+
+// SetSerialized turns serial tracking on or off for a product
+func (r *SerialRepository) SetSerialized(productID int, serialized bool) (*model.SerializationSetting, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	setting := &model.SerializationSetting{
+		ProductID:  productID,
+		Serialized: serialized,
+		UpdatedAt:  time.Now().UTC(),
+	}
+	r.serialized[productID] = setting
+
+	result := *setting
+	return &result, nil
+}
+
+// GetSerialization reports whether a product is tracked by serial number.
+// Products never configured are not serialized.
+func (r *SerialRepository) GetSerialization(productID int) (*model.SerializationSetting, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	setting, exists := r.serialized[productID]
+	if !exists {
+		return &model.SerializationSetting{ProductID: productID}, nil
+	}
+
+	result := *setting
+	return &result, nil
+}
+
+// GetBySerial retrieves a serialized unit by its serial number
+func (r *SerialRepository) GetBySerial(serialNumber string) (*model.SerialNumber, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	serial, exists := r.serials[serialNumber]
+	if !exists {
+		return nil, ErrSerialNotFound
+	}
+
+	serialCopy := *serial
+	return &serialCopy, nil
+}
+
+// ListByProduct returns a product's serialized units in serial number order.
+// A warehouseID of zero and an empty status match everything.
+func (r *SerialRepository) ListByProduct(productID, warehouseID int, status model.SerialStatus) ([]model.SerialNumber, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	serials := []model.SerialNumber{}
+	for _, serial := range r.serials {
+		if serial.ProductID != productID {
+			continue
+		}
+		if warehouseID > 0 && serial.WarehouseID != warehouseID {
+			continue
+		}
+		if status != "" && serial.Status != status {
+			continue
+		}
+		serials = append(serials, *serial)
+	}
+
+	sort.Slice(serials, func(i, j int) bool { return serials[i].SerialNumber < serials[j].SerialNumber })
+	return serials, nil
+}
+
+// Register puts serialized units in stock at a warehouse, for every serial
+// number or for none of them. Units previously sold or removed may be
+// registered again, keeping the order they last went to.
+func (r *SerialRepository) Register(productID, warehouseID int, serialNumbers []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, serialNumber := range serialNumbers {
+		serial, exists := r.serials[serialNumber]
+		if !exists {
+			continue
+		}
+		if serial.ProductID != productID || serial.Status == model.SerialInStock || serial.Status == model.SerialInTransit {
+			return ErrSerialExists
+		}
+	}
+
+	now := time.Now().UTC()
+	for _, serialNumber := range serialNumbers {
+		serial, exists := r.serials[serialNumber]
+		if !exists {
+			serial = &model.SerialNumber{SerialNumber: serialNumber, ProductID: productID}
+			r.serials[serialNumber] = serial
+		}
+		serial.WarehouseID = warehouseID
+		serial.Status = model.SerialInStock
+		serial.ReceivedAt = now
+	}
+	return nil
+}
+
+// Remove takes specific in-stock units at a warehouse out of stock, for
+// every serial number or for none of them
+func (r *SerialRepository) Remove(productID, warehouseID int, serialNumbers []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, serialNumber := range serialNumbers {
+		serial, exists := r.serials[serialNumber]
+		if !exists {
+			return ErrSerialNotFound
+		}
+		if serial.ProductID != productID || serial.WarehouseID != warehouseID || serial.Status != model.SerialInStock {
+			return ErrSerialWrongState
+		}
+	}
+
+	for _, serialNumber := range serialNumbers {
+		r.serials[serialNumber].Status = model.SerialRemoved
+	}
+	return nil
+}
+
+// Move transfers specific in-stock units from one warehouse to another, for
+// every serial number or for none of them
+func (r *SerialRepository) Move(productID, fromWarehouseID, toWarehouseID int, serialNumbers []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, serialNumber := range serialNumbers {
+		serial, exists := r.serials[serialNumber]
+		if !exists {
+			return ErrSerialNotFound
+		}
+		if serial.ProductID != productID || serial.WarehouseID != fromWarehouseID || serial.Status != model.SerialInStock {
+			return ErrSerialWrongState
+		}
+	}
+
+	for _, serialNumber := range serialNumbers {
+		r.serials[serialNumber].WarehouseID = toWarehouseID
+	}
+	return nil
+}
+
+// Allocate marks quantity in-stock units at a warehouse as sold to an
+// order, oldest received first, and returns their serial numbers. If fewer
+// units are in stock none are allocated.
+func (r *SerialRepository) Allocate(productID, warehouseID, quantity int, reservation *model.Reservation) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	serials := r.oldest(productID, warehouseID, model.SerialInStock, quantity)
+	if len(serials) < quantity {
+		return nil, ErrSerialShortage
+	}
+
+	now := time.Now().UTC()
+	allocated := []string{}
+	for _, serial := range serials {
+		serial.Status = model.SerialSold
+		serial.ReservationID = reservation.ReservationID
+		serial.OrderRef = reservation.OrderRef
+		serial.CustomerID = reservation.CustomerID
+		serial.SoldAt = &now
+		allocated = append(allocated, serial.SerialNumber)
+	}
+
+	return allocated, nil
+}
+
+// Unallocate puts units allocated by Allocate back in stock, for every
+// serial number or for none of them
+func (r *SerialRepository) Unallocate(serialNumbers []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, serialNumber := range serialNumbers {
+		serial, exists := r.serials[serialNumber]
+		if !exists {
+			return ErrSerialNotFound
+		}
+		if serial.Status != model.SerialSold {
+			return ErrSerialWrongState
+		}
+	}
+
+	for _, serialNumber := range serialNumbers {
+		serial := r.serials[serialNumber]
+		serial.Status = model.SerialInStock
+		serial.ReservationID = 0
+		serial.OrderRef = ""
+		serial.CustomerID = 0
+		serial.SoldAt = nil
+	}
+	return nil
+}
+
+// Relocate moves up to quantity units of a product from one warehouse and
+// status to another, oldest received first
+func (r *SerialRepository) Relocate(productID, fromWarehouseID int, fromStatus model.SerialStatus, toWarehouseID int, toStatus model.SerialStatus, quantity int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, serial := range r.oldest(productID, fromWarehouseID, fromStatus, quantity) {
+		serial.WarehouseID = toWarehouseID
+		serial.Status = toStatus
+	}
+	return nil
+}
+
+// oldest returns up to limit units in a status at a warehouse, oldest received first. Callers must hold the lock.
+func (r *SerialRepository) oldest(productID, warehouseID int, status model.SerialStatus, limit int) []*model.SerialNumber {
+	matches := []*model.SerialNumber{}
+	for _, serial := range r.serials {
+		if serial.ProductID == productID && serial.WarehouseID == warehouseID && serial.Status == status {
+			matches = append(matches, serial)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].ReceivedAt.Equal(matches[j].ReceivedAt) {
+			return matches[i].ReceivedAt.Before(matches[j].ReceivedAt)
+		}
+		return matches[i].SerialNumber < matches[j].SerialNumber
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
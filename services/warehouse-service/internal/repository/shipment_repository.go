@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrShipmentNotFound = errors.New("shipment not found")
+)
+
+type ShipmentRepository struct {
+	shipments      map[int]*model.Shipment
+	mu             sync.RWMutex
+	nextShipmentID int
+}
+
+func NewShipmentRepository() *ShipmentRepository {
+	return &ShipmentRepository{
+		shipments:      make(map[int]*model.Shipment),
+		nextShipmentID: 1,
+	}
+}
+
+// Create stores a new shipment and assigns its ID
+func (r *ShipmentRepository) Create(shipment *model.Shipment) (*model.Shipment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyShipment(shipment)
+	stored.ShipmentID = r.nextShipmentID
+	r.shipments[stored.ShipmentID] = stored
+	r.nextShipmentID++
+
+	return copyShipment(stored), nil
+}
+
+// GetByID retrieves a shipment by its ID
+func (r *ShipmentRepository) GetByID(shipmentID int) (*model.Shipment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	shipment, exists := r.shipments[shipmentID]
+	if !exists {
+		return nil, ErrShipmentNotFound
+	}
+
+	return copyShipment(shipment), nil
+}
+
+// List returns shipments in ID order. Empty filters match every shipment.
+func (r *ShipmentRepository) List(orderRef string, status model.ShipmentStatus) ([]*model.Shipment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	shipments := []*model.Shipment{}
+	for _, shipment := range r.shipments {
+		if (orderRef == "" || shipment.OrderRef == orderRef) && (status == "" || shipment.Status == status) {
+			shipments = append(shipments, copyShipment(shipment))
+		}
+	}
+
+	sort.Slice(shipments, func(i, j int) bool { return shipments[i].ShipmentID < shipments[j].ShipmentID })
+	return shipments, nil
+}
+
+// ListUndelivered returns shipments the carrier has not yet delivered, in ID order
+func (r *ShipmentRepository) ListUndelivered() ([]*model.Shipment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	shipments := []*model.Shipment{}
+	for _, shipment := range r.shipments {
+		if shipment.Status != model.ShipmentDelivered {
+			shipments = append(shipments, copyShipment(shipment))
+		}
+	}
+
+	sort.Slice(shipments, func(i, j int) bool { return shipments[i].ShipmentID < shipments[j].ShipmentID })
+	return shipments, nil
+}
+
+// SetEvents replaces a shipment's tracking history with the carrier's and
+// takes its status from the latest event. Delivery stamps DeliveredAt.
+func (r *ShipmentRepository) SetEvents(shipmentID int, events []model.ShipmentEvent) (*model.Shipment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	shipment, exists := r.shipments[shipmentID]
+	if !exists {
+		return nil, ErrShipmentNotFound
+	}
+	if len(events) == 0 {
+		return copyShipment(shipment), nil
+	}
+
+	shipment.Events = append([]model.ShipmentEvent(nil), events...)
+	latest := events[len(events)-1]
+	shipment.Status = latest.Status
+	if latest.Status == model.ShipmentDelivered && shipment.DeliveredAt == nil {
+		deliveredAt := latest.OccurredAt
+		shipment.DeliveredAt = &deliveredAt
+	}
+
+	return copyShipment(shipment), nil
+}
+
+// copyShipment returns a copy of a shipment that shares no slices with the original
+func copyShipment(shipment *model.Shipment) *model.Shipment {
+	shipmentCopy := *shipment
+	shipmentCopy.Cartons = make([]model.Carton, len(shipment.Cartons))
+	for i, carton := range shipment.Cartons {
+		shipmentCopy.Cartons[i] = copyCarton(carton)
+	}
+	shipmentCopy.Events = append([]model.ShipmentEvent(nil), shipment.Events...)
+	return &shipmentCopy
+}
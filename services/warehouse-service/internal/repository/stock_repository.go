@@ -0,0 +1,536 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrLotNotFound       = errors.New("lot not found")
+	ErrLotMismatch       = errors.New("lot already exists with a different expiry date")
+)
+
+// stockKey identifies a product in a warehouse
+type stockKey struct {
+	productID   int
+	warehouseID int
+}
+
+// stockEntry holds the on-hand, reserved and inbound in-transit quantities
+// for a stockKey. The totals include the stock of every lot; the part not
+// covered by lots is untracked and never expires.
+type stockEntry struct {
+	onHand    int
+	reserved  int
+	inTransit int
+	lots      map[string]*lotEntry
+}
+
+// lotEntry holds the quantities of one lot within a stockEntry
+type lotEntry struct {
+	expiresAt time.Time
+	onHand    int
+	reserved  int
+	inTransit int
+}
+
+type StockRepository struct {
+	stock map[stockKey]*stockEntry
+	mu    sync.RWMutex
+}
+
+func NewStockRepository() *StockRepository {
+	return &StockRepository{
+		stock: make(map[stockKey]*stockEntry),
+	}
+}
+
+// GetByProduct retrieves the stock of a product in every warehouse that has held it
+func (r *StockRepository) GetByProduct(productID int) ([]model.StockLevel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	levels := []model.StockLevel{}
+	for key, entry := range r.stock {
+		if key.productID != productID {
+			continue
+		}
+		levels = append(levels, toStockLevel(key, entry, now))
+	}
+
+	sort.Slice(levels, func(i, j int) bool { return levels[i].WarehouseID < levels[j].WarehouseID })
+	return levels, nil
+}
+
+// AvailableByWarehouse returns the available quantity of each product,
+// keyed by warehouse ID then product ID
+func (r *StockRepository) AvailableByWarehouse(productIDs []int) (map[int]map[int]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[int]bool, len(productIDs))
+	for _, productID := range productIDs {
+		wanted[productID] = true
+	}
+
+	now := time.Now()
+	available := make(map[int]map[int]int)
+	for key, entry := range r.stock {
+		if !wanted[key.productID] {
+			continue
+		}
+		quantity := entry.available(now)
+		if quantity <= 0 {
+			continue
+		}
+		if available[key.warehouseID] == nil {
+			available[key.warehouseID] = make(map[int]int)
+		}
+		available[key.warehouseID][key.productID] = quantity
+	}
+
+	return available, nil
+}
+
+// ListExpiringLots returns lots with stock on hand that expire at or before
+// cutoff, soonest first
+func (r *StockRepository) ListExpiringLots(cutoff time.Time) ([]model.ExpiringLot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	lots := []model.ExpiringLot{}
+	for key, entry := range r.stock {
+		for lotNumber, lot := range entry.lots {
+			if lot.onHand == 0 || lot.expiresAt.After(cutoff) {
+				continue
+			}
+			lots = append(lots, model.ExpiringLot{
+				ProductID:       key.productID,
+				WarehouseID:     key.warehouseID,
+				LotNumber:       lotNumber,
+				ExpiresAt:       lot.expiresAt,
+				OnHand:          lot.onHand,
+				Reserved:        lot.reserved,
+				DaysUntilExpiry: int(lot.expiresAt.Sub(now).Hours() / 24),
+				Expired:         !lot.expiresAt.After(now),
+			})
+		}
+	}
+
+	sort.Slice(lots, func(i, j int) bool {
+		if !lots[i].ExpiresAt.Equal(lots[j].ExpiresAt) {
+			return lots[i].ExpiresAt.Before(lots[j].ExpiresAt)
+		}
+		if lots[i].ProductID != lots[j].ProductID {
+			return lots[i].ProductID < lots[j].ProductID
+		}
+		return lots[i].WarehouseID < lots[j].WarehouseID
+	})
+	return lots, nil
+}
+
+// Adjust changes the on-hand quantity of a product in a warehouse by delta.
+// An empty lotNumber adjusts untracked stock. Stock held by reservations
+// cannot be adjusted away.
+func (r *StockRepository) Adjust(productID, warehouseID int, lotNumber string, delta int) (*model.StockLevel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := stockKey{productID: productID, warehouseID: warehouseID}
+	entry := r.entry(key)
+	if lotNumber == "" {
+		onHand, reserved, _ := entry.untracked()
+		if onHand+delta < reserved {
+			return nil, ErrInsufficientStock
+		}
+	} else {
+		lot, exists := entry.lots[lotNumber]
+		if !exists {
+			return nil, ErrLotNotFound
+		}
+		if lot.onHand+delta < lot.reserved {
+			return nil, ErrInsufficientStock
+		}
+		lot.onHand += delta
+	}
+	entry.onHand += delta
+
+	level := toStockLevel(key, entry, time.Now())
+	return &level, nil
+}
+
+// Receive adds on-hand quantity of a product in a warehouse. A non-empty
+// lotNumber receives into that lot, creating it with expiresAt if needed.
+func (r *StockRepository) Receive(productID, warehouseID int, lotNumber string, expiresAt *time.Time, quantity int) (*model.StockLevel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := stockKey{productID: productID, warehouseID: warehouseID}
+	entry := r.entry(key)
+	if lotNumber != "" {
+		lot, exists := entry.lots[lotNumber]
+		switch {
+		case !exists && expiresAt == nil:
+			return nil, ErrLotNotFound
+		case exists && expiresAt != nil && !lot.expiresAt.Equal(*expiresAt):
+			return nil, ErrLotMismatch
+		case !exists:
+			lot = entry.lot(lotNumber, *expiresAt)
+		}
+		lot.onHand += quantity
+	}
+	entry.onHand += quantity
+
+	level := toStockLevel(key, entry, time.Now())
+	return &level, nil
+}
+
+// Set replaces the on-hand quantity of a product in a warehouse and
+// returns the change from the previous quantity. Lot-tracked stock is kept,
+// so the count cannot go below it.
+func (r *StockRepository) Set(productID, warehouseID, onHand int) (*model.StockLevel, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := stockKey{productID: productID, warehouseID: warehouseID}
+	entry := r.entry(key)
+	untrackedOnHand, untrackedReserved, _ := entry.untracked()
+	if onHand-(entry.onHand-untrackedOnHand) < untrackedReserved {
+		return nil, 0, ErrInsufficientStock
+	}
+	delta := onHand - entry.onHand
+	entry.onHand = onHand
+
+	level := toStockLevel(key, entry, time.Now())
+	return &level, delta, nil
+}
+
+// Transfer moves available quantity of a product from one warehouse to
+// another, first-expired lots first. Lots keep their expiry at the destination.
+func (r *StockRepository) Transfer(productID, fromWarehouseID, toWarehouseID, quantity int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	from := r.entry(stockKey{productID: productID, warehouseID: fromWarehouseID})
+	if from.available(now) < quantity {
+		return ErrInsufficientStock
+	}
+	to := r.entry(stockKey{productID: productID, warehouseID: toWarehouseID})
+
+	for _, taken := range from.take(quantity, now) {
+		to.lot(taken.LotNumber, taken.ExpiresAt).onHand += taken.Quantity
+	}
+	to.onHand += quantity
+	return nil
+}
+
+// Ship moves available stock of every line out of the source warehouse and
+// into the destination's in-transit stock, or moves none of it. Lots are
+// shipped first-expired first and keep their expiry in transit.
+func (r *StockRepository) Ship(fromWarehouseID, toWarehouseID int, lines []model.TransferLineRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	needed := make(map[int]int)
+	for _, line := range lines {
+		needed[line.ProductID] += line.Quantity
+	}
+	for productID, quantity := range needed {
+		from := r.entry(stockKey{productID: productID, warehouseID: fromWarehouseID})
+		if from.available(now) < quantity {
+			return ErrInsufficientStock
+		}
+	}
+
+	for productID, quantity := range needed {
+		from := r.entry(stockKey{productID: productID, warehouseID: fromWarehouseID})
+		to := r.entry(stockKey{productID: productID, warehouseID: toWarehouseID})
+		for _, taken := range from.take(quantity, now) {
+			to.lot(taken.LotNumber, taken.ExpiresAt).inTransit += taken.Quantity
+		}
+		to.inTransit += quantity
+	}
+	return nil
+}
+
+// ReceiveInTransit moves in-transit stock into on-hand at the receiving
+// warehouse, or writes it off when onHand is false. In-transit lots are
+// taken first-expired first, then untracked stock.
+func (r *StockRepository) ReceiveInTransit(productID, warehouseID, quantity int, onHand bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(stockKey{productID: productID, warehouseID: warehouseID})
+	if entry.inTransit < quantity {
+		return ErrInsufficientStock
+	}
+
+	remaining := quantity
+	for _, lotNumber := range entry.lotsByExpiry() {
+		lot := entry.lots[lotNumber]
+		moved := min(lot.inTransit, remaining)
+		lot.inTransit -= moved
+		if onHand {
+			lot.onHand += moved
+		}
+		remaining -= moved
+	}
+	entry.inTransit -= quantity
+	if onHand {
+		entry.onHand += quantity
+	}
+	return nil
+}
+
+// Reserve holds stock for every line or for none of them. Lines without
+// a warehouse are filled from the warehouses with available stock in
+// warehouse ID order, splitting across warehouses if needed. Within a
+// warehouse, lots are held first-expired first and expired lots are skipped.
+func (r *StockRepository) Reserve(lines []model.ReserveLineRequest) ([]model.ReservationLine, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Plan every hold before applying any, so a shortage leaves stock untouched
+	now := time.Now()
+	held := make(map[stockKey]int)
+	available := func(key stockKey) int {
+		entry, exists := r.stock[key]
+		if !exists {
+			return 0
+		}
+		return entry.available(now) - held[key]
+	}
+
+	planned := []model.ReservationLine{}
+	for _, line := range lines {
+		if line.WarehouseID > 0 {
+			key := stockKey{productID: line.ProductID, warehouseID: line.WarehouseID}
+			if available(key) < line.Quantity {
+				return nil, ErrInsufficientStock
+			}
+			held[key] += line.Quantity
+			planned = append(planned, model.ReservationLine{
+				ProductID:   line.ProductID,
+				WarehouseID: line.WarehouseID,
+				Quantity:    line.Quantity,
+			})
+			continue
+		}
+
+		remaining := line.Quantity
+		for _, key := range r.keysForProduct(line.ProductID) {
+			take := min(available(key), remaining)
+			if take <= 0 {
+				continue
+			}
+			held[key] += take
+			remaining -= take
+			planned = append(planned, model.ReservationLine{
+				ProductID:   line.ProductID,
+				WarehouseID: key.warehouseID,
+				Quantity:    take,
+			})
+			if remaining == 0 {
+				break
+			}
+		}
+		if remaining > 0 {
+			return nil, ErrInsufficientStock
+		}
+	}
+
+	for i := range planned {
+		entry := r.stock[stockKey{productID: planned[i].ProductID, warehouseID: planned[i].WarehouseID}]
+		planned[i].Lots = entry.hold(planned[i].Quantity, now)
+	}
+
+	return planned, nil
+}
+
+// Unreserve returns held stock to the available pool
+func (r *StockRepository) Unreserve(lines []model.ReservationLine) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, line := range lines {
+		entry := r.entry(stockKey{productID: line.ProductID, warehouseID: line.WarehouseID})
+		entry.reserved = max(entry.reserved-line.Quantity, 0)
+		for _, held := range line.Lots {
+			if lot, exists := entry.lots[held.LotNumber]; exists {
+				lot.reserved = max(lot.reserved-held.Quantity, 0)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Commit removes held stock from on-hand once a reservation is confirmed
+func (r *StockRepository) Commit(lines []model.ReservationLine) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, line := range lines {
+		entry := r.entry(stockKey{productID: line.ProductID, warehouseID: line.WarehouseID})
+		entry.reserved = max(entry.reserved-line.Quantity, 0)
+		entry.onHand = max(entry.onHand-line.Quantity, 0)
+		for _, held := range line.Lots {
+			if lot, exists := entry.lots[held.LotNumber]; exists {
+				lot.reserved = max(lot.reserved-held.Quantity, 0)
+				lot.onHand = max(lot.onHand-held.Quantity, 0)
+			}
+		}
+	}
+
+	return nil
+}
+
+// entry returns the stock entry for key, creating it if needed. Callers must hold the write lock.
+func (r *StockRepository) entry(key stockKey) *stockEntry {
+	entry, exists := r.stock[key]
+	if !exists {
+		entry = &stockEntry{lots: make(map[string]*lotEntry)}
+		r.stock[key] = entry
+	}
+	return entry
+}
+
+// keysForProduct lists the stock keys of a product in warehouse ID order. Callers must hold the lock.
+func (r *StockRepository) keysForProduct(productID int) []stockKey {
+	keys := []stockKey{}
+	for key := range r.stock {
+		if key.productID == productID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].warehouseID < keys[j].warehouseID })
+	return keys
+}
+
+// lot returns the named lot, creating it with expiresAt if needed
+func (e *stockEntry) lot(lotNumber string, expiresAt time.Time) *lotEntry {
+	lot, exists := e.lots[lotNumber]
+	if !exists {
+		lot = &lotEntry{expiresAt: expiresAt}
+		e.lots[lotNumber] = lot
+	}
+	return lot
+}
+
+// untracked returns the on-hand, reserved and in-transit stock not covered by any lot
+func (e *stockEntry) untracked() (onHand, reserved, inTransit int) {
+	onHand, reserved, inTransit = e.onHand, e.reserved, e.inTransit
+	for _, lot := range e.lots {
+		onHand -= lot.onHand
+		reserved -= lot.reserved
+		inTransit -= lot.inTransit
+	}
+	return onHand, reserved, inTransit
+}
+
+// available returns the unreserved on-hand stock, excluding lots expired at now
+func (e *stockEntry) available(now time.Time) int {
+	available := e.onHand - e.reserved
+	for _, lot := range e.lots {
+		if !lot.expiresAt.After(now) {
+			available -= max(lot.onHand-lot.reserved, 0)
+		}
+	}
+	return max(available, 0)
+}
+
+// lotsByExpiry returns lot numbers first-expired first
+func (e *stockEntry) lotsByExpiry() []string {
+	lotNumbers := make([]string, 0, len(e.lots))
+	for lotNumber := range e.lots {
+		lotNumbers = append(lotNumbers, lotNumber)
+	}
+	sort.Slice(lotNumbers, func(i, j int) bool {
+		a, b := e.lots[lotNumbers[i]], e.lots[lotNumbers[j]]
+		if !a.expiresAt.Equal(b.expiresAt) {
+			return a.expiresAt.Before(b.expiresAt)
+		}
+		return lotNumbers[i] < lotNumbers[j]
+	})
+	return lotNumbers
+}
+
+// allocate picks quantity from unexpired lots first-expired first, then
+// from untracked stock, calling apply for each lot used. Callers must check
+// that quantity is available.
+func (e *stockEntry) allocate(quantity int, now time.Time, apply func(lot *lotEntry, quantity int)) []model.LotQuantity {
+	picked := []model.LotQuantity{}
+	remaining := quantity
+	for _, lotNumber := range e.lotsByExpiry() {
+		lot := e.lots[lotNumber]
+		if remaining == 0 {
+			break
+		}
+		if !lot.expiresAt.After(now) {
+			continue
+		}
+		take := min(lot.onHand-lot.reserved, remaining)
+		if take <= 0 {
+			continue
+		}
+		apply(lot, take)
+		remaining -= take
+		picked = append(picked, model.LotQuantity{LotNumber: lotNumber, ExpiresAt: lot.expiresAt, Quantity: take})
+	}
+	return picked
+}
+
+// hold reserves quantity first-expired first and returns the lots held
+func (e *stockEntry) hold(quantity int, now time.Time) []model.LotQuantity {
+	lots := e.allocate(quantity, now, func(lot *lotEntry, quantity int) { lot.reserved += quantity })
+	e.reserved += quantity
+	return lots
+}
+
+// take removes quantity from on-hand first-expired first and returns the lots taken
+func (e *stockEntry) take(quantity int, now time.Time) []model.LotQuantity {
+	lots := e.allocate(quantity, now, func(lot *lotEntry, quantity int) { lot.onHand -= quantity })
+	e.onHand -= quantity
+	return lots
+}
+
+func toStockLevel(key stockKey, entry *stockEntry, now time.Time) model.StockLevel {
+	level := model.StockLevel{
+		ProductID:   key.productID,
+		WarehouseID: key.warehouseID,
+		OnHand:      entry.onHand,
+		Reserved:    entry.reserved,
+		Available:   entry.available(now),
+		InTransit:   entry.inTransit,
+	}
+	for _, lotNumber := range entry.lotsByExpiry() {
+		lot := entry.lots[lotNumber]
+		if lot.onHand == 0 && lot.inTransit == 0 {
+			continue
+		}
+		expired := !lot.expiresAt.After(now)
+		available := max(lot.onHand-lot.reserved, 0)
+		if expired {
+			available = 0
+		}
+		level.Lots = append(level.Lots, model.LotLevel{
+			LotNumber: lotNumber,
+			ExpiresAt: lot.expiresAt,
+			OnHand:    lot.onHand,
+			Reserved:  lot.reserved,
+			Available: available,
+			InTransit: lot.inTransit,
+			Expired:   expired,
+		})
+	}
+	return level
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrTransferNotFound     = errors.New("transfer not found")
+	ErrTransferConflict     = errors.New("transfer is not in the expected status")
+	ErrTransferOverReceipt  = errors.New("received quantity exceeds the quantity shipped")
+	ErrTransferLineNotFound = errors.New("product is not on the transfer")
+)
+
+type TransferRepository struct {
+	transfers      map[int]*model.TransferOrder
+	mu             sync.RWMutex
+	nextTransferID int
+}
+
+func NewTransferRepository() *TransferRepository {
+	return &TransferRepository{
+		transfers:      make(map[int]*model.TransferOrder),
+		nextTransferID: 1,
+	}
+}
+
+// Create stores a new transfer order and assigns its ID
+func (r *TransferRepository) Create(transfer *model.TransferOrder) (*model.TransferOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copyTransfer(transfer)
+	stored.TransferID = r.nextTransferID
+	r.transfers[stored.TransferID] = stored
+	r.nextTransferID++
+
+	return copyTransfer(stored), nil
+}
+
+// GetByID retrieves a transfer order by its ID
+func (r *TransferRepository) GetByID(transferID int) (*model.TransferOrder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	transfer, exists := r.transfers[transferID]
+	if !exists {
+		return nil, ErrTransferNotFound
+	}
+
+	return copyTransfer(transfer), nil
+}
+
+// List returns transfer orders in ID order. An empty status matches every transfer.
+func (r *TransferRepository) List(status model.TransferStatus) ([]*model.TransferOrder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	transfers := []*model.TransferOrder{}
+	for _, transfer := range r.transfers {
+		if status == "" || transfer.Status == status {
+			transfers = append(transfers, copyTransfer(transfer))
+		}
+	}
+
+	sort.Slice(transfers, func(i, j int) bool { return transfers[i].TransferID < transfers[j].TransferID })
+	return transfers, nil
+}
+
+// UpdateStatus moves a transfer from one status to another, failing if it
+// is no longer in the expected status. Shipping stamps ShippedAt.
+func (r *TransferRepository) UpdateStatus(transferID int, from, to model.TransferStatus) (*model.TransferOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	transfer, exists := r.transfers[transferID]
+	if !exists {
+		return nil, ErrTransferNotFound
+	}
+	if transfer.Status != from {
+		return nil, ErrTransferConflict
+	}
+
+	transfer.Status = to
+	switch to {
+	case model.TransferInTransit:
+		now := time.Now().UTC()
+		transfer.ShippedAt = &now
+	case model.TransferRequested:
+		transfer.ShippedAt = nil
+	}
+	return copyTransfer(transfer), nil
+}
+
+// RecordReceipt adds received quantities to a shipped transfer, for every
+// line or for none of them, and marks it received once nothing is outstanding
+func (r *TransferRepository) RecordReceipt(transferID int, lines []model.TransferLineRequest) (*model.TransferOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	transfer, exists := r.transfers[transferID]
+	if !exists {
+		return nil, ErrTransferNotFound
+	}
+	if transfer.Status != model.TransferInTransit && transfer.Status != model.TransferPartiallyReceived {
+		return nil, ErrTransferConflict
+	}
+
+	outstanding := make(map[int]int, len(transfer.Lines))
+	for _, line := range transfer.Lines {
+		outstanding[line.ProductID] += line.Quantity - line.Received
+	}
+	for _, line := range lines {
+		remaining, onTransfer := outstanding[line.ProductID]
+		if !onTransfer {
+			return nil, ErrTransferLineNotFound
+		}
+		if line.Quantity > remaining {
+			return nil, ErrTransferOverReceipt
+		}
+		outstanding[line.ProductID] = remaining - line.Quantity
+	}
+
+	for _, received := range lines {
+		remaining := received.Quantity
+		for i := range transfer.Lines {
+			line := &transfer.Lines[i]
+			if line.ProductID != received.ProductID {
+				continue
+			}
+			take := min(line.Quantity-line.Received, remaining)
+			line.Received += take
+			remaining -= take
+		}
+	}
+
+	transfer.Status = model.TransferReceived
+	for _, remaining := range outstanding {
+		if remaining > 0 {
+			transfer.Status = model.TransferPartiallyReceived
+			break
+		}
+	}
+	return copyTransfer(transfer), nil
+}
+
+// Close finalizes a shipped transfer, recording anything not yet received
+// as a shortage on its line
+func (r *TransferRepository) Close(transferID int) (*model.TransferOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	transfer, exists := r.transfers[transferID]
+	if !exists {
+		return nil, ErrTransferNotFound
+	}
+	switch transfer.Status {
+	case model.TransferInTransit, model.TransferPartiallyReceived, model.TransferReceived:
+	default:
+		return nil, ErrTransferConflict
+	}
+
+	for i := range transfer.Lines {
+		transfer.Lines[i].Shortage = transfer.Lines[i].Quantity - transfer.Lines[i].Received
+	}
+	now := time.Now().UTC()
+	transfer.Status = model.TransferClosed
+	transfer.ClosedAt = &now
+	return copyTransfer(transfer), nil
+}
+
+// copyTransfer returns a copy of a transfer order that shares no slices with the original
+func copyTransfer(transfer *model.TransferOrder) *model.TransferOrder {
+	transferCopy := *transfer
+	transferCopy.Lines = make([]model.TransferLine, len(transfer.Lines))
+	copy(transferCopy.Lines, transfer.Lines)
+	return &transferCopy
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/gocart-v2/shared/model"
+)
+
+var (
+	ErrWarehouseNotFound = errors.New("warehouse not found")
+	ErrWarehouseExists   = errors.New("warehouse code already in use")
+)
+
+type WarehouseRepository struct {
+	warehouses      map[int]*model.Warehouse
+	mu              sync.RWMutex
+	nextWarehouseID int
+}
+
+func NewWarehouseRepository() *WarehouseRepository {
+	return &WarehouseRepository{
+		warehouses:      make(map[int]*model.Warehouse),
+		nextWarehouseID: 1,
+	}
+}
+
+// Create stores a new warehouse and assigns its ID
+func (r *WarehouseRepository) Create(warehouse *model.Warehouse) (*model.Warehouse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.warehouses {
+		if existing.Code == warehouse.Code {
+			return nil, ErrWarehouseExists
+		}
+	}
+
+	warehouseCopy := *warehouse
+	warehouseCopy.WarehouseID = r.nextWarehouseID
+	r.warehouses[warehouseCopy.WarehouseID] = &warehouseCopy
+	r.nextWarehouseID++
+
+	result := warehouseCopy
+	return &result, nil
+}
+
+// GetByID retrieves a warehouse by its ID
+func (r *WarehouseRepository) GetByID(warehouseID int) (*model.Warehouse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	warehouse, exists := r.warehouses[warehouseID]
+	if !exists {
+		return nil, ErrWarehouseNotFound
+	}
+
+	warehouseCopy := *warehouse
+	return &warehouseCopy, nil
+}
+
+// List returns every warehouse ordered by ID
+func (r *WarehouseRepository) List() ([]model.Warehouse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	warehouses := make([]model.Warehouse, 0, len(r.warehouses))
+	for _, warehouse := range r.warehouses {
+		warehouses = append(warehouses, *warehouse)
+	}
+
+	sort.Slice(warehouses, func(i, j int) bool { return warehouses[i].WarehouseID < warehouses[j].WarehouseID })
+	return warehouses, nil
+}
+
+// Update replaces a warehouse's details
+func (r *WarehouseRepository) Update(warehouse *model.Warehouse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.warehouses[warehouse.WarehouseID]; !exists {
+		return ErrWarehouseNotFound
+	}
+	for _, existing := range r.warehouses {
+		if existing.Code == warehouse.Code && existing.WarehouseID != warehouse.WarehouseID {
+			return ErrWarehouseExists
+		}
+	}
+
+	warehouseCopy := *warehouse
+	r.warehouses[warehouse.WarehouseID] = &warehouseCopy
+	return nil
+}
@@ -0,0 +1,136 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"golang.org/x/net/webdav"
+
+	"github.com/gocart-v2/warehouse-service/internal/handler"
+)
+
+type AllHandlers struct {
+	RootHandler          *handler.RootHandler
+	StockHandler         *handler.StockHandler
+	ReservationHandler   *handler.ReservationHandler
+	MovementHandler      *handler.MovementHandler
+	WarehouseHandler     *handler.WarehouseHandler
+	AllocationHandler    *handler.AllocationHandler
+	TransferHandler      *handler.TransferHandler
+	ReplenishmentHandler *handler.ReplenishmentHandler
+	SerialHandler        *handler.SerialHandler
+	BackorderHandler     *handler.BackorderHandler
+	PickHandler          *handler.PickHandler
+	ShipmentHandler      *handler.ShipmentHandler
+	CycleCountHandler    *handler.CycleCountHandler
+	SwaggerHandler       *webdav.Handler
+}
+
+func SetupRoutes(e *gin.Engine, h *AllHandlers) {
+	root := e.Group("")
+	{
+		root.GET("/health", h.RootHandler.GetHealthStatus)
+	}
+
+	v1 := e.Group("/v1")
+	{
+		// Warehouse routes
+		warehouse := v1.Group("/warehouse")
+		{
+			sites := warehouse.Group("/sites")
+			{
+				sites.POST("", h.WarehouseHandler.CreateWarehouse)
+				sites.GET("", h.WarehouseHandler.ListWarehouses)
+				sites.GET("/:warehouseId", h.WarehouseHandler.GetWarehouse)
+				sites.PUT("/:warehouseId", h.WarehouseHandler.UpdateWarehouse)
+			}
+
+			stock := warehouse.Group("/stock")
+			{
+				stock.GET("/:productId", h.StockHandler.GetStock)
+				stock.POST("/:productId/adjustments", h.StockHandler.AdjustStock)
+				stock.PUT("/:productId/warehouses/:warehouseId", h.StockHandler.SetStock)
+				stock.POST("/:productId/receipts", h.StockHandler.ReceiveStock)
+				stock.POST("/:productId/transfers", h.StockHandler.TransferStock)
+				stock.GET("/:productId/reconciliation", h.MovementHandler.ReconcileStock)
+				stock.PUT("/:productId/reorder-policy", h.ReplenishmentHandler.SetReorderPolicy)
+				stock.GET("/:productId/reorder-policy", h.ReplenishmentHandler.GetReorderPolicy)
+				stock.DELETE("/:productId/reorder-policy", h.ReplenishmentHandler.DeleteReorderPolicy)
+				stock.PUT("/:productId/serialization", h.SerialHandler.SetSerialization)
+				stock.GET("/:productId/serialization", h.SerialHandler.GetSerialization)
+				stock.GET("/:productId/serials", h.SerialHandler.ListSerials)
+			}
+
+			warehouse.GET("/lots/expiring", h.StockHandler.ListExpiringLots)
+			warehouse.GET("/serials/:serialNumber", h.SerialHandler.GetSerial)
+			warehouse.GET("/movements", h.MovementHandler.ListMovements)
+			warehouse.POST("/allocations", h.AllocationHandler.PlanAllocation)
+			warehouse.GET("/replenishment", h.ReplenishmentHandler.GetReplenishmentReport)
+
+			reservations := warehouse.Group("/reservations")
+			{
+				reservations.POST("", h.ReservationHandler.CreateReservation)
+				reservations.GET("/:reservationId", h.ReservationHandler.GetReservation)
+				reservations.POST("/:reservationId/confirm", h.ReservationHandler.ConfirmReservation)
+				reservations.POST("/:reservationId/release", h.ReservationHandler.ReleaseReservation)
+			}
+
+			transfers := warehouse.Group("/transfers")
+			{
+				transfers.POST("", h.TransferHandler.CreateTransfer)
+				transfers.GET("", h.TransferHandler.ListTransfers)
+				transfers.GET("/:transferId", h.TransferHandler.GetTransfer)
+				transfers.POST("/:transferId/ship", h.TransferHandler.ShipTransfer)
+				transfers.POST("/:transferId/receipts", h.TransferHandler.ReceiveTransfer)
+				transfers.POST("/:transferId/close", h.TransferHandler.CloseTransfer)
+				transfers.POST("/:transferId/cancel", h.TransferHandler.CancelTransfer)
+			}
+
+			backorders := warehouse.Group("/backorders")
+			{
+				backorders.GET("", h.BackorderHandler.ListBackorders)
+				backorders.GET("/:backorderId", h.BackorderHandler.GetBackorder)
+				backorders.POST("/:backorderId/cancel", h.BackorderHandler.CancelBackorder)
+			}
+
+			pickLists := warehouse.Group("/pick-lists")
+			{
+				pickLists.POST("", h.PickHandler.CreatePickLists)
+				pickLists.GET("", h.PickHandler.ListPickLists)
+				pickLists.GET("/:pickListId", h.PickHandler.GetPickList)
+				pickLists.POST("/:pickListId/confirm", h.PickHandler.ConfirmPick)
+				pickLists.POST("/:pickListId/cartons", h.PickHandler.PackCarton)
+			}
+
+			pickBatches := warehouse.Group("/pick-batches")
+			{
+				pickBatches.POST("", h.PickHandler.CreatePickBatch)
+				pickBatches.GET("/:batchId", h.PickHandler.GetPickBatch)
+				pickBatches.POST("/:batchId/confirm", h.PickHandler.ConfirmBatchPick)
+			}
+
+			shipments := warehouse.Group("/shipments")
+			{
+				shipments.POST("", h.ShipmentHandler.CreateShipment)
+				shipments.GET("", h.ShipmentHandler.ListShipments)
+				shipments.GET("/:shipmentId", h.ShipmentHandler.GetShipment)
+				shipments.POST("/:shipmentId/tracking", h.ShipmentHandler.RefreshShipment)
+			}
+
+			cycleCounts := warehouse.Group("/cycle-counts")
+			{
+				cycleCounts.POST("", h.CycleCountHandler.CreateCycleCount)
+				cycleCounts.GET("", h.CycleCountHandler.ListCycleCounts)
+				cycleCounts.GET("/:cycleCountId", h.CycleCountHandler.GetCycleCount)
+				cycleCounts.POST("/:cycleCountId/counts", h.CycleCountHandler.RecordCounts)
+				cycleCounts.POST("/:cycleCountId/submit", h.CycleCountHandler.SubmitCycleCount)
+				cycleCounts.POST("/:cycleCountId/approve", h.CycleCountHandler.ApproveCycleCount)
+				cycleCounts.POST("/:cycleCountId/cancel", h.CycleCountHandler.CancelCycleCount)
+			}
+		}
+	}
+
+	swagger := e.Group("/swagger")
+	{
+		swagger.GET("/*any", ginSwagger.WrapHandler(h.SwaggerHandler))
+	}
+}
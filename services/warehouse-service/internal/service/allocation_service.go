@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/allocation"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+)
+
+var (
+	ErrInvalidAllocation = errors.New("invalid allocation request")
+)
+
+type AllocationService struct {
+	engine        *allocation.Engine
+	stockRepo     *repository.StockRepository
+	warehouseRepo *repository.WarehouseRepository
+}
+
+func NewAllocationService(
+	engine *allocation.Engine,
+	stockRepo *repository.StockRepository,
+	warehouseRepo *repository.WarehouseRepository,
+) *AllocationService {
+	return &AllocationService{
+		engine:        engine,
+		stockRepo:     stockRepo,
+		warehouseRepo: warehouseRepo,
+	}
+}
+
+// PlanAllocation decides which warehouses would fulfill the requested items,
+// without holding any stock
+func (s *AllocationService) PlanAllocation(req *model.AllocationRequest) (*model.Allocation, error) {
+	lines := make([]allocation.Line, 0, len(req.Items))
+	for _, item := range req.Items {
+		lines = append(lines, allocation.Line{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	return s.allocate(req.Strategy, &req.Destination, lines)
+}
+
+// allocate runs the engine against current availability in every active warehouse.
+// Stock in warehouses that were never registered is still used, but with
+// no known location.
+func (s *AllocationService) allocate(strategy model.AllocationStrategy, destination *model.Address, lines []allocation.Line) (*model.Allocation, error) {
+	productIDs := make([]int, 0, len(lines))
+	for _, line := range lines {
+		productIDs = append(productIDs, line.ProductID)
+	}
+
+	available, err := s.stockRepo.AvailableByWarehouse(productIDs)
+	if err != nil {
+		return nil, err
+	}
+	warehouses, err := s.warehouseRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make(map[int]model.Warehouse, len(warehouses))
+	for _, warehouse := range warehouses {
+		registered[warehouse.WarehouseID] = warehouse
+	}
+
+	sites := make([]allocation.Site, 0, len(available))
+	for warehouseID, quantities := range available {
+		site := allocation.Site{
+			WarehouseID: warehouseID,
+			Available:   quantities,
+		}
+		if warehouse, ok := registered[warehouseID]; ok {
+			if !warehouse.Active {
+				continue
+			}
+			site.Address = &warehouse.Address
+			site.HandlingCost = warehouse.HandlingCost
+		}
+		sites = append(sites, site)
+	}
+
+	plan, err := s.engine.Allocate(&allocation.Request{
+		Strategy:    strategy,
+		Destination: destination,
+		Lines:       lines,
+		Sites:       sites,
+	})
+	switch err {
+	case allocation.ErrInsufficientStock:
+		return nil, ErrInsufficientStock
+	case allocation.ErrInvalidRequest, allocation.ErrUnknownStrategy:
+		return nil, ErrInvalidAllocation
+	}
+	return plan, err
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+)
+
+var (
+	ErrInvalidBackorder  = errors.New("invalid backorder query")
+	ErrBackorderNotFound = errors.New("backorder not found")
+	ErrBackorderClosed   = errors.New("backorder is no longer outstanding")
+)
+
+// ReasonBackorderAllocated is recorded when received stock is committed to a backorder
+const ReasonBackorderAllocated = "BACKORDER_ALLOCATED"
+
+type BackorderService struct {
+	backorderRepo *repository.BackorderRepository
+	stockRepo     *repository.StockRepository
+	movementRepo  *repository.MovementRepository
+	serialRepo    *repository.SerialRepository
+
+	// allocating serializes allocation so each unit goes to one backorder
+	allocating sync.Mutex
+}
+
+func NewBackorderService(
+	backorderRepo *repository.BackorderRepository,
+	stockRepo *repository.StockRepository,
+	movementRepo *repository.MovementRepository,
+	serialRepo *repository.SerialRepository,
+) *BackorderService {
+	return &BackorderService{
+		backorderRepo: backorderRepo,
+		stockRepo:     stockRepo,
+		movementRepo:  movementRepo,
+		serialRepo:    serialRepo,
+	}
+}
+
+// CreateBackorders opens a backorder for each backorder line of a confirmed
+// reservation and returns the lines with their backorder IDs
+func (s *BackorderService) CreateBackorders(reservation *model.Reservation) ([]model.BackorderLine, error) {
+	now := time.Now().UTC()
+	lines := make([]model.BackorderLine, 0, len(reservation.Backorders))
+	for _, line := range reservation.Backorders {
+		backorder, err := s.backorderRepo.Create(&model.Backorder{
+			OrderRef:         reservation.OrderRef,
+			CustomerID:       reservation.CustomerID,
+			ProductID:        line.ProductID,
+			Quantity:         line.Quantity,
+			Status:           model.BackorderOpen,
+			ExpectedShipDate: line.ExpectedShipDate,
+			ReleaseDate:      line.ReleaseDate,
+			CreatedAt:        now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		line.BackorderID = backorder.BackorderID
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// GetBackorder retrieves a backorder
+func (s *BackorderService) GetBackorder(backorderID int) (*model.Backorder, error) {
+	if backorderID < 1 {
+		return nil, ErrInvalidBackorder
+	}
+
+	backorder, err := s.backorderRepo.GetByID(backorderID)
+	if err == repository.ErrBackorderNotFound {
+		return nil, ErrBackorderNotFound
+	}
+	return backorder, err
+}
+
+// ListBackorders returns backorders, optionally for one order, product or status
+func (s *BackorderService) ListBackorders(orderRef string, productID int, status model.BackorderStatus) ([]*model.Backorder, error) {
+	if productID < 0 {
+		return nil, ErrInvalidBackorder
+	}
+	switch status {
+	case "", model.BackorderOpen, model.BackorderPartiallyAllocated, model.BackorderAllocated, model.BackorderCancelled:
+	default:
+		return nil, ErrInvalidBackorder
+	}
+
+	return s.backorderRepo.List(orderRef, productID, status)
+}
+
+// CancelBackorder stops a backorder from receiving further stock. Units
+// already allocated stay committed to the order.
+func (s *BackorderService) CancelBackorder(backorderID int) (*model.Backorder, error) {
+	if _, err := s.GetBackorder(backorderID); err != nil {
+		return nil, err
+	}
+
+	backorder, err := s.backorderRepo.Cancel(backorderID)
+	if err == repository.ErrBackorderClosed {
+		return nil, ErrBackorderClosed
+	}
+	return backorder, err
+}
+
+// AllocateStock commits a product's available stock to its outstanding
+// backorders, oldest first, and returns how many backorders received stock
+func (s *BackorderService) AllocateStock(productID int) (int, error) {
+	s.allocating.Lock()
+	defer s.allocating.Unlock()
+
+	backorders, err := s.backorderRepo.ListOutstanding(productID)
+	if err != nil || len(backorders) == 0 {
+		return 0, err
+	}
+	setting, err := s.serialRepo.GetSerialization(productID)
+	if err != nil {
+		return 0, err
+	}
+
+	allocated := 0
+	for _, backorder := range backorders {
+		available, err := s.stockRepo.AvailableByWarehouse([]int{productID})
+		if err != nil {
+			return allocated, err
+		}
+		total := 0
+		for _, stock := range available {
+			total += stock[productID]
+		}
+		if total == 0 {
+			break
+		}
+
+		lines, err := s.stockRepo.Reserve([]model.ReserveLineRequest{{
+			ProductID: productID,
+			Quantity:  min(total, backorder.Quantity-backorder.Allocated),
+		}})
+		if err == repository.ErrInsufficientStock {
+			// Stock was taken in the meantime; the next receipt will try again
+			break
+		}
+		if err != nil {
+			return allocated, err
+		}
+		if setting.Serialized {
+			if err := s.allocateSerials(lines, backorder); err != nil {
+				if unreserveErr := s.stockRepo.Unreserve(lines); unreserveErr != nil {
+					return allocated, unreserveErr
+				}
+				if err == repository.ErrSerialShortage {
+					// Units without serial numbers cannot ship; wait for the next receipt
+					break
+				}
+				return allocated, err
+			}
+		}
+		if err := s.stockRepo.Commit(lines); err != nil {
+			return allocated, err
+		}
+
+		now := time.Now().UTC()
+		movements := []model.StockMovement{}
+		for _, line := range lines {
+			movements = append(movements, pickMovements(line, backorder.OrderRef, ReasonBackorderAllocated, now)...)
+		}
+		if _, err := s.movementRepo.Append(movements...); err != nil {
+			return allocated, err
+		}
+		if _, err := s.backorderRepo.RecordAllocation(backorder.BackorderID, lines); err != nil {
+			return allocated, err
+		}
+		allocated++
+	}
+
+	return allocated, nil
+}
+
+// AllocateReceived runs AllocateStock for products that just gained stock.
+// Failures are logged rather than returned so the receipt itself still succeeds.
+func (s *BackorderService) AllocateReceived(productIDs ...int) {
+	for _, productID := range productIDs {
+		if _, err := s.AllocateStock(productID); err != nil {
+			log.Printf("Failed to allocate backorders for product %d: %v", productID, err)
+		}
+	}
+}
+
+// allocateSerials picks units for each line reserved for a backorder,
+// oldest received first. Either every line gets its units or none do.
+func (s *BackorderService) allocateSerials(lines []model.ReservationLine, backorder *model.Backorder) error {
+	for i, line := range lines {
+		serialNumbers, err := s.serialRepo.Allocate(line.ProductID, line.WarehouseID, line.Quantity,
+			&model.Reservation{OrderRef: backorder.OrderRef, CustomerID: backorder.CustomerID})
+		if err != nil {
+			for j := range lines[:i] {
+				if unallocateErr := s.serialRepo.Unallocate(lines[j].SerialNumbers); unallocateErr != nil {
+					return unallocateErr
+				}
+				lines[j].SerialNumbers = nil
+			}
+			return err
+		}
+		lines[i].SerialNumbers = serialNumbers
+	}
+	return nil
+}
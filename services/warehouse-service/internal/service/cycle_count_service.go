@@ -0,0 +1,409 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+)
+
+var (
+	ErrInvalidCycleCount  = errors.New("invalid cycle count data")
+	ErrCycleCountNotFound = errors.New("cycle count not found")
+	ErrCycleCountState    = errors.New("cycle count is not in a status that allows this action")
+	ErrCountLineNotFound  = errors.New("product or lot is not on the cycle count")
+	ErrCountIncomplete    = errors.New("every line must be counted before the cycle count is submitted")
+	ErrSelfApproval       = errors.New("a cycle count must be approved by someone other than who submitted it")
+)
+
+// ReasonCycleCount is recorded on adjustments posted from a cycle count
+const ReasonCycleCount = "CYCLE_COUNT"
+
+// DefaultCountApprovalThreshold is the largest variance, in units, a
+// cycle count line may show before the count needs approval
+const DefaultCountApprovalThreshold = 5
+
+type CycleCountService struct {
+	cycleCountRepo    *repository.CycleCountRepository
+	stockRepo         *repository.StockRepository
+	movementRepo      *repository.MovementRepository
+	serialRepo        *repository.SerialRepository
+	backorderService  *BackorderService
+	approvalThreshold int
+
+	// posting serializes status changes so a count is only posted once
+	posting sync.Mutex
+}
+
+func NewCycleCountService(
+	cycleCountRepo *repository.CycleCountRepository,
+	stockRepo *repository.StockRepository,
+	movementRepo *repository.MovementRepository,
+	serialRepo *repository.SerialRepository,
+	backorderService *BackorderService,
+	approvalThreshold int,
+) *CycleCountService {
+	return &CycleCountService{
+		cycleCountRepo:    cycleCountRepo,
+		stockRepo:         stockRepo,
+		movementRepo:      movementRepo,
+		serialRepo:        serialRepo,
+		backorderService:  backorderService,
+		approvalThreshold: approvalThreshold,
+	}
+}
+
+// CreateCycleCount starts a count of products in a warehouse, snapshotting
+// the on-hand quantity of each lot and of untracked stock. Serialized
+// products are snapshotted by serial number.
+func (s *CycleCountService) CreateCycleCount(req *model.CreateCycleCountRequest) (*model.CycleCount, error) {
+	if req.WarehouseID < 1 || len(req.ProductIDs) == 0 || req.Actor == "" {
+		return nil, ErrInvalidCycleCount
+	}
+
+	lines := []model.CycleCountLine{}
+	seen := make(map[int]bool, len(req.ProductIDs))
+	for _, productID := range req.ProductIDs {
+		if productID < 1 || seen[productID] {
+			return nil, ErrInvalidCycleCount
+		}
+		seen[productID] = true
+
+		productLines, err := s.snapshot(productID, req.WarehouseID)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, productLines...)
+	}
+
+	return s.cycleCountRepo.Create(&model.CycleCount{
+		WarehouseID: req.WarehouseID,
+		Status:      model.CycleCountCounting,
+		Lines:       lines,
+		CreatedBy:   req.Actor,
+		CreatedAt:   time.Now().UTC(),
+	})
+}
+
+// GetCycleCount retrieves a cycle count
+func (s *CycleCountService) GetCycleCount(cycleCountID int) (*model.CycleCount, error) {
+	if cycleCountID < 1 {
+		return nil, ErrInvalidCycleCount
+	}
+
+	cycleCount, err := s.cycleCountRepo.GetByID(cycleCountID)
+	if err == repository.ErrCycleCountNotFound {
+		return nil, ErrCycleCountNotFound
+	}
+	return cycleCount, err
+}
+
+// ListCycleCounts returns cycle counts, optionally for one warehouse or status
+func (s *CycleCountService) ListCycleCounts(warehouseID int, status model.CycleCountStatus) ([]*model.CycleCount, error) {
+	if warehouseID < 0 {
+		return nil, ErrInvalidCycleCount
+	}
+	switch status {
+	case "", model.CycleCountCounting, model.CycleCountPendingApproval, model.CycleCountPosted, model.CycleCountCancelled:
+	default:
+		return nil, ErrInvalidCycleCount
+	}
+
+	return s.cycleCountRepo.List(warehouseID, status)
+}
+
+// RecordCounts stores counted quantities. Serialized lines are counted by
+// listing the serial number of every unit found.
+func (s *CycleCountService) RecordCounts(cycleCountID int, req *model.RecordCountsRequest) (*model.CycleCount, error) {
+	if len(req.Lines) == 0 || req.Actor == "" {
+		return nil, ErrInvalidCycleCount
+	}
+	cycleCount, err := s.GetCycleCount(cycleCountID)
+	if err != nil {
+		return nil, err
+	}
+	serialized := make(map[int]bool)
+	for _, line := range cycleCount.Lines {
+		serialized[line.ProductID] = line.Serialized
+	}
+
+	type lineKey struct {
+		productID int
+		lotNumber string
+	}
+	counts := make([]model.CycleCountLine, 0, len(req.Lines))
+	seen := make(map[lineKey]bool, len(req.Lines))
+	for _, line := range req.Lines {
+		key := lineKey{productID: line.ProductID, lotNumber: line.LotNumber}
+		if line.ProductID < 1 || seen[key] {
+			return nil, ErrInvalidCycleCount
+		}
+		seen[key] = true
+
+		count := model.CycleCountLine{ProductID: line.ProductID, LotNumber: line.LotNumber}
+		if serialized[line.ProductID] {
+			counted := len(line.SerialNumbers)
+			if line.Counted != nil && *line.Counted != counted {
+				return nil, ErrSerialsRequired
+			}
+			found := make(map[string]bool, counted)
+			for _, serialNumber := range line.SerialNumbers {
+				if serialNumber == "" || found[serialNumber] {
+					return nil, ErrSerialsRequired
+				}
+				found[serialNumber] = true
+			}
+			count.Counted = &counted
+			count.CountedSerials = line.SerialNumbers
+		} else {
+			if line.Counted == nil || *line.Counted < 0 || len(line.SerialNumbers) > 0 {
+				return nil, ErrInvalidCycleCount
+			}
+			count.Counted = line.Counted
+		}
+		counts = append(counts, count)
+	}
+
+	updated, err := s.cycleCountRepo.RecordCounts(cycleCountID, counts, req.Actor)
+	switch err {
+	case repository.ErrCycleCountConflict:
+		return nil, ErrCycleCountState
+	case repository.ErrCountLineNotFound:
+		return nil, ErrCountLineNotFound
+	}
+	return updated, err
+}
+
+// SubmitCycleCount works out the variances of a fully counted cycle count.
+// Counts within the approval threshold are posted straight away; the rest
+// wait for approval.
+func (s *CycleCountService) SubmitCycleCount(cycleCountID int, actor string) (*model.CycleCount, error) {
+	if actor == "" {
+		return nil, ErrInvalidCycleCount
+	}
+	if _, err := s.GetCycleCount(cycleCountID); err != nil {
+		return nil, err
+	}
+
+	s.posting.Lock()
+	defer s.posting.Unlock()
+
+	submitted, err := s.cycleCountRepo.Submit(cycleCountID, s.approvalThreshold, actor, time.Now().UTC())
+	switch err {
+	case nil:
+	case repository.ErrCycleCountConflict:
+		return nil, ErrCycleCountState
+	case repository.ErrCountIncomplete:
+		return nil, ErrCountIncomplete
+	default:
+		return nil, err
+	}
+
+	for _, line := range submitted.Lines {
+		if line.RequiresApproval {
+			return submitted, nil
+		}
+	}
+	return s.post(submitted, "")
+}
+
+// ApproveCycleCount posts a cycle count whose variances needed approval
+func (s *CycleCountService) ApproveCycleCount(cycleCountID int, actor string) (*model.CycleCount, error) {
+	if actor == "" {
+		return nil, ErrInvalidCycleCount
+	}
+
+	s.posting.Lock()
+	defer s.posting.Unlock()
+
+	cycleCount, err := s.GetCycleCount(cycleCountID)
+	if err != nil {
+		return nil, err
+	}
+	if cycleCount.Status != model.CycleCountPendingApproval {
+		return nil, ErrCycleCountState
+	}
+	if actor == cycleCount.SubmittedBy {
+		return nil, ErrSelfApproval
+	}
+
+	return s.post(cycleCount, actor)
+}
+
+// CancelCycleCount abandons a cycle count that has not been posted
+func (s *CycleCountService) CancelCycleCount(cycleCountID int, actor string) (*model.CycleCount, error) {
+	if actor == "" {
+		return nil, ErrInvalidCycleCount
+	}
+
+	s.posting.Lock()
+	defer s.posting.Unlock()
+
+	cycleCount, err := s.GetCycleCount(cycleCountID)
+	if err != nil {
+		return nil, err
+	}
+	if cycleCount.Status != model.CycleCountCounting && cycleCount.Status != model.CycleCountPendingApproval {
+		return nil, ErrCycleCountState
+	}
+
+	return s.cycleCountRepo.Close(cycleCountID, cycleCount.Status, model.CycleCountCancelled, "", time.Now().UTC())
+}
+
+// snapshot builds the lines for one product in a warehouse
+func (s *CycleCountService) snapshot(productID, warehouseID int) ([]model.CycleCountLine, error) {
+	setting, err := s.serialRepo.GetSerialization(productID)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := s.stockRepo.GetByProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	var level model.StockLevel
+	for _, candidate := range levels {
+		if candidate.WarehouseID == warehouseID {
+			level = candidate
+		}
+	}
+
+	if setting.Serialized {
+		serials, err := s.serialRepo.ListByProduct(productID, warehouseID, model.SerialInStock)
+		if err != nil {
+			return nil, err
+		}
+		line := model.CycleCountLine{ProductID: productID, Serialized: true, Expected: level.OnHand}
+		for _, serial := range serials {
+			line.ExpectedSerials = append(line.ExpectedSerials, serial.SerialNumber)
+		}
+		return []model.CycleCountLine{line}, nil
+	}
+
+	lines := []model.CycleCountLine{}
+	untracked := level.OnHand
+	for _, lot := range level.Lots {
+		lines = append(lines, model.CycleCountLine{ProductID: productID, LotNumber: lot.LotNumber, Expected: lot.OnHand})
+		untracked -= lot.OnHand
+	}
+	if untracked > 0 || len(level.Lots) == 0 {
+		lines = append(lines, model.CycleCountLine{ProductID: productID, Expected: untracked})
+	}
+	return lines, nil
+}
+
+// post applies the variances of a submitted cycle count as adjustments,
+// undoing them all if any cannot be applied. Serialized lines register the
+// units found and remove the units missing.
+func (s *CycleCountService) post(cycleCount *model.CycleCount, approvedBy string) (*model.CycleCount, error) {
+	actor := approvedBy
+	if actor == "" {
+		actor = cycleCount.SubmittedBy
+	}
+
+	undo := []func(){}
+	rollback := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}
+
+	now := time.Now().UTC()
+	movements := []model.StockMovement{}
+	received := []int{}
+	for _, line := range cycleCount.Lines {
+		delta := line.Variance
+		if line.Serialized {
+			found, missing, err := s.serialVariance(cycleCount.WarehouseID, line)
+			if err != nil {
+				rollback()
+				return nil, err
+			}
+			if err := s.serialRepo.Register(line.ProductID, cycleCount.WarehouseID, found); err != nil {
+				rollback()
+				return nil, serialError(err)
+			}
+			undo = append(undo, func() { s.serialRepo.Remove(line.ProductID, cycleCount.WarehouseID, found) })
+			if err := s.serialRepo.Remove(line.ProductID, cycleCount.WarehouseID, missing); err != nil {
+				rollback()
+				return nil, serialError(err)
+			}
+			undo = append(undo, func() { s.serialRepo.Register(line.ProductID, cycleCount.WarehouseID, missing) })
+			delta = len(found) - len(missing)
+		}
+		if delta == 0 {
+			continue
+		}
+
+		_, err := s.stockRepo.Adjust(line.ProductID, cycleCount.WarehouseID, line.LotNumber, delta)
+		if err != nil {
+			rollback()
+			switch err {
+			case repository.ErrInsufficientStock:
+				return nil, ErrInsufficientStock
+			case repository.ErrLotNotFound:
+				return nil, ErrLotNotFound
+			}
+			return nil, err
+		}
+		undo = append(undo, func() { s.stockRepo.Adjust(line.ProductID, cycleCount.WarehouseID, line.LotNumber, -delta) })
+
+		movements = append(movements, model.StockMovement{
+			ProductID:   line.ProductID,
+			WarehouseID: cycleCount.WarehouseID,
+			Type:        model.MovementAdjustment,
+			LotNumber:   line.LotNumber,
+			Quantity:    delta,
+			ReasonCode:  ReasonCycleCount,
+			Actor:       actor,
+			Reference:   "CYCLE-COUNT-" + strconv.Itoa(cycleCount.CycleCountID),
+			CreatedAt:   now,
+		})
+		if delta > 0 {
+			received = append(received, line.ProductID)
+		}
+	}
+
+	posted, err := s.cycleCountRepo.Close(cycleCount.CycleCountID, model.CycleCountPendingApproval, model.CycleCountPosted, approvedBy, now)
+	if err != nil {
+		rollback()
+		if err == repository.ErrCycleCountConflict {
+			return nil, ErrCycleCountState
+		}
+		return nil, err
+	}
+	if _, err := s.movementRepo.Append(movements...); err != nil {
+		return nil, err
+	}
+	s.backorderService.AllocateReceived(received...)
+
+	return posted, nil
+}
+
+// serialVariance compares the serial numbers counted on a line with those
+// in stock now. Units sold since the snapshot are not reported missing.
+func (s *CycleCountService) serialVariance(warehouseID int, line model.CycleCountLine) (found, missing []string, err error) {
+	serials, err := s.serialRepo.ListByProduct(line.ProductID, warehouseID, model.SerialInStock)
+	if err != nil {
+		return nil, nil, err
+	}
+	inStock := make(map[string]bool, len(serials))
+	for _, serial := range serials {
+		inStock[serial.SerialNumber] = true
+	}
+	counted := make(map[string]bool, len(line.CountedSerials))
+	for _, serialNumber := range line.CountedSerials {
+		counted[serialNumber] = true
+		if !inStock[serialNumber] {
+			found = append(found, serialNumber)
+		}
+	}
+	for _, serialNumber := range line.ExpectedSerials {
+		if inStock[serialNumber] && !counted[serialNumber] {
+			missing = append(missing, serialNumber)
+		}
+	}
+	return found, missing, nil
+}
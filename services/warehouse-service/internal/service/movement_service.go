@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+)
+
+var (
+	ErrInvalidMovementQuery = errors.New("invalid movement query")
+)
+
+type MovementService struct {
+	movementRepo *repository.MovementRepository
+	stockRepo    *repository.StockRepository
+}
+
+func NewMovementService(movementRepo *repository.MovementRepository, stockRepo *repository.StockRepository) *MovementService {
+	return &MovementService{
+		movementRepo: movementRepo,
+		stockRepo:    stockRepo,
+	}
+}
+
+// ListMovements returns the ledger entries for a product, oldest first.
+// A warehouseID of zero and an empty movementType match everything.
+func (s *MovementService) ListMovements(productID, warehouseID int, movementType model.MovementType) ([]model.StockMovement, error) {
+	if productID < 1 || warehouseID < 0 {
+		return nil, ErrInvalidMovementQuery
+	}
+
+	movements, err := s.movementRepo.ListByProduct(productID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	if movementType == "" {
+		return movements, nil
+	}
+
+	filtered := []model.StockMovement{}
+	for _, movement := range movements {
+		if movement.Type == movementType {
+			filtered = append(filtered, movement)
+		}
+	}
+	return filtered, nil
+}
+
+// Reconcile compares a product's recorded on-hand and in-transit stock in
+// each warehouse with the sums of its ledger
+func (s *MovementService) Reconcile(productID int) ([]model.StockReconciliation, error) {
+	if productID < 1 {
+		return nil, ErrInvalidMovementQuery
+	}
+
+	levels, err := s.stockRepo.GetByProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.movementRepo.Balances(productID, model.StockOnHand)
+	if err != nil {
+		return nil, err
+	}
+	transit, err := s.movementRepo.Balances(productID, model.StockInTransit)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := make(map[int]model.StockLevel, len(levels))
+	for _, level := range levels {
+		recorded[level.WarehouseID] = level
+	}
+	for _, ledger := range []map[int]int{balances, transit} {
+		for warehouseID := range ledger {
+			if _, exists := recorded[warehouseID]; !exists {
+				recorded[warehouseID] = model.StockLevel{ProductID: productID, WarehouseID: warehouseID}
+			}
+		}
+	}
+
+	report := make([]model.StockReconciliation, 0, len(recorded))
+	for warehouseID, level := range recorded {
+		discrepancy := level.OnHand - balances[warehouseID] + level.InTransit - transit[warehouseID]
+		report = append(report, model.StockReconciliation{
+			ProductID:       productID,
+			WarehouseID:     warehouseID,
+			OnHand:          level.OnHand,
+			LedgerOnHand:    balances[warehouseID],
+			InTransit:       level.InTransit,
+			LedgerInTransit: transit[warehouseID],
+			Discrepancy:     discrepancy,
+			Balanced:        level.OnHand == balances[warehouseID] && level.InTransit == transit[warehouseID],
+		})
+	}
+
+	sort.Slice(report, func(i, j int) bool { return report[i].WarehouseID < report[j].WarehouseID })
+	return report, nil
+}
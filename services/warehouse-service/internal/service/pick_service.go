@@ -0,0 +1,356 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+)
+
+var (
+	ErrInvalidPick       = errors.New("invalid pick data")
+	ErrPickListNotFound  = errors.New("pick list not found")
+	ErrPickBatchNotFound = errors.New("pick batch not found")
+	ErrPickListState     = errors.New("pick list is not in a status that allows this action")
+	ErrNothingToPick     = errors.New("order has no allocated stock left to pick")
+	ErrCartonMismatch    = errors.New("carton contents do not match the units left to pack")
+)
+
+type PickService struct {
+	pickListRepo    *repository.PickListRepository
+	reservationRepo *repository.ReservationRepository
+	backorderRepo   *repository.BackorderRepository
+	movementRepo    *repository.MovementRepository
+
+	// generating serializes pick list generation so units are listed once
+	generating sync.Mutex
+}
+
+func NewPickService(
+	pickListRepo *repository.PickListRepository,
+	reservationRepo *repository.ReservationRepository,
+	backorderRepo *repository.BackorderRepository,
+	movementRepo *repository.MovementRepository,
+) *PickService {
+	return &PickService{
+		pickListRepo:    pickListRepo,
+		reservationRepo: reservationRepo,
+		backorderRepo:   backorderRepo,
+		movementRepo:    movementRepo,
+	}
+}
+
+// CreatePickLists generates one pick list per warehouse for the stock
+// committed to an order that is not on a pick list yet. Backordered units
+// become pickable as they are allocated.
+func (s *PickService) CreatePickLists(orderRef string) ([]*model.PickList, error) {
+	if orderRef == "" {
+		return nil, ErrInvalidPick
+	}
+
+	s.generating.Lock()
+	defer s.generating.Unlock()
+
+	reservations, err := s.reservationRepo.ListByOrderRef(orderRef, model.ReservationConfirmed)
+	if err != nil {
+		return nil, err
+	}
+	backorders, err := s.backorderRepo.List(orderRef, 0, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var shipTo *model.Address
+	sources := []model.ReservationLine{}
+	for _, reservation := range reservations {
+		if shipTo == nil {
+			shipTo = reservation.Destination
+		}
+		sources = append(sources, reservation.Lines...)
+	}
+	for _, backorder := range backorders {
+		sources = append(sources, backorder.Allocations...)
+	}
+
+	// Skip the units already on earlier pick lists for the order
+	existing, err := s.pickListRepo.List(orderRef, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[[2]int]int)
+	for _, pickList := range existing {
+		for _, line := range pickList.Lines {
+			listed[[2]int{pickList.WarehouseID, line.ProductID}] += line.Quantity
+		}
+	}
+
+	lines := make(map[int][]model.PickLine)
+	for _, source := range sources {
+		for _, line := range pickLinesFor(source) {
+			key := [2]int{source.WarehouseID, line.ProductID}
+			skip := min(listed[key], line.Quantity)
+			listed[key] -= skip
+			if skip == line.Quantity {
+				continue
+			}
+			if len(line.SerialNumbers) > 0 {
+				line.SerialNumbers = line.SerialNumbers[skip:]
+			}
+			line.Quantity -= skip
+			lines[source.WarehouseID] = append(lines[source.WarehouseID], line)
+		}
+	}
+	if len(lines) == 0 {
+		return nil, ErrNothingToPick
+	}
+
+	warehouseIDs := make([]int, 0, len(lines))
+	for warehouseID := range lines {
+		warehouseIDs = append(warehouseIDs, warehouseID)
+	}
+	sort.Ints(warehouseIDs)
+
+	now := time.Now().UTC()
+	pickLists := make([]*model.PickList, 0, len(warehouseIDs))
+	for _, warehouseID := range warehouseIDs {
+		pickList, err := s.pickListRepo.Create(&model.PickList{
+			OrderRef:    orderRef,
+			WarehouseID: warehouseID,
+			Status:      model.PickListOpen,
+			ShipTo:      shipTo,
+			Lines:       lines[warehouseID],
+			CreatedAt:   now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		pickLists = append(pickLists, pickList)
+	}
+
+	return pickLists, nil
+}
+
+// GetPickList retrieves a pick list
+func (s *PickService) GetPickList(pickListID int) (*model.PickList, error) {
+	if pickListID < 1 {
+		return nil, ErrInvalidPick
+	}
+
+	pickList, err := s.pickListRepo.GetByID(pickListID)
+	if err == repository.ErrPickListNotFound {
+		return nil, ErrPickListNotFound
+	}
+	return pickList, err
+}
+
+// ListPickLists returns pick lists, optionally for one order, warehouse or status
+func (s *PickService) ListPickLists(orderRef string, warehouseID int, status model.PickListStatus) ([]*model.PickList, error) {
+	if warehouseID < 0 {
+		return nil, ErrInvalidPick
+	}
+	switch status {
+	case "", model.PickListOpen, model.PickListPicked, model.PickListPacked, model.PickListShipped:
+	default:
+		return nil, ErrInvalidPick
+	}
+
+	return s.pickListRepo.List(orderRef, warehouseID, status)
+}
+
+// ConfirmPick records that every line of an open pick list was pulled,
+// writing a zero-quantity pick movement per line. The stock itself left
+// the ledger when its reservation was confirmed.
+func (s *PickService) ConfirmPick(pickListID int, actor string) (*model.PickList, error) {
+	if actor == "" {
+		return nil, ErrInvalidPick
+	}
+	if _, err := s.GetPickList(pickListID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	pickList, err := s.pickListRepo.ConfirmPick(pickListID, actor, now)
+	if err == repository.ErrPickListConflict {
+		return nil, ErrPickListState
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordPicks(pickList, actor, now); err != nil {
+		return nil, err
+	}
+
+	return pickList, nil
+}
+
+// PackCarton records a carton packed from a picked pick list
+func (s *PickService) PackCarton(pickListID int, req *model.PackCartonRequest) (*model.PickList, error) {
+	if req.Box == "" || req.Length < 1 || req.Width < 1 || req.Height < 1 || req.Weight < 1 ||
+		len(req.Items) == 0 || req.Actor == "" {
+		return nil, ErrInvalidPick
+	}
+	items := make([]model.PackedItem, 0, len(req.Items))
+	seen := make(map[int]bool, len(req.Items))
+	for _, item := range req.Items {
+		if item.ProductID < 1 || item.Quantity < 1 || seen[item.ProductID] {
+			return nil, ErrInvalidPick
+		}
+		seen[item.ProductID] = true
+		items = append(items, model.PackedItem{
+			ProductID:     item.ProductID,
+			Quantity:      item.Quantity,
+			SerialNumbers: item.SerialNumbers,
+		})
+	}
+	if _, err := s.GetPickList(pickListID); err != nil {
+		return nil, err
+	}
+
+	pickList, err := s.pickListRepo.AddCarton(pickListID, &model.Carton{
+		Box:      req.Box,
+		Length:   req.Length,
+		Width:    req.Width,
+		Height:   req.Height,
+		Weight:   req.Weight,
+		Items:    items,
+		PackedBy: req.Actor,
+		PackedAt: time.Now().UTC(),
+	})
+	switch err {
+	case repository.ErrPickListConflict:
+		return nil, ErrPickListState
+	case repository.ErrCartonMismatch:
+		return nil, ErrCartonMismatch
+	}
+	return pickList, err
+}
+
+// CreatePickBatch groups open pick lists in a warehouse so they can be
+// picked together. Without IDs every open, unbatched list is included.
+func (s *PickService) CreatePickBatch(req *model.CreatePickBatchRequest) (*model.PickBatch, error) {
+	if req.WarehouseID < 1 {
+		return nil, ErrInvalidPick
+	}
+
+	pickListIDs := req.PickListIDs
+	if len(pickListIDs) == 0 {
+		open, err := s.pickListRepo.List("", req.WarehouseID, model.PickListOpen)
+		if err != nil {
+			return nil, err
+		}
+		for _, pickList := range open {
+			if pickList.BatchID == 0 {
+				pickListIDs = append(pickListIDs, pickList.PickListID)
+			}
+		}
+		if len(pickListIDs) == 0 {
+			return nil, ErrNothingToPick
+		}
+	}
+	seen := make(map[int]bool, len(pickListIDs))
+	for _, pickListID := range pickListIDs {
+		if pickListID < 1 || seen[pickListID] {
+			return nil, ErrInvalidPick
+		}
+		seen[pickListID] = true
+	}
+
+	batch, err := s.pickListRepo.CreateBatch(req.WarehouseID, pickListIDs, time.Now().UTC())
+	switch err {
+	case repository.ErrPickListNotFound:
+		return nil, ErrPickListNotFound
+	case repository.ErrPickListConflict:
+		return nil, ErrPickListState
+	}
+	return batch, err
+}
+
+// GetPickBatch retrieves a pick batch
+func (s *PickService) GetPickBatch(batchID int) (*model.PickBatch, error) {
+	if batchID < 1 {
+		return nil, ErrInvalidPick
+	}
+
+	batch, err := s.pickListRepo.GetBatch(batchID)
+	if err == repository.ErrPickBatchNotFound {
+		return nil, ErrPickBatchNotFound
+	}
+	return batch, err
+}
+
+// ConfirmBatchPick records that every open pick list in a batch was
+// pulled, writing pick movements for each list as ConfirmPick does
+func (s *PickService) ConfirmBatchPick(batchID int, actor string) ([]*model.PickList, error) {
+	if actor == "" {
+		return nil, ErrInvalidPick
+	}
+	if _, err := s.GetPickBatch(batchID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	pickLists, err := s.pickListRepo.ConfirmBatchPick(batchID, actor, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, pickList := range pickLists {
+		// Lists picked before the batch was confirmed already have movements
+		if pickList.PickedAt == nil || !pickList.PickedAt.Equal(now) {
+			continue
+		}
+		if err := s.recordPicks(pickList, actor, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return pickLists, nil
+}
+
+// recordPicks writes a zero-quantity pick movement for each line of a
+// picked pick list
+func (s *PickService) recordPicks(pickList *model.PickList, actor string, at time.Time) error {
+	movements := make([]model.StockMovement, 0, len(pickList.Lines))
+	for _, line := range pickList.Lines {
+		movements = append(movements, model.StockMovement{
+			ProductID:   line.ProductID,
+			WarehouseID: pickList.WarehouseID,
+			Type:        model.MovementPick,
+			LotNumber:   line.LotNumber,
+			Quantity:    0,
+			ReasonCode:  ReasonPickConfirmed,
+			Actor:       actor,
+			Reference:   pickList.OrderRef,
+			CreatedAt:   at,
+		})
+	}
+	_, err := s.movementRepo.Append(movements...)
+	return err
+}
+
+// pickLinesFor splits a committed line into one pick line per lot, plus one
+// for untracked units, handing out its serial numbers in order
+func pickLinesFor(line model.ReservationLine) []model.PickLine {
+	lines := []model.PickLine{}
+	serialNumbers := line.SerialNumbers
+	add := func(lotNumber string, quantity int) {
+		pickLine := model.PickLine{ProductID: line.ProductID, LotNumber: lotNumber, Quantity: quantity}
+		if len(serialNumbers) >= quantity {
+			pickLine.SerialNumbers = serialNumbers[:quantity]
+			serialNumbers = serialNumbers[quantity:]
+		}
+		lines = append(lines, pickLine)
+	}
+
+	untracked := line.Quantity
+	for _, lot := range line.Lots {
+		add(lot.LotNumber, lot.Quantity)
+		untracked -= lot.Quantity
+	}
+	if untracked > 0 {
+		add("", untracked)
+	}
+	return lines
+}
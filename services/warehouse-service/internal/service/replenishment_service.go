@@ -0,0 +1,204 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/notify"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+)
+
+var (
+	ErrInvalidReorderPolicy  = errors.New("invalid reorder policy data")
+	ErrReorderPolicyNotFound = errors.New("reorder policy not found")
+)
+
+// DefaultVelocityWindow is how far back checkouts are counted towards sales velocity
+const DefaultVelocityWindow = 30 * 24 * time.Hour
+
+type ReplenishmentService struct {
+	policyRepo     *repository.ReorderPolicyRepository
+	stockRepo      *repository.StockRepository
+	movementRepo   *repository.MovementRepository
+	notifier       notify.Notifier
+	velocityWindow time.Duration
+
+	// alerted holds products already reported low, so an alert is published
+	// once per dip below the reorder point rather than on every evaluation
+	alerted map[int]bool
+	mu      sync.Mutex
+}
+
+func NewReplenishmentService(
+	policyRepo *repository.ReorderPolicyRepository,
+	stockRepo *repository.StockRepository,
+	movementRepo *repository.MovementRepository,
+	notifier notify.Notifier,
+	velocityWindow time.Duration,
+) *ReplenishmentService {
+	return &ReplenishmentService{
+		policyRepo:     policyRepo,
+		stockRepo:      stockRepo,
+		movementRepo:   movementRepo,
+		notifier:       notifier,
+		velocityWindow: velocityWindow,
+		alerted:        make(map[int]bool),
+	}
+}
+
+// SetReorderPolicy creates or replaces a product's reorder thresholds
+func (s *ReplenishmentService) SetReorderPolicy(productID int, req *model.SetReorderPolicyRequest) (*model.ReorderPolicy, error) {
+	if productID < 1 || req.ReorderPoint < 0 || req.ReorderQuantity < 1 || req.LeadTimeDays < 0 {
+		return nil, ErrInvalidReorderPolicy
+	}
+
+	return s.policyRepo.Put(&model.ReorderPolicy{
+		ProductID:       productID,
+		ReorderPoint:    req.ReorderPoint,
+		ReorderQuantity: req.ReorderQuantity,
+		LeadTimeDays:    req.LeadTimeDays,
+		UpdatedAt:       time.Now().UTC(),
+	})
+}
+
+// GetReorderPolicy retrieves a product's reorder thresholds
+func (s *ReplenishmentService) GetReorderPolicy(productID int) (*model.ReorderPolicy, error) {
+	if productID < 1 {
+		return nil, ErrInvalidReorderPolicy
+	}
+
+	policy, err := s.policyRepo.GetByProductID(productID)
+	if err == repository.ErrReorderPolicyNotFound {
+		return nil, ErrReorderPolicyNotFound
+	}
+	return policy, err
+}
+
+// DeleteReorderPolicy stops replenishment tracking for a product
+func (s *ReplenishmentService) DeleteReorderPolicy(productID int) error {
+	if productID < 1 {
+		return ErrInvalidReorderPolicy
+	}
+
+	err := s.policyRepo.Delete(productID)
+	if err == repository.ErrReorderPolicyNotFound {
+		return ErrReorderPolicyNotFound
+	}
+	return err
+}
+
+// GetReplenishmentReport suggests replenishment for every product with a reorder policy
+func (s *ReplenishmentService) GetReplenishmentReport() (*model.ReplenishmentReport, error) {
+	suggestions, err := s.suggest()
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ReplenishmentReport{
+		GeneratedAt:        time.Now().UTC(),
+		VelocityWindowDays: int(s.velocityWindow.Hours() / 24),
+		Suggestions:        suggestions,
+	}, nil
+}
+
+// EvaluateStock publishes an alert for each product that has newly fallen
+// to or below its reorder point and returns how many were published
+func (s *ReplenishmentService) EvaluateStock() (int, error) {
+	suggestions, err := s.suggest()
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	published := 0
+	for _, suggestion := range suggestions {
+		if !suggestion.ReorderNeeded {
+			delete(s.alerted, suggestion.ProductID)
+			continue
+		}
+		if s.alerted[suggestion.ProductID] {
+			continue
+		}
+		alert := &model.LowStockAlert{ReplenishmentSuggestion: suggestion, RaisedAt: now}
+		if err := s.notifier.NotifyLowStock(alert); err != nil {
+			return published, err
+		}
+		s.alerted[suggestion.ProductID] = true
+		published++
+	}
+
+	return published, nil
+}
+
+// RunEvaluator evaluates stock against reorder policies every interval. It never returns.
+func (s *ReplenishmentService) RunEvaluator(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if count, err := s.EvaluateStock(); err != nil {
+			log.Println("Failed to evaluate stock levels:", err)
+		} else if count > 0 {
+			log.Printf("Published %d low-stock alerts", count)
+		}
+	}
+}
+
+// suggest builds a replenishment suggestion for every reorder policy.
+// Sales velocity is the units committed by checkouts over the velocity window.
+func (s *ReplenishmentService) suggest() ([]model.ReplenishmentSuggestion, error) {
+	policies, err := s.policyRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	sold, err := s.movementRepo.OutflowSince(model.MovementReservationConfirm, time.Now().Add(-s.velocityWindow))
+	if err != nil {
+		return nil, err
+	}
+	windowDays := s.velocityWindow.Hours() / 24
+
+	suggestions := make([]model.ReplenishmentSuggestion, 0, len(policies))
+	for _, policy := range policies {
+		levels, err := s.stockRepo.GetByProduct(policy.ProductID)
+		if err != nil {
+			return nil, err
+		}
+
+		suggestion := model.ReplenishmentSuggestion{
+			ProductID:       policy.ProductID,
+			ReorderPoint:    policy.ReorderPoint,
+			ReorderQuantity: policy.ReorderQuantity,
+		}
+		for _, level := range levels {
+			suggestion.Available += level.Available
+			suggestion.InTransit += level.InTransit
+		}
+		if windowDays > 0 {
+			suggestion.DailyVelocity = math.Round(float64(sold[policy.ProductID])/windowDays*100) / 100
+		}
+
+		leadTimeDemand := int(math.Ceil(suggestion.DailyVelocity * float64(policy.LeadTimeDays)))
+		suggestion.ProjectedAvailable = suggestion.Available + suggestion.InTransit - leadTimeDemand
+		if suggestion.DailyVelocity > 0 {
+			cover := math.Round(float64(suggestion.Available)/suggestion.DailyVelocity*10) / 10
+			suggestion.DaysOfCover = &cover
+		}
+
+		// Order enough to cover the reorder point after lead-time demand, and at least the reorder quantity
+		if suggestion.ProjectedAvailable <= policy.ReorderPoint {
+			suggestion.ReorderNeeded = true
+			suggestion.SuggestedQuantity = max(policy.ReorderQuantity, policy.ReorderPoint-suggestion.ProjectedAvailable)
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,456 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/allocation"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+)
+
+var (
+	ErrInvalidReservation  = errors.New("invalid reservation data")
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationExists   = errors.New("an active reservation already exists for this order")
+	ErrReservationExpired  = errors.New("reservation has expired")
+	ErrReservationClosed   = errors.New("reservation is no longer active")
+)
+
+// DefaultReservationTTL is how long stock is held when a request sets no TTL
+const DefaultReservationTTL = 15 * time.Minute
+
+// allocationAttempts bounds retries when stock moves between planning and reserving
+const allocationAttempts = 3
+
+type ReservationService struct {
+	reservationRepo   *repository.ReservationRepository
+	stockRepo         *repository.StockRepository
+	movementRepo      *repository.MovementRepository
+	serialRepo        *repository.SerialRepository
+	allocationService *AllocationService
+	backorderService  *BackorderService
+}
+
+func NewReservationService(
+	reservationRepo *repository.ReservationRepository,
+	stockRepo *repository.StockRepository,
+	movementRepo *repository.MovementRepository,
+	serialRepo *repository.SerialRepository,
+	allocationService *AllocationService,
+	backorderService *BackorderService,
+) *ReservationService {
+	return &ReservationService{
+		reservationRepo:   reservationRepo,
+		stockRepo:         stockRepo,
+		movementRepo:      movementRepo,
+		serialRepo:        serialRepo,
+		allocationService: allocationService,
+		backorderService:  backorderService,
+	}
+}
+
+// Reserve holds stock for every line of an order, or fails without holding
+// any. Lines with backorder terms hold what they can and backorder the rest.
+func (s *ReservationService) Reserve(req *model.ReserveRequest) (*model.Reservation, error) {
+	if req.OrderRef == "" || len(req.Lines) == 0 || req.TTLSeconds < 0 || req.CustomerID < 0 {
+		return nil, ErrInvalidReservation
+	}
+	for _, line := range req.Lines {
+		if line.ProductID < 1 || line.Quantity < 1 || line.WarehouseID < 0 {
+			return nil, ErrInvalidReservation
+		}
+		if line.Backorder != nil && line.Backorder.ExpectedShipDate.IsZero() {
+			return nil, ErrInvalidReservation
+		}
+	}
+
+	// Free anything past its expiry first so it counts as available
+	if _, err := s.ExpireReservations(); err != nil {
+		return nil, err
+	}
+
+	lines, backorders, err := s.holdOrBackorder(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := DefaultReservationTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	now := time.Now().UTC()
+	reservation, err := s.reservationRepo.Create(&model.Reservation{
+		OrderRef:    req.OrderRef,
+		CustomerID:  req.CustomerID,
+		Status:      model.ReservationActive,
+		Lines:       lines,
+		Backorders:  backorders,
+		Destination: req.Destination,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	})
+	if err != nil {
+		// Give the stock back since the reservation was never recorded
+		if unreserveErr := s.stockRepo.Unreserve(lines); unreserveErr != nil {
+			return nil, unreserveErr
+		}
+		if err == repository.ErrReservationExists {
+			return nil, ErrReservationExists
+		}
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// holdOrBackorder reserves stock for a request after moving the shortfall
+// of backorderable lines to backorders. Stock can change between checking
+// and holding, so it retries a few times before giving up.
+func (s *ReservationService) holdOrBackorder(req *model.ReserveRequest) ([]model.ReservationLine, []model.BackorderLine, error) {
+	for attempt := 0; attempt < allocationAttempts; attempt++ {
+		lines, backorders, err := s.splitBackorders(req.Lines)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(lines) == 0 {
+			return []model.ReservationLine{}, backorders, nil
+		}
+
+		holdReq := *req
+		holdReq.Lines = lines
+		held, err := s.hold(&holdReq)
+		if err == ErrInsufficientStock && len(backorders) > 0 {
+			continue
+		}
+		return held, backorders, err
+	}
+
+	return nil, nil, ErrInsufficientStock
+}
+
+// splitBackorders caps backorderable lines at the stock available now and
+// returns the shortfall as backorder lines
+func (s *ReservationService) splitBackorders(lines []model.ReserveLineRequest) ([]model.ReserveLineRequest, []model.BackorderLine, error) {
+	productIDs := []int{}
+	for _, line := range lines {
+		if line.Backorder != nil {
+			productIDs = append(productIDs, line.ProductID)
+		}
+	}
+	if len(productIDs) == 0 {
+		return lines, nil, nil
+	}
+
+	available, err := s.stockRepo.AvailableByWarehouse(productIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	total := make(map[int]int)
+	for _, stock := range available {
+		for productID, quantity := range stock {
+			total[productID] += quantity
+		}
+	}
+
+	holdable := []model.ReserveLineRequest{}
+	backorders := []model.BackorderLine{}
+	for _, line := range lines {
+		if line.Backorder == nil {
+			holdable = append(holdable, line)
+			continue
+		}
+
+		var take int
+		if line.WarehouseID > 0 {
+			take = min(available[line.WarehouseID][line.ProductID], total[line.ProductID], line.Quantity)
+			if take > 0 {
+				available[line.WarehouseID][line.ProductID] -= take
+			}
+		} else {
+			take = min(total[line.ProductID], line.Quantity)
+		}
+		total[line.ProductID] -= take
+
+		if take > 0 {
+			holdable = append(holdable, model.ReserveLineRequest{
+				ProductID:   line.ProductID,
+				WarehouseID: line.WarehouseID,
+				Quantity:    take,
+			})
+		}
+		if take < line.Quantity {
+			backorders = append(backorders, model.BackorderLine{
+				ProductID:        line.ProductID,
+				Quantity:         line.Quantity - take,
+				ExpectedShipDate: line.Backorder.ExpectedShipDate,
+				ReleaseDate:      line.Backorder.ReleaseDate,
+			})
+		}
+	}
+
+	return holdable, backorders, nil
+}
+
+// hold reserves stock for a request. Without a destination, lines are
+// filled in warehouse ID order; with one, the allocation engine picks the
+// warehouses and the plan is reserved as a whole, replanning if stock
+// changed in between.
+func (s *ReservationService) hold(req *model.ReserveRequest) ([]model.ReservationLine, error) {
+	if req.Destination == nil {
+		lines, err := s.stockRepo.Reserve(req.Lines)
+		if err == repository.ErrInsufficientStock {
+			return nil, ErrInsufficientStock
+		}
+		return lines, err
+	}
+
+	for attempt := 0; attempt < allocationAttempts; attempt++ {
+		planned := []model.ReserveLineRequest{}
+		unallocated := []allocation.Line{}
+		for _, line := range req.Lines {
+			if line.WarehouseID > 0 {
+				planned = append(planned, line)
+			} else {
+				unallocated = append(unallocated, allocation.Line{ProductID: line.ProductID, Quantity: line.Quantity})
+			}
+		}
+
+		if len(unallocated) > 0 {
+			plan, err := s.allocationService.allocate(req.Strategy, req.Destination, unallocated)
+			if err != nil {
+				return nil, err
+			}
+			for _, shipment := range plan.Shipments {
+				for _, item := range shipment.Items {
+					planned = append(planned, model.ReserveLineRequest{
+						ProductID:   item.ProductID,
+						WarehouseID: shipment.WarehouseID,
+						Quantity:    item.Quantity,
+					})
+				}
+			}
+		}
+
+		lines, err := s.stockRepo.Reserve(planned)
+		if err == repository.ErrInsufficientStock {
+			continue
+		}
+		return lines, err
+	}
+
+	return nil, ErrInsufficientStock
+}
+
+// GetReservation retrieves a reservation
+func (s *ReservationService) GetReservation(reservationID int) (*model.Reservation, error) {
+	if reservationID < 1 {
+		return nil, ErrInvalidReservation
+	}
+
+	reservation, err := s.reservationRepo.GetByID(reservationID)
+	if err == repository.ErrReservationNotFound {
+		return nil, ErrReservationNotFound
+	}
+	return reservation, err
+}
+
+// ConfirmReservation turns held stock into a committed pick, removing it from on-hand
+func (s *ReservationService) ConfirmReservation(reservationID int) (*model.Reservation, error) {
+	reservation, err := s.GetReservation(reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reservation.Status == model.ReservationActive && !reservation.ExpiresAt.After(time.Now()) {
+		if _, err := s.expire(reservation); err != nil {
+			return nil, err
+		}
+		return nil, ErrReservationExpired
+	}
+
+	// Pick serialized units first so a shortage leaves the reservation
+	// active with its stock still held
+	serialNumbers, err := s.pickSerials(reservation)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmed, err := s.reservationRepo.UpdateStatus(reservationID, model.ReservationActive, model.ReservationConfirmed)
+	if err != nil {
+		s.unpickSerials(serialNumbers)
+		if err == repository.ErrStatusConflict {
+			return nil, ErrReservationClosed
+		}
+		return nil, err
+	}
+
+	if err := s.stockRepo.Commit(confirmed.Lines); err != nil {
+		if _, revertErr := s.reservationRepo.UpdateStatus(reservationID, model.ReservationConfirmed, model.ReservationActive); revertErr != nil {
+			log.Printf("Failed to reopen reservation %d: %v", reservationID, revertErr)
+		}
+		s.unpickSerials(serialNumbers)
+		return nil, err
+	}
+	if serialNumbers != nil {
+		if confirmed, err = s.reservationRepo.SetSerialNumbers(reservationID, serialNumbers); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	movements := make([]model.StockMovement, 0, len(confirmed.Lines))
+	for _, line := range confirmed.Lines {
+		movements = append(movements, pickMovements(line, confirmed.OrderRef, ReasonReservationConfirmed, now)...)
+	}
+	if _, err := s.movementRepo.Append(movements...); err != nil {
+		return nil, err
+	}
+
+	if len(confirmed.Backorders) > 0 {
+		backorders, err := s.backorderService.CreateBackorders(confirmed)
+		if err != nil {
+			return nil, err
+		}
+		return s.reservationRepo.SetBackorders(reservationID, backorders)
+	}
+
+	return confirmed, nil
+}
+
+// ReleaseReservation returns held stock to the available pool
+func (s *ReservationService) ReleaseReservation(reservationID int) (*model.Reservation, error) {
+	if _, err := s.GetReservation(reservationID); err != nil {
+		return nil, err
+	}
+
+	released, err := s.reservationRepo.UpdateStatus(reservationID, model.ReservationActive, model.ReservationReleased)
+	if err == repository.ErrStatusConflict {
+		return nil, ErrReservationClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.stockRepo.Unreserve(released.Lines); err != nil {
+		return nil, err
+	}
+
+	return released, nil
+}
+
+// ExpireReservations releases every active reservation past its expiry
+func (s *ReservationService) ExpireReservations() (int, error) {
+	expired, err := s.reservationRepo.ListExpired(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, reservation := range expired {
+		ok, err := s.expire(reservation)
+		if err != nil {
+			return count, err
+		}
+		if ok {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// RunExpiry expires reservations every interval. It never returns.
+func (s *ReservationService) RunExpiry(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if count, err := s.ExpireReservations(); err != nil {
+			log.Println("Failed to expire reservations:", err)
+		} else if count > 0 {
+			log.Printf("Expired %d reservations", count)
+		}
+	}
+}
+
+// expire moves an active reservation to expired and frees its stock.
+// It reports false if the reservation was closed concurrently.
+func (s *ReservationService) expire(reservation *model.Reservation) (bool, error) {
+	expired, err := s.reservationRepo.UpdateStatus(reservation.ReservationID, model.ReservationActive, model.ReservationExpired)
+	if err == repository.ErrStatusConflict {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, s.stockRepo.Unreserve(expired.Lines)
+}
+
+// pickSerials allocates specific units to each line of a reservation whose
+// product is serialized, oldest received first, and returns them indexed
+// like its lines, or nil if no product is serialized. Either every line
+// gets its units or none do.
+func (s *ReservationService) pickSerials(reservation *model.Reservation) ([][]string, error) {
+	serialNumbers := make([][]string, len(reservation.Lines))
+	picked := false
+	for i, line := range reservation.Lines {
+		setting, err := s.serialRepo.GetSerialization(line.ProductID)
+		if err == nil && setting.Serialized {
+			serialNumbers[i], err = s.serialRepo.Allocate(line.ProductID, line.WarehouseID, line.Quantity, reservation)
+			picked = true
+		}
+		if err == repository.ErrSerialShortage {
+			err = ErrSerialShortage
+		}
+		if err != nil {
+			s.unpickSerials(serialNumbers)
+			return nil, err
+		}
+	}
+
+	if !picked {
+		return nil, nil
+	}
+	return serialNumbers, nil
+}
+
+// unpickSerials puts units allocated by pickSerials back in stock
+func (s *ReservationService) unpickSerials(serialNumbers [][]string) {
+	for _, lineSerials := range serialNumbers {
+		if err := s.serialRepo.Unallocate(lineSerials); err != nil {
+			log.Printf("Failed to return serial numbers %v to stock: %v", lineSerials, err)
+		}
+	}
+}
+
+// pickMovements builds the ledger entries for stock committed to an order:
+// one per lot picked, plus one for any untracked stock
+func pickMovements(line model.ReservationLine, orderRef, reasonCode string, at time.Time) []model.StockMovement {
+	movement := func(lotNumber string, quantity int) model.StockMovement {
+		return model.StockMovement{
+			ProductID:   line.ProductID,
+			WarehouseID: line.WarehouseID,
+			Type:        model.MovementReservationConfirm,
+			LotNumber:   lotNumber,
+			Quantity:    -quantity,
+			ReasonCode:  reasonCode,
+			Actor:       SystemActor,
+			Reference:   orderRef,
+			CreatedAt:   at,
+		}
+	}
+
+	movements := []model.StockMovement{}
+	untracked := line.Quantity
+	for _, lot := range line.Lots {
+		movements = append(movements, movement(lot.LotNumber, lot.Quantity))
+		untracked -= lot.Quantity
+	}
+	if untracked > 0 {
+		movements = append(movements, movement("", untracked))
+	}
+	return movements
+}
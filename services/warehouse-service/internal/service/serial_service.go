@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+)
+
+var (
+	ErrInvalidSerial  = errors.New("invalid serial number query")
+	ErrSerialNotFound = errors.New("serial number not found")
+)
+
+type SerialService struct {
+	repo *repository.SerialRepository
+}
+
+func NewSerialService(repo *repository.SerialRepository) *SerialService {
+	return &SerialService{repo: repo}
+}
+
+// SetSerialization turns serial tracking on or off for a product. Stock
+// received while tracking is on must list its serial numbers.
+func (s *SerialService) SetSerialization(productID int, serialized bool) (*model.SerializationSetting, error) {
+	if productID < 1 {
+		return nil, ErrInvalidSerial
+	}
+
+	return s.repo.SetSerialized(productID, serialized)
+}
+
+// GetSerialization reports whether a product is tracked by serial number
+func (s *SerialService) GetSerialization(productID int) (*model.SerializationSetting, error) {
+	if productID < 1 {
+		return nil, ErrInvalidSerial
+	}
+
+	return s.repo.GetSerialization(productID)
+}
+
+// GetSerial looks up a unit by serial number, including the order and
+// customer it was sold to
+func (s *SerialService) GetSerial(serialNumber string) (*model.SerialNumber, error) {
+	if serialNumber == "" {
+		return nil, ErrInvalidSerial
+	}
+
+	serial, err := s.repo.GetBySerial(serialNumber)
+	if err == repository.ErrSerialNotFound {
+		return nil, ErrSerialNotFound
+	}
+	return serial, err
+}
+
+// ListSerials returns a product's serialized units. A warehouseID of zero
+// and an empty status match everything.
+func (s *SerialService) ListSerials(productID, warehouseID int, status model.SerialStatus) ([]model.SerialNumber, error) {
+	if productID < 1 || warehouseID < 0 {
+		return nil, ErrInvalidSerial
+	}
+	switch status {
+	case "", model.SerialInStock, model.SerialInTransit, model.SerialSold, model.SerialRemoved:
+	default:
+		return nil, ErrInvalidSerial
+	}
+
+	return s.repo.ListByProduct(productID, warehouseID, status)
+}
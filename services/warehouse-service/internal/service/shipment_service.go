@@ -0,0 +1,201 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/carrier"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+)
+
+var (
+	ErrInvalidShipment  = errors.New("invalid shipment data")
+	ErrShipmentNotFound = errors.New("shipment not found")
+	ErrShipmentRejected = errors.New("carrier rejected the shipment")
+)
+
+type ShipmentService struct {
+	shipmentRepo *repository.ShipmentRepository
+	pickListRepo *repository.PickListRepository
+	carrier      carrier.Carrier
+}
+
+func NewShipmentService(
+	shipmentRepo *repository.ShipmentRepository,
+	pickListRepo *repository.PickListRepository,
+	carrier carrier.Carrier,
+) *ShipmentService {
+	return &ShipmentService{
+		shipmentRepo: shipmentRepo,
+		pickListRepo: pickListRepo,
+		carrier:      carrier,
+	}
+}
+
+// CreateShipment books a packed pick list with the carrier and records the
+// shipment with its tracking number
+func (s *ShipmentService) CreateShipment(req *model.CreateShipmentRequest) (*model.Shipment, error) {
+	if req.PickListID < 1 || req.ServiceLevel == "" || req.Actor == "" {
+		return nil, ErrInvalidShipment
+	}
+
+	// Claim the pick list first so it cannot be shipped twice
+	pickList, err := s.pickListRepo.UpdateStatus(req.PickListID, model.PickListPacked, model.PickListShipped)
+	switch err {
+	case nil:
+	case repository.ErrPickListNotFound:
+		return nil, ErrPickListNotFound
+	case repository.ErrPickListConflict:
+		return nil, ErrPickListState
+	default:
+		return nil, err
+	}
+	revert := func() {
+		if _, err := s.pickListRepo.UpdateStatus(pickList.PickListID, model.PickListShipped, model.PickListPacked); err != nil {
+			log.Printf("Failed to return pick list %d to packed: %v", pickList.PickListID, err)
+		}
+	}
+
+	shipTo := pickList.ShipTo
+	if req.ShipTo != nil {
+		shipTo = req.ShipTo
+	}
+	if shipTo == nil {
+		revert()
+		return nil, ErrInvalidShipment
+	}
+
+	parcels := make([]carrier.Parcel, 0, len(pickList.Cartons))
+	for _, carton := range pickList.Cartons {
+		parcels = append(parcels, carrier.Parcel{
+			Length: carton.Length,
+			Width:  carton.Width,
+			Height: carton.Height,
+			Weight: carton.Weight,
+		})
+	}
+	label, err := s.carrier.CreateLabel(&carrier.LabelRequest{
+		Reference:    "PICK-" + strconv.Itoa(pickList.PickListID),
+		ServiceLevel: req.ServiceLevel,
+		ShipTo:       *shipTo,
+		Parcels:      parcels,
+	})
+	if err != nil {
+		revert()
+		if err == carrier.ErrAddressRejected || err == carrier.ErrServiceNotOffered {
+			return nil, ErrShipmentRejected
+		}
+		return nil, err
+	}
+
+	return s.shipmentRepo.Create(&model.Shipment{
+		OrderRef:       pickList.OrderRef,
+		PickListID:     pickList.PickListID,
+		WarehouseID:    pickList.WarehouseID,
+		Carrier:        s.carrier.Name(),
+		ServiceLevel:   req.ServiceLevel,
+		TrackingNumber: label.TrackingNumber,
+		Status:         model.ShipmentLabelCreated,
+		ShipTo:         *shipTo,
+		Cartons:        pickList.Cartons,
+		Events: []model.ShipmentEvent{{
+			Status:     model.ShipmentLabelCreated,
+			Detail:     "Label created",
+			OccurredAt: label.CreatedAt,
+		}},
+		CreatedBy: req.Actor,
+		CreatedAt: time.Now().UTC(),
+	})
+}
+
+// GetShipment retrieves a shipment
+func (s *ShipmentService) GetShipment(shipmentID int) (*model.Shipment, error) {
+	if shipmentID < 1 {
+		return nil, ErrInvalidShipment
+	}
+
+	shipment, err := s.shipmentRepo.GetByID(shipmentID)
+	if err == repository.ErrShipmentNotFound {
+		return nil, ErrShipmentNotFound
+	}
+	return shipment, err
+}
+
+// ListShipments returns shipments, optionally for one order or status
+func (s *ShipmentService) ListShipments(orderRef string, status model.ShipmentStatus) ([]*model.Shipment, error) {
+	switch status {
+	case "", model.ShipmentLabelCreated, model.ShipmentInTransit, model.ShipmentOutForDelivery,
+		model.ShipmentDelivered, model.ShipmentException:
+	default:
+		return nil, ErrInvalidShipment
+	}
+
+	return s.shipmentRepo.List(orderRef, status)
+}
+
+// RefreshShipment pulls the latest tracking events for a shipment from the carrier
+func (s *ShipmentService) RefreshShipment(shipmentID int) (*model.Shipment, error) {
+	shipment, err := s.GetShipment(shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.track(shipment)
+}
+
+// TrackShipments refreshes every undelivered shipment and returns how many changed status
+func (s *ShipmentService) TrackShipments() (int, error) {
+	shipments, err := s.shipmentRepo.ListUndelivered()
+	if err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, shipment := range shipments {
+		tracked, err := s.track(shipment)
+		if err != nil {
+			return changed, err
+		}
+		if tracked.Status != shipment.Status {
+			changed++
+		}
+	}
+
+	return changed, nil
+}
+
+// RunTracker refreshes undelivered shipments every interval. It never returns.
+func (s *ShipmentService) RunTracker(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if count, err := s.TrackShipments(); err != nil {
+			log.Println("Failed to track shipments:", err)
+		} else if count > 0 {
+			log.Printf("Updated status of %d shipments", count)
+		}
+	}
+}
+
+// track records the carrier's events on a shipment
+func (s *ShipmentService) track(shipment *model.Shipment) (*model.Shipment, error) {
+	events, err := s.carrier.Track(shipment.TrackingNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	shipmentEvents := make([]model.ShipmentEvent, 0, len(events))
+	for _, event := range events {
+		shipmentEvents = append(shipmentEvents, model.ShipmentEvent{
+			Status:     event.Status,
+			Detail:     event.Detail,
+			OccurredAt: event.OccurredAt,
+		})
+	}
+
+	return s.shipmentRepo.SetEvents(shipment.ShipmentID, shipmentEvents)
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+)
+
+var (
+	ErrInvalidStock      = errors.New("invalid stock data")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrLotNotFound       = errors.New("lot not found")
+	ErrLotMismatch       = errors.New("lot already exists with a different expiry date")
+	ErrSerialsRequired   = errors.New("serialized products need one unique serial number per unit")
+	ErrSerialExists      = errors.New("serial number is already in stock")
+	ErrSerialUnavailable = errors.New("serial number is not in stock at this warehouse")
+	ErrSerialShortage    = errors.New("not enough serialized units in stock to pick")
+)
+
+// Reason codes recorded on movements the service writes itself
+const (
+	ReasonPurchaseOrder        = "PURCHASE_ORDER"
+	ReasonStockCount           = "STOCK_COUNT"
+	ReasonTransfer             = "TRANSFER"
+	ReasonReservationConfirmed = "RESERVATION_CONFIRMED"
+	ReasonPickConfirmed        = "PICK_CONFIRMED"
+)
+
+// SystemActor is recorded on movements not triggered by a person
+const SystemActor = "system"
+
+type StockService struct {
+	repo             *repository.StockRepository
+	movementRepo     *repository.MovementRepository
+	serialRepo       *repository.SerialRepository
+	backorderService *BackorderService
+}
+
+func NewStockService(
+	repo *repository.StockRepository,
+	movementRepo *repository.MovementRepository,
+	serialRepo *repository.SerialRepository,
+	backorderService *BackorderService,
+) *StockService {
+	return &StockService{
+		repo:             repo,
+		movementRepo:     movementRepo,
+		serialRepo:       serialRepo,
+		backorderService: backorderService,
+	}
+}
+
+// GetStock retrieves a product's stock across all warehouses
+func (s *StockService) GetStock(productID int) (*model.ProductStock, error) {
+	if productID < 1 {
+		return nil, ErrInvalidStock
+	}
+
+	levels, err := s.repo.GetByProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	stock := &model.ProductStock{
+		ProductID:  productID,
+		Warehouses: levels,
+	}
+	for _, level := range levels {
+		stock.OnHand += level.OnHand
+		stock.Reserved += level.Reserved
+		stock.Available += level.Available
+		stock.InTransit += level.InTransit
+	}
+
+	return stock, nil
+}
+
+// AdjustStock changes a product's on-hand quantity in a warehouse
+func (s *StockService) AdjustStock(productID int, req *model.StockAdjustmentRequest) (*model.StockLevel, error) {
+	if productID < 1 || req.WarehouseID < 1 || req.Quantity == 0 || req.ReasonCode == "" || req.Actor == "" {
+		return nil, ErrInvalidStock
+	}
+
+	serialized, err := s.checkSerials(productID, req.SerialNumbers, req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if serialized {
+		if req.Quantity > 0 {
+			err = s.serialRepo.Register(productID, req.WarehouseID, req.SerialNumbers)
+		} else {
+			err = s.serialRepo.Remove(productID, req.WarehouseID, req.SerialNumbers)
+		}
+		if err != nil {
+			return nil, serialError(err)
+		}
+	}
+
+	level, err := s.repo.Adjust(productID, req.WarehouseID, req.LotNumber, req.Quantity)
+	if err != nil && serialized {
+		// Put the serial numbers back the way they were
+		if req.Quantity > 0 {
+			s.serialRepo.Remove(productID, req.WarehouseID, req.SerialNumbers)
+		} else {
+			s.serialRepo.Register(productID, req.WarehouseID, req.SerialNumbers)
+		}
+	}
+	if err == repository.ErrInsufficientStock {
+		return nil, ErrInsufficientStock
+	}
+	if err == repository.ErrLotNotFound {
+		return nil, ErrLotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.movementRepo.Append(model.StockMovement{
+		ProductID:   productID,
+		WarehouseID: req.WarehouseID,
+		Type:        model.MovementAdjustment,
+		LotNumber:   req.LotNumber,
+		Quantity:    req.Quantity,
+		ReasonCode:  req.ReasonCode,
+		Actor:       req.Actor,
+		Reference:   req.Reference,
+		CreatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if req.Quantity > 0 {
+		s.backorderService.AllocateReceived(productID)
+	}
+
+	return level, nil
+}
+
+// SetStock records a counted on-hand quantity for a product in a warehouse
+func (s *StockService) SetStock(productID, warehouseID int, req *model.SetStockRequest) (*model.StockLevel, error) {
+	if productID < 1 || warehouseID < 1 || req.OnHand < 0 || req.Actor == "" {
+		return nil, ErrInvalidStock
+	}
+
+	setting, err := s.serialRepo.GetSerialization(productID)
+	if err != nil {
+		return nil, err
+	}
+	if setting.Serialized {
+		// A bare count cannot say which units were found or lost
+		return nil, ErrSerialsRequired
+	}
+
+	level, delta, err := s.repo.Set(productID, warehouseID, req.OnHand)
+	if err == repository.ErrInsufficientStock {
+		return nil, ErrInsufficientStock
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A count that matches the books changes nothing
+	if delta != 0 {
+		_, err = s.movementRepo.Append(model.StockMovement{
+			ProductID:   productID,
+			WarehouseID: warehouseID,
+			Type:        model.MovementAdjustment,
+			Quantity:    delta,
+			ReasonCode:  ReasonStockCount,
+			Actor:       req.Actor,
+			Reference:   req.Reference,
+			CreatedAt:   time.Now().UTC(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if delta > 0 {
+		s.backorderService.AllocateReceived(productID)
+	}
+
+	return level, nil
+}
+
+// ReceiveStock adds received goods to a warehouse. A new lot must come with its expiry date.
+func (s *StockService) ReceiveStock(productID int, req *model.StockReceiptRequest) (*model.StockLevel, error) {
+	if productID < 1 || req.WarehouseID < 1 || req.Quantity < 1 || req.Actor == "" ||
+		(req.ExpiresAt != nil && req.LotNumber == "") {
+		return nil, ErrInvalidStock
+	}
+
+	serialized, err := s.checkSerials(productID, req.SerialNumbers, req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if serialized {
+		if err := s.serialRepo.Register(productID, req.WarehouseID, req.SerialNumbers); err != nil {
+			return nil, serialError(err)
+		}
+	}
+
+	level, err := s.repo.Receive(productID, req.WarehouseID, req.LotNumber, req.ExpiresAt, req.Quantity)
+	if err != nil && serialized {
+		s.serialRepo.Remove(productID, req.WarehouseID, req.SerialNumbers)
+	}
+	if err == repository.ErrLotNotFound {
+		return nil, ErrLotNotFound
+	}
+	if err == repository.ErrLotMismatch {
+		return nil, ErrLotMismatch
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reasonCode := req.ReasonCode
+	if reasonCode == "" {
+		reasonCode = ReasonPurchaseOrder
+	}
+	_, err = s.movementRepo.Append(model.StockMovement{
+		ProductID:   productID,
+		WarehouseID: req.WarehouseID,
+		Type:        model.MovementReceipt,
+		LotNumber:   req.LotNumber,
+		Quantity:    req.Quantity,
+		ReasonCode:  reasonCode,
+		Actor:       req.Actor,
+		Reference:   req.Reference,
+		CreatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.backorderService.AllocateReceived(productID)
+
+	return level, nil
+}
+
+// TransferStock immediately moves stock of a product between two
+// warehouses. Serialized products move the units named in the request.
+func (s *StockService) TransferStock(productID int, req *model.StockTransferRequest) (*model.ProductStock, error) {
+	if productID < 1 || req.FromWarehouseID < 1 || req.ToWarehouseID < 1 ||
+		req.FromWarehouseID == req.ToWarehouseID || req.Quantity < 1 || req.Actor == "" {
+		return nil, ErrInvalidStock
+	}
+
+	serialized, err := s.checkSerials(productID, req.SerialNumbers, req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if serialized {
+		err = s.serialRepo.Move(productID, req.FromWarehouseID, req.ToWarehouseID, req.SerialNumbers)
+		if err != nil {
+			return nil, serialError(err)
+		}
+	}
+
+	err = s.repo.Transfer(productID, req.FromWarehouseID, req.ToWarehouseID, req.Quantity)
+	if err != nil && serialized {
+		// Put the serial numbers back where they were
+		s.serialRepo.Move(productID, req.ToWarehouseID, req.FromWarehouseID, req.SerialNumbers)
+	}
+	if err == repository.ErrInsufficientStock {
+		return nil, ErrInsufficientStock
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	_, err = s.movementRepo.Append(
+		model.StockMovement{
+			ProductID:   productID,
+			WarehouseID: req.FromWarehouseID,
+			Type:        model.MovementTransferOut,
+			Quantity:    -req.Quantity,
+			ReasonCode:  ReasonTransfer,
+			Actor:       req.Actor,
+			Reference:   req.Reference,
+			CreatedAt:   now,
+		},
+		model.StockMovement{
+			ProductID:   productID,
+			WarehouseID: req.ToWarehouseID,
+			Type:        model.MovementTransferIn,
+			Quantity:    req.Quantity,
+			ReasonCode:  ReasonTransfer,
+			Actor:       req.Actor,
+			Reference:   req.Reference,
+			CreatedAt:   now,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetStock(productID)
+}
+
+// ListExpiringLots returns lots with stock on hand that expire within the
+// given number of days, including lots that have already expired
+func (s *StockService) ListExpiringLots(days int) ([]model.ExpiringLot, error) {
+	if days < 0 {
+		return nil, ErrInvalidStock
+	}
+
+	return s.repo.ListExpiringLots(time.Now().AddDate(0, 0, days))
+}
+
+// checkSerials reports whether a product is serialized and, if so, that
+// serialNumbers holds one unique serial number per unit of quantity
+func (s *StockService) checkSerials(productID int, serialNumbers []string, quantity int) (bool, error) {
+	setting, err := s.serialRepo.GetSerialization(productID)
+	if err != nil {
+		return false, err
+	}
+	if !setting.Serialized {
+		if len(serialNumbers) > 0 {
+			return false, ErrInvalidStock
+		}
+		return false, nil
+	}
+
+	if len(serialNumbers) != max(quantity, -quantity) {
+		return true, ErrSerialsRequired
+	}
+	seen := make(map[string]bool, len(serialNumbers))
+	for _, serialNumber := range serialNumbers {
+		if serialNumber == "" || seen[serialNumber] {
+			return true, ErrSerialsRequired
+		}
+		seen[serialNumber] = true
+	}
+	return true, nil
+}
+
+// serialError maps serial repository errors to service errors
+func serialError(err error) error {
+	switch err {
+	case repository.ErrSerialExists:
+		return ErrSerialExists
+	case repository.ErrSerialNotFound, repository.ErrSerialWrongState:
+		return ErrSerialUnavailable
+	default:
+		return err
+	}
+}
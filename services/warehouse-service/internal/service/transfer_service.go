@@ -0,0 +1,289 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+)
+
+var (
+	ErrInvalidTransfer  = errors.New("invalid transfer data")
+	ErrTransferNotFound = errors.New("transfer not found")
+	ErrTransferState    = errors.New("transfer is not in a status that allows this action")
+	ErrTransferReceipt  = errors.New("receipt does not match the quantities outstanding on the transfer")
+)
+
+// ReasonTransferShortage is recorded when a closed transfer wrote off stock never received
+const ReasonTransferShortage = "TRANSFER_SHORTAGE"
+
+type TransferService struct {
+	transferRepo     *repository.TransferRepository
+	stockRepo        *repository.StockRepository
+	movementRepo     *repository.MovementRepository
+	serialRepo       *repository.SerialRepository
+	backorderService *BackorderService
+}
+
+func NewTransferService(
+	transferRepo *repository.TransferRepository,
+	stockRepo *repository.StockRepository,
+	movementRepo *repository.MovementRepository,
+	serialRepo *repository.SerialRepository,
+	backorderService *BackorderService,
+) *TransferService {
+	return &TransferService{
+		transferRepo:     transferRepo,
+		stockRepo:        stockRepo,
+		movementRepo:     movementRepo,
+		serialRepo:       serialRepo,
+		backorderService: backorderService,
+	}
+}
+
+// CreateTransfer opens a transfer order. No stock moves until it is shipped.
+func (s *TransferService) CreateTransfer(req *model.CreateTransferRequest) (*model.TransferOrder, error) {
+	if req.FromWarehouseID < 1 || req.ToWarehouseID < 1 || req.FromWarehouseID == req.ToWarehouseID ||
+		len(req.Lines) == 0 || req.Actor == "" {
+		return nil, ErrInvalidTransfer
+	}
+	if err := validateTransferLines(req.Lines); err != nil {
+		return nil, err
+	}
+
+	lines := make([]model.TransferLine, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, model.TransferLine{ProductID: line.ProductID, Quantity: line.Quantity})
+	}
+
+	return s.transferRepo.Create(&model.TransferOrder{
+		FromWarehouseID: req.FromWarehouseID,
+		ToWarehouseID:   req.ToWarehouseID,
+		Status:          model.TransferRequested,
+		Lines:           lines,
+		Reference:       req.Reference,
+		CreatedBy:       req.Actor,
+		CreatedAt:       time.Now().UTC(),
+	})
+}
+
+// GetTransfer retrieves a transfer order
+func (s *TransferService) GetTransfer(transferID int) (*model.TransferOrder, error) {
+	if transferID < 1 {
+		return nil, ErrInvalidTransfer
+	}
+
+	transfer, err := s.transferRepo.GetByID(transferID)
+	if err == repository.ErrTransferNotFound {
+		return nil, ErrTransferNotFound
+	}
+	return transfer, err
+}
+
+// ListTransfers returns transfer orders, optionally only those in one status
+func (s *TransferService) ListTransfers(status model.TransferStatus) ([]*model.TransferOrder, error) {
+	switch status {
+	case "", model.TransferRequested, model.TransferInTransit, model.TransferPartiallyReceived,
+		model.TransferReceived, model.TransferClosed, model.TransferCancelled:
+	default:
+		return nil, ErrInvalidTransfer
+	}
+
+	return s.transferRepo.List(status)
+}
+
+// ShipTransfer takes the stock out of the source warehouse and holds it as
+// in-transit at the destination until it is received
+func (s *TransferService) ShipTransfer(transferID int, actor string) (*model.TransferOrder, error) {
+	if actor == "" {
+		return nil, ErrInvalidTransfer
+	}
+	if _, err := s.GetTransfer(transferID); err != nil {
+		return nil, err
+	}
+
+	shipped, err := s.transferRepo.UpdateStatus(transferID, model.TransferRequested, model.TransferInTransit)
+	if err == repository.ErrTransferConflict {
+		return nil, ErrTransferState
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]model.TransferLineRequest, 0, len(shipped.Lines))
+	for _, line := range shipped.Lines {
+		lines = append(lines, model.TransferLineRequest{ProductID: line.ProductID, Quantity: line.Quantity})
+	}
+	if err := s.stockRepo.Ship(shipped.FromWarehouseID, shipped.ToWarehouseID, lines); err != nil {
+		// Put the transfer back so it can be shipped once stock is available
+		if _, revertErr := s.transferRepo.UpdateStatus(transferID, model.TransferInTransit, model.TransferRequested); revertErr != nil {
+			return nil, revertErr
+		}
+		if err == repository.ErrInsufficientStock {
+			return nil, ErrInsufficientStock
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	movements := make([]model.StockMovement, 0, 2*len(shipped.Lines))
+	for _, line := range shipped.Lines {
+		err := s.serialRepo.Relocate(line.ProductID, shipped.FromWarehouseID, model.SerialInStock,
+			shipped.ToWarehouseID, model.SerialInTransit, line.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements,
+			transferMovement(shipped, line.ProductID, shipped.FromWarehouseID, model.MovementTransferOut, model.StockOnHand, -line.Quantity, actor, now),
+			transferMovement(shipped, line.ProductID, shipped.ToWarehouseID, model.MovementTransferOut, model.StockInTransit, line.Quantity, actor, now),
+		)
+	}
+	if _, err := s.movementRepo.Append(movements...); err != nil {
+		return nil, err
+	}
+
+	return shipped, nil
+}
+
+// ReceiveTransfer moves received stock from in-transit to on-hand at the
+// destination. Transfers may be received in several partial receipts.
+func (s *TransferService) ReceiveTransfer(transferID int, req *model.TransferReceiptRequest) (*model.TransferOrder, error) {
+	if len(req.Lines) == 0 || req.Actor == "" {
+		return nil, ErrInvalidTransfer
+	}
+	if err := validateTransferLines(req.Lines); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetTransfer(transferID); err != nil {
+		return nil, err
+	}
+
+	received, err := s.transferRepo.RecordReceipt(transferID, req.Lines)
+	switch err {
+	case nil:
+	case repository.ErrTransferConflict:
+		return nil, ErrTransferState
+	case repository.ErrTransferOverReceipt, repository.ErrTransferLineNotFound:
+		return nil, ErrTransferReceipt
+	default:
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	movements := make([]model.StockMovement, 0, 2*len(req.Lines))
+	for _, line := range req.Lines {
+		if err := s.stockRepo.ReceiveInTransit(line.ProductID, received.ToWarehouseID, line.Quantity, true); err != nil {
+			return nil, err
+		}
+		err := s.serialRepo.Relocate(line.ProductID, received.ToWarehouseID, model.SerialInTransit,
+			received.ToWarehouseID, model.SerialInStock, line.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements,
+			transferMovement(received, line.ProductID, received.ToWarehouseID, model.MovementTransferIn, model.StockInTransit, -line.Quantity, req.Actor, now),
+			transferMovement(received, line.ProductID, received.ToWarehouseID, model.MovementTransferIn, model.StockOnHand, line.Quantity, req.Actor, now),
+		)
+	}
+	if _, err := s.movementRepo.Append(movements...); err != nil {
+		return nil, err
+	}
+	for _, line := range req.Lines {
+		s.backorderService.AllocateReceived(line.ProductID)
+	}
+
+	return received, nil
+}
+
+// CloseTransfer finalizes a shipped transfer. Anything still in transit is
+// written off at the destination and reported as a shortage on its line.
+func (s *TransferService) CloseTransfer(transferID int, actor string) (*model.TransferOrder, error) {
+	if actor == "" {
+		return nil, ErrInvalidTransfer
+	}
+	if _, err := s.GetTransfer(transferID); err != nil {
+		return nil, err
+	}
+
+	closed, err := s.transferRepo.Close(transferID)
+	if err == repository.ErrTransferConflict {
+		return nil, ErrTransferState
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	movements := []model.StockMovement{}
+	for _, line := range closed.Lines {
+		if line.Shortage == 0 {
+			continue
+		}
+		if err := s.stockRepo.ReceiveInTransit(line.ProductID, closed.ToWarehouseID, line.Shortage, false); err != nil {
+			return nil, err
+		}
+		err := s.serialRepo.Relocate(line.ProductID, closed.ToWarehouseID, model.SerialInTransit,
+			closed.ToWarehouseID, model.SerialRemoved, line.Shortage)
+		if err != nil {
+			return nil, err
+		}
+		movement := transferMovement(closed, line.ProductID, closed.ToWarehouseID, model.MovementTransferShortage, model.StockInTransit, -line.Shortage, actor, now)
+		movement.ReasonCode = ReasonTransferShortage
+		movements = append(movements, movement)
+	}
+	if _, err := s.movementRepo.Append(movements...); err != nil {
+		return nil, err
+	}
+
+	return closed, nil
+}
+
+// CancelTransfer abandons a transfer that has not shipped
+func (s *TransferService) CancelTransfer(transferID int) (*model.TransferOrder, error) {
+	if _, err := s.GetTransfer(transferID); err != nil {
+		return nil, err
+	}
+
+	cancelled, err := s.transferRepo.UpdateStatus(transferID, model.TransferRequested, model.TransferCancelled)
+	if err == repository.ErrTransferConflict {
+		return nil, ErrTransferState
+	}
+	return cancelled, err
+}
+
+// validateTransferLines rejects non-positive quantities and repeated products
+func validateTransferLines(lines []model.TransferLineRequest) error {
+	seen := make(map[int]bool, len(lines))
+	for _, line := range lines {
+		if line.ProductID < 1 || line.Quantity < 1 || seen[line.ProductID] {
+			return ErrInvalidTransfer
+		}
+		seen[line.ProductID] = true
+	}
+	return nil
+}
+
+// transferMovement builds a ledger entry for one step of a transfer
+func transferMovement(
+	transfer *model.TransferOrder,
+	productID, warehouseID int,
+	movementType model.MovementType,
+	state model.StockState,
+	quantity int,
+	actor string,
+	at time.Time,
+) model.StockMovement {
+	return model.StockMovement{
+		ProductID:   productID,
+		WarehouseID: warehouseID,
+		Type:        movementType,
+		State:       state,
+		Quantity:    quantity,
+		ReasonCode:  ReasonTransfer,
+		Actor:       actor,
+		Reference:   fmt.Sprintf("TRANSFER-%d", transfer.TransferID),
+		CreatedAt:   at,
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/gocart-v2/shared/model"
+	"github.com/gocart-v2/warehouse-service/internal/repository"
+)
+
+var (
+	ErrWarehouseNotFound = errors.New("warehouse not found")
+	ErrWarehouseExists   = errors.New("warehouse code already in use")
+	ErrInvalidWarehouse  = errors.New("invalid warehouse data")
+)
+
+type WarehouseService struct {
+	repo *repository.WarehouseRepository
+}
+
+func NewWarehouseService(repo *repository.WarehouseRepository) *WarehouseService {
+	return &WarehouseService{repo: repo}
+}
+
+// CreateWarehouse registers a new fulfillment site
+func (s *WarehouseService) CreateWarehouse(warehouse *model.Warehouse) (*model.Warehouse, error) {
+	if warehouse.Code == "" || warehouse.Name == "" || warehouse.Address.Country == "" || warehouse.HandlingCost < 0 {
+		return nil, ErrInvalidWarehouse
+	}
+
+	created, err := s.repo.Create(warehouse)
+	if err == repository.ErrWarehouseExists {
+		return nil, ErrWarehouseExists
+	}
+	return created, err
+}
+
+// GetWarehouse retrieves a fulfillment site
+func (s *WarehouseService) GetWarehouse(warehouseID int) (*model.Warehouse, error) {
+	if warehouseID < 1 {
+		return nil, ErrInvalidWarehouse
+	}
+
+	warehouse, err := s.repo.GetByID(warehouseID)
+	if err == repository.ErrWarehouseNotFound {
+		return nil, ErrWarehouseNotFound
+	}
+	return warehouse, err
+}
+
+// ListWarehouses returns every fulfillment site
+func (s *WarehouseService) ListWarehouses() ([]model.Warehouse, error) {
+	return s.repo.List()
+}
+
+// UpdateWarehouse replaces a fulfillment site's details
+func (s *WarehouseService) UpdateWarehouse(warehouseID int, warehouse *model.Warehouse) error {
+	if warehouseID < 1 || warehouse.Code == "" || warehouse.Name == "" || warehouse.Address.Country == "" || warehouse.HandlingCost < 0 {
+		return ErrInvalidWarehouse
+	}
+
+	warehouse.WarehouseID = warehouseID
+	err := s.repo.Update(warehouse)
+	if err == repository.ErrWarehouseNotFound {
+		return ErrWarehouseNotFound
+	}
+	if err == repository.ErrWarehouseExists {
+		return ErrWarehouseExists
+	}
+	return err
+}